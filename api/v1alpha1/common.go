@@ -20,6 +20,28 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// StatusReason classifies an error reported in PowerNodeState so automation can react to it without
+// parsing the message
+// +kubebuilder:validation:Enum=Unsupported;InvalidValue;OutOfRange;WriteFailed;Busy;Conflict;Unknown
+type StatusReason string
+
+const (
+	// StatusReasonUnsupported means the node does not support the requested feature or value
+	StatusReasonUnsupported StatusReason = "Unsupported"
+	// StatusReasonInvalidValue means the requested configuration is malformed or inconsistent
+	StatusReasonInvalidValue StatusReason = "InvalidValue"
+	// StatusReasonOutOfRange means a requested value is outside the range allowed by the hardware
+	StatusReasonOutOfRange StatusReason = "OutOfRange"
+	// StatusReasonWriteFailed means applying the configuration to the hardware failed
+	StatusReasonWriteFailed StatusReason = "WriteFailed"
+	// StatusReasonBusy means the kernel rejected the configuration because the resource is in use
+	StatusReasonBusy StatusReason = "Busy"
+	// StatusReasonConflict means more than one resource selects the node
+	StatusReasonConflict StatusReason = "Conflict"
+	// StatusReasonUnknown is used for errors that are not classified
+	StatusReasonUnknown StatusReason = "Unknown"
+)
+
 type StatusErrors struct {
 	Errors []string `json:"errors,omitempty"`
 }
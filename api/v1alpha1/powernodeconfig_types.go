@@ -35,6 +35,14 @@ type PowerNodeConfigSpec struct {
 	// and kubelet reserved CPUs are managed by the shared pool.
 	// +optional
 	ReservedCPUs []ReservedSpec `json:"reservedCPUs,omitempty"`
+
+	// SharedIdleInjectionPercent requests forced idle time, in percent, on the shared pool CPUs
+	// using the intel_powerclamp idle injection driver. It reduces power even when the shared
+	// profile pins the frequency. If not specified or 0, idle injection is disabled.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SharedIdleInjectionPercent *int `json:"sharedIdleInjectionPercent,omitempty"`
 }
 
 // ReservedSpec defines a group of reserved CPUs with a PowerProfile.
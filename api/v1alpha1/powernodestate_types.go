@@ -53,8 +53,26 @@ type PowerNodeStateStatus struct {
 	// Owned by: Uncore controller
 	// +optional
 	Uncore *NodeUncoreStatus `json:"uncore,omitempty"`
+
+	// Conditions summarise whether the node level configuration was applied, the reason of a
+	// False condition is the StatusReason of the first error
+	// Owned by: PowerNodeConfig controller (CPUPoolsApplied) and Uncore controller (UncoreApplied)
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionCPUPoolsApplied reports whether the shared and reserved pools of the PowerNodeConfig were applied
+	ConditionCPUPoolsApplied = "CPUPoolsApplied"
+	// ConditionUncoreApplied reports whether the Uncore configuration was applied
+	ConditionUncoreApplied = "UncoreApplied"
+
+	// ReasonApplied is the reason of a True condition
+	ReasonApplied = "Applied"
+)
+
 // NodeInfo contains static information about the node, written once by the PowerConfig controller.
 type NodeInfo struct {
 	// CPUCapacity is the total number of CPUs on the node (from node.Status.Capacity)
@@ -75,6 +93,10 @@ type PowerNodeProfileStatus struct {
 	// Errors contains any errors encountered while applying this profile on this node
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
 }
 
 // CPUPoolsStatus contains the status of all CPU pools on this node
@@ -95,6 +117,13 @@ type CPUPoolsStatus struct {
 	// +listType=map
 	// +listMapKey=podUID
 	Exclusive []ExclusiveCPUPoolStatus `json:"exclusive,omitzero"`
+
+	// UClamp contains the utilisation clamping applied to shared pool containers
+	// Owned by: PowerPod controller (uclamp)
+	// +optional
+	// +listType=map
+	// +listMapKey=podUID
+	UClamp []UClampPodStatus `json:"uclamp,omitzero"`
 }
 
 // SharedCPUPoolStatus represents the status of the shared CPU pool
@@ -108,9 +137,17 @@ type SharedCPUPoolStatus struct {
 	// CPUIDs are the CPU IDs in this pool, pretty-printed as ranges (e.g. "2-23,46,47")
 	CPUIDs string `json:"cpuIDs"`
 
+	// IdleInjectionPercent is the forced idle percentage applied to this pool
+	// +optional
+	IdleInjectionPercent int `json:"idleInjectionPercent,omitempty"`
+
 	// Errors contains any errors encountered while configuring this pool
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
 }
 
 // ReservedCPUPoolStatus represents the status of a reserved CPU pool
@@ -133,6 +170,10 @@ type PowerProfileCPUs struct {
 	// Errors contains any errors encountered while configuring the CPUs in this pool
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
 }
 
 // ExclusiveCPUPoolStatus represents the status of exclusive CPU pools
@@ -165,6 +206,119 @@ type PowerContainer struct {
 	// Errors contains any errors encountered while configuring the container
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
+
+	// Scaling reports the dynamic frequency scaling of the container's CPUs, if its PowerProfile has a
+	// CPUScalingPolicy. It is refreshed periodically by the node agent
+	// +optional
+	Scaling *ContainerScalingStatus `json:"scaling,omitempty"`
+}
+
+// ScalingConnectionState is the state of the usage source of a scaled container
+// +kubebuilder:validation:Enum=Connected;Disconnected;Degraded
+type ScalingConnectionState string
+
+const (
+	// ScalingConnected means the usage of the container's CPUs is sampled
+	ScalingConnected ScalingConnectionState = "Connected"
+	// ScalingDisconnected means the usage source of the container is not connected, e.g. the DPDK
+	// telemetry socket was not found, and the CPUs run at the fallback frequency
+	ScalingDisconnected ScalingConnectionState = "Disconnected"
+	// ScalingDegraded means the usage source repeatedly failed to provide fresh samples and the CPUs
+	// run at the fallback frequency until it recovers
+	ScalingDegraded ScalingConnectionState = "Degraded"
+)
+
+// ContainerScalingStatus represents the dynamic frequency scaling of a container
+type ContainerScalingStatus struct {
+	// Connection is the state of the usage source of the container
+	Connection ScalingConnectionState `json:"connection"`
+
+	// LastError is the latest error reading the usage of the container's CPUs
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// CPUs contains the scaling state of each CPU of the container
+	// +optional
+	CPUs []CPUScalingStatus `json:"cpus,omitempty"`
+}
+
+// CPUScalingStatus represents the scaling state of a CPU as of its last evaluation
+type CPUScalingStatus struct {
+	// ID is the ID of the CPU
+	ID uint `json:"id"`
+
+	// TargetFrequency is the frequency last set by the scaler in kHz, unset before the first change
+	// +optional
+	TargetFrequency uint `json:"targetFrequency,omitempty"`
+
+	// CurrentFrequency is the current frequency of the CPU in kHz
+	// +optional
+	CurrentFrequency uint `json:"currentFrequency,omitempty"`
+
+	// Usage is the last usage sample of the CPU in percent, unset if the usage is unavailable
+	// +optional
+	Usage *int `json:"usage,omitempty"`
+
+	// LastChange is the time the target frequency was last changed
+	// +optional
+	LastChange *metav1.Time `json:"lastChange,omitempty"`
+
+	// GainPercent is the gain adapted to the workload in percent of the configured gain,
+	// unset if the policy does not adapt the gain
+	// +optional
+	GainPercent *int `json:"gainPercent,omitempty"`
+
+	// Idle is set while the C-states of the CPU are overridden because its usage is below the idle threshold
+	// +optional
+	Idle bool `json:"idle,omitempty"`
+}
+
+// UClampPodStatus represents the utilisation clamping applied to the shared pool containers of a pod
+type UClampPodStatus struct {
+	// PodUID is the UID of the pod (SSA map key)
+	PodUID string `json:"podUID"`
+
+	// Pod is the name of the pod
+	Pod string `json:"pod"`
+
+	// Min is the cpu.uclamp.min value applied, in percent
+	Min int `json:"min"`
+
+	// Max is the cpu.uclamp.max value applied, in percent
+	Max int `json:"max"`
+
+	// Containers contains the containers the values are applied to
+	// +optional
+	Containers []UClampContainer `json:"containers,omitempty"`
+
+	// Errors contains any errors encountered while resolving the requested values
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
+}
+
+// UClampContainer contains information about a shared pool container with utilisation clamping
+type UClampContainer struct {
+	// Name is the name of the container
+	Name string `json:"name"`
+
+	// ID is the ID of the container
+	ID string `json:"id"`
+
+	// Errors contains any errors encountered while configuring the container's cgroup
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
 }
 
 // NodeUncoreStatus represents the status of uncore frequency configuration on a node
@@ -178,6 +332,10 @@ type NodeUncoreStatus struct {
 	// Errors contains any errors encountered while configuring uncore frequency
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// Reasons classifies the errors, Reasons[i] is the reason of Errors[i]
+	// +optional
+	Reasons []StatusReason `json:"reasons,omitempty"`
 }
 
 // +kubebuilder:object:root=true
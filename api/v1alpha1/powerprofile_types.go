@@ -22,7 +22,8 @@ import (
 )
 
 // PowerProfileSpec defines the desired state of PowerProfile
-// +kubebuilder:validation:XValidation:rule="!has(self.cpuScalingPolicy) || (has(self.pstates.governor) && self.pstates.governor == 'userspace')",message="pstates.governor must be 'userspace' when cpuScalingPolicy is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.cpuScalingPolicy) || (has(self.cpuScalingPolicy.actuator) && self.cpuScalingPolicy.actuator != 'setspeed') || (has(self.pstates.governor) && self.pstates.governor == 'userspace')",message="pstates.governor must be 'userspace' when cpuScalingPolicy uses the setspeed actuator"
+// +kubebuilder:validation:XValidation:rule="!has(self.cpuScalingPolicy) || !has(self.cpuScalingPolicy.actuator) || self.cpuScalingPolicy.actuator != 'epp' || (has(self.pstates.governor) && self.pstates.governor == 'powersave' && (!has(self.pstates.epp) || size(self.pstates.epp) == 0))",message="the epp actuator of cpuScalingPolicy requires the 'powersave' governor and no pstates.epp"
 // +kubebuilder:validation:XValidation:rule="!has(oldSelf.cpuScalingPolicy) || has(self.cpuScalingPolicy)",message="cpuScalingPolicy cannot be removed once set"
 type PowerProfileSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
@@ -50,8 +51,13 @@ type PowerProfileSpec struct {
 	// +kubebuilder:default="100%"
 	CPUCapacity intstr.IntOrString `json:"cpuCapacity,omitempty"`
 
-	// Configures DPDK telemetry-based dynamic CPU frequency scaling.
+	// Configures usage-based dynamic CPU frequency scaling of exclusive CPUs.
 	CPUScalingPolicy *CPUScalingPolicy `json:"cpuScalingPolicy,omitempty"`
+
+	// Utilisation clamping applied to the cgroups of shared pool containers of pods
+	// annotated with this profile. Only has an effect with the schedutil governor.
+	// +optional
+	UClamp *UClampConfig `json:"uclamp,omitempty"`
 }
 
 type NodeSelector struct {
@@ -81,6 +87,12 @@ type PStatesConfig struct {
 	// Governor to be used
 	// +kubebuilder:default=powersave
 	Governor string `json:"governor,omitempty"`
+
+	// GovernorTunables sets sysfs tunables of the governor, keyed by attribute name (e.g. rate_limit_us for
+	// schedutil, up_threshold and sampling_rate for ondemand, freq_step for conservative). The original values
+	// are restored when CPUs leave the pool.
+	// +optional
+	GovernorTunables map[string]int `json:"governorTunables,omitempty"`
 }
 
 // CStatesConfig defines the CPU C-states configuration.
@@ -99,12 +111,16 @@ type CStatesConfig struct {
 	MaxLatencyUs *int `json:"maxLatencyUs,omitempty"`
 }
 
-// CPUScalingPolicy configures DPDK telemetry-based dynamic CPU frequency scaling.
+// CPUScalingPolicy configures usage-based dynamic CPU frequency scaling, driven by DPDK telemetry
+// or by the CPU time the kernel accounts to the CPUs.
 // +kubebuilder:validation:XValidation:rule="duration(self.samplePeriod).getMilliseconds() >= 10 && duration(self.samplePeriod).getMilliseconds() <= 1000",message="samplePeriod must be between 10ms and 1s"
 // +kubebuilder:validation:XValidation:rule="duration(self.cooldownPeriod).getMilliseconds() >= duration(self.samplePeriod).getMilliseconds()",message="cooldownPeriod must be larger than samplePeriod"
+// +kubebuilder:validation:XValidation:rule="!has(self.pid) || self.controller == 'pid'",message="pid gains require the pid controller"
 type CPUScalingPolicy struct {
-	// Workload type
-	// +kubebuilder:validation:Enum=polling-dpdk
+	// Workload type, selecting the source of the CPU usage:
+	// polling-dpdk reads the busy cycles DPDK lcores report over telemetry,
+	// cpu-utilisation reads the busy time of the CPUs from /proc/stat.
+	// +kubebuilder:validation:Enum=polling-dpdk;cpu-utilisation
 	// +kubebuilder:default=polling-dpdk
 	WorkloadType string `json:"workloadType,omitempty"`
 
@@ -144,11 +160,160 @@ type CPUScalingPolicy struct {
 	// +kubebuilder:default=50
 	ScalePercentage *int `json:"scalePercentage,omitempty"`
 
-	// Frequency to set when CPU usage is not available, in percent of max frequency
+	// Percentage factor of CPU frequency change when scaling up, scalePercentage is used if not set
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	ScaleUpPercentage *int `json:"scaleUpPercentage,omitempty"`
+
+	// Percentage factor of CPU frequency change when scaling down, scalePercentage is used if not set
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=200
+	// +optional
+	ScaleDownPercentage *int `json:"scaleDownPercentage,omitempty"`
+
+	// Maximum change of the target frequency per update, in MHz. The change is not limited if not set
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxFrequencyStep *int `json:"maxFrequencyStep,omitempty"`
+
+	// Time to elapse after lowering the frequency target before next scaling control,
+	// cooldownPeriod is used if not set
+	// +kubebuilder:validation:Format=duration
+	// +optional
+	ScaleDownCooldownPeriod *metav1.Duration `json:"scaleDownCooldownPeriod,omitempty"`
+
+	// CPU usage, in percent, at or above which the frequency is set to the maximum at once,
+	// regardless of the scaling factors and maxFrequencyStep. Disabled if not set
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BurstUsageThreshold *int `json:"burstUsageThreshold,omitempty"`
+
+	// Frequency to set when CPU usage is not available, in percent of the frequency range of the profile's P-states
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:default=0
 	FallbackFreqPercent *int `json:"fallbackFreqPercent,omitempty"`
+
+	// Controller computing the next frequency from the CPU usage:
+	// proportional scales the current frequency by the relative usage error using scalePercentage,
+	// pid steers the frequency with proportional, integral and derivative terms of the usage error.
+	// +kubebuilder:validation:Enum=proportional;pid
+	// +kubebuilder:default=proportional
+	Controller string `json:"controller,omitempty"`
+
+	// Gains of the pid controller, defaults are used if not set
+	// +optional
+	PID *PIDGains `json:"pid,omitempty"`
+
+	// Adapts the gain of the controller to the workload, lowering it when the frequency oscillates and raising it
+	// when the frequency follows the load slowly. Disabled if not set
+	// +optional
+	AdaptiveGain *AdaptiveGain `json:"adaptiveGain,omitempty"`
+
+	// Overrides the C-states of the CPUs while their usage is low, and restores the C-states of the profile
+	// when the load returns. Disabled if not set
+	// +optional
+	IdleCStates *IdleCStates `json:"idleCStates,omitempty"`
+
+	// Actuator applying the frequency targets to the CPUs:
+	// setspeed sets the frequency with the userspace governor,
+	// max-freq caps scaling_max_freq under any other governor, e.g. performance or powersave,
+	// epp steers energy_performance_preference under the powersave governor, e.g. with intel_pstate
+	// in active mode or amd-pstate-epp.
+	// +kubebuilder:validation:Enum=setspeed;max-freq;epp
+	// +kubebuilder:default=setspeed
+	Actuator string `json:"actuator,omitempty"`
+}
+
+// PIDGains configures the pid controller of dynamic CPU frequency scaling. The gains are applied to the
+// usage error at each evaluation, a gain of 100 moves the frequency across the whole frequency range of
+// the CPU for a usage error of 100 percent points.
+type PIDGains struct {
+	// Proportional gain, in percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=50
+	ProportionalGain *int `json:"proportionalGain,omitempty"`
+
+	// Integral gain, in percent per evaluation
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=10
+	IntegralGain *int `json:"integralGain,omitempty"`
+
+	// Derivative gain, in percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=0
+	DerivativeGain *int `json:"derivativeGain,omitempty"`
+}
+
+// AdaptiveGain configures the adaptation of the gain of the scaling controller, scalePercentage or the gains of the
+// pid controller. The gain is halved when the direction of the frequency changes reverses maxReversals times within
+// the window, and raised by a quarter when the frequency keeps moving in the same direction, within
+// minGainPercent and maxGainPercent of the configured gain.
+type AdaptiveGain struct {
+	// Time window in which reversals of the direction of the frequency changes are counted
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:default="1s"
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Reversals within the window after which the gain is lowered
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:default=3
+	MaxReversals *int `json:"maxReversals,omitempty"`
+
+	// Lowest adapted gain, in percent of the configured gain
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=25
+	MinGainPercent *int `json:"minGainPercent,omitempty"`
+
+	// Highest adapted gain, in percent of the configured gain
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=200
+	MaxGainPercent *int `json:"maxGainPercent,omitempty"`
+}
+
+// IdleCStates configures the C-states of scaled CPUs while they are idle. A CPU is idle when its usage stays below
+// usageThreshold for idlePeriod, and loaded again as soon as a usage sample reaches the threshold or is unavailable.
+// Polling workloads only enter the C-states if they sleep at low load, e.g. DPDK applications using the power
+// management of their poll mode drivers or the interrupt mode of their queues.
+type IdleCStates struct {
+	// CPU usage, in percent, below which a CPU is idle
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	UsageThreshold *int `json:"usageThreshold,omitempty"`
+
+	// Time the usage must stay below usageThreshold before the C-states are overridden
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:default="1s"
+	IdlePeriod *metav1.Duration `json:"idlePeriod,omitempty"`
+
+	// C-states enabled (true) or disabled (false) while the CPU is idle, keyed by name (e.g. "C1E", "C6").
+	// C-states which are not named keep the configuration of the profile
+	// +kubebuilder:validation:MinProperties=1
+	Names map[string]bool `json:"names"`
+}
+
+// UClampConfig defines the cgroup v2 cpu.uclamp.min and cpu.uclamp.max values of a container.
+// +kubebuilder:validation:XValidation:rule="!has(self.min) || !has(self.max) || self.min <= self.max",message="uclamp min must not be greater than max"
+type UClampConfig struct {
+	// Minimum utilisation the scheduler assumes for the container's tasks, in percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Min *int `json:"min,omitempty"`
+
+	// Maximum utilisation the scheduler assumes for the container's tasks, in percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Max *int `json:"max,omitempty"`
 }
 
 // PowerProfileStatus defines the observed state of PowerProfile
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -96,7 +97,7 @@ func (v *powerProfileValidator) ValidateCreate(ctx context.Context, obj runtime.
 			schema.GroupKind{Group: GroupVersion.Group, Kind: "PowerProfile"},
 			profile.Name, field.ErrorList{err})
 	}
-	return nil, nil
+	return nil, validateSpec(profile)
 }
 
 // ValidateUpdate implements admission.CustomValidator.
@@ -119,7 +120,7 @@ func (v *powerProfileValidator) ValidateUpdate(ctx context.Context, oldObj, newO
 				fmt.Errorf("cannot change spec.shared from true to false, %s", err.Error()))
 		}
 	}
-	return nil, nil
+	return nil, validateSpec(newProfile)
 }
 
 // ValidateDelete implements admission.CustomValidator.
@@ -146,6 +147,93 @@ func (v *powerProfileValidator) ValidateDelete(ctx context.Context, obj runtime.
 	return nil, nil
 }
 
+// validateSpec checks the constraints between fields of the spec which the CRD schema does not cover.
+func validateSpec(profile *PowerProfile) error {
+	if profile.Spec.CPUScalingPolicy == nil {
+		return nil
+	}
+	allErrs := validateCPUScalingPolicy(profile.Spec.CPUScalingPolicy, field.NewPath("spec", "cpuScalingPolicy"))
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "PowerProfile"},
+			profile.Name, allErrs)
+	}
+	return nil
+}
+
+// validateCPUScalingPolicy checks that the settings of a policy which depend on each other can take effect.
+// Defaults are already set by the API server, so fields with defaults are only nil in tests.
+func validateCPUScalingPolicy(policy *CPUScalingPolicy, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if policy.Controller == "pid" {
+		if policy.ScaleUpPercentage != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("scaleUpPercentage"),
+				"scaleUpPercentage requires the proportional controller"))
+		}
+		if policy.ScaleDownPercentage != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("scaleDownPercentage"),
+				"scaleDownPercentage requires the proportional controller"))
+		}
+	}
+
+	// changes smaller than allowedFrequencyDifference are never applied
+	if policy.MaxFrequencyStep != nil && policy.AllowedFrequencyDifference != nil &&
+		*policy.MaxFrequencyStep <= *policy.AllowedFrequencyDifference {
+		errs = append(errs, field.Invalid(fldPath.Child("maxFrequencyStep"), *policy.MaxFrequencyStep,
+			fmt.Sprintf("maxFrequencyStep (%d) must be larger than allowedFrequencyDifference (%d)",
+				*policy.MaxFrequencyStep, *policy.AllowedFrequencyDifference)))
+	}
+
+	if policy.ScaleDownCooldownPeriod != nil && policy.SamplePeriod != nil &&
+		policy.ScaleDownCooldownPeriod.Duration < policy.SamplePeriod.Duration {
+		errs = append(errs, field.Invalid(fldPath.Child("scaleDownCooldownPeriod"), policy.ScaleDownCooldownPeriod.Duration.String(),
+			fmt.Sprintf("scaleDownCooldownPeriod must not be shorter than samplePeriod (%s)", policy.SamplePeriod.Duration)))
+	}
+
+	// a threshold within the allowed usage difference would burst while the usage is on target
+	if policy.BurstUsageThreshold != nil && policy.TargetUsage != nil {
+		upperUsage := *policy.TargetUsage
+		if policy.AllowedUsageDifference != nil {
+			upperUsage += *policy.AllowedUsageDifference
+		}
+		if *policy.BurstUsageThreshold <= upperUsage {
+			errs = append(errs, field.Invalid(fldPath.Child("burstUsageThreshold"), *policy.BurstUsageThreshold,
+				fmt.Sprintf("burstUsageThreshold (%d) must be above targetUsage + allowedUsageDifference (%d)",
+					*policy.BurstUsageThreshold, upperUsage)))
+		}
+	}
+
+	// the frequency changes at most once per cooldown, so a shorter window cannot see maxReversals reversals
+	if gain := policy.AdaptiveGain; gain != nil && gain.Window != nil && gain.MaxReversals != nil && policy.CooldownPeriod != nil {
+		minWindow := policy.CooldownPeriod.Duration * time.Duration(*gain.MaxReversals)
+		if gain.Window.Duration < minWindow {
+			errs = append(errs, field.Invalid(fldPath.Child("adaptiveGain", "window"), gain.Window.Duration.String(),
+				fmt.Sprintf("window must be at least maxReversals times cooldownPeriod (%s)", minWindow)))
+		}
+	}
+
+	if idle := policy.IdleCStates; idle != nil {
+		// a threshold within the allowed usage difference would put CPUs to sleep while the usage is on target
+		if idle.UsageThreshold != nil && policy.TargetUsage != nil {
+			lowerUsage := *policy.TargetUsage
+			if policy.AllowedUsageDifference != nil {
+				lowerUsage -= *policy.AllowedUsageDifference
+			}
+			if *idle.UsageThreshold >= lowerUsage {
+				errs = append(errs, field.Invalid(fldPath.Child("idleCStates", "usageThreshold"), *idle.UsageThreshold,
+					fmt.Sprintf("usageThreshold (%d) must be below targetUsage - allowedUsageDifference (%d)",
+						*idle.UsageThreshold, lowerUsage)))
+			}
+		}
+		if idle.IdlePeriod != nil && policy.SamplePeriod != nil && idle.IdlePeriod.Duration < policy.SamplePeriod.Duration {
+			errs = append(errs, field.Invalid(fldPath.Child("idleCStates", "idlePeriod"), idle.IdlePeriod.Duration.String(),
+				fmt.Sprintf("idlePeriod must not be shorter than samplePeriod (%s)", policy.SamplePeriod.Duration)))
+		}
+	}
+	return errs
+}
+
 // validatePowerNodeConfigReferences returns an error if any PowerNodeConfig references the profile.
 // When sharedOnly is true, only sharedPowerProfile references are checked.
 func (v *powerProfileValidator) validatePowerNodeConfigReferences(ctx context.Context, profile *PowerProfile, sharedOnly bool) error {
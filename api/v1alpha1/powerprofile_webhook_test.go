@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -224,3 +225,131 @@ func TestPowerProfileValidatePodReferences(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(v int) *int { return &v }
+
+func TestPowerProfileValidateCPUScalingPolicy(t *testing.T) {
+	// the defaults the API server sets
+	newPolicy := func(modify func(policy *CPUScalingPolicy)) *CPUScalingPolicy {
+		policy := &CPUScalingPolicy{
+			SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+			CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+			TargetUsage:                intPtr(80),
+			AllowedUsageDifference:     intPtr(5),
+			AllowedFrequencyDifference: intPtr(25),
+			ScalePercentage:            intPtr(50),
+			Controller:                 "proportional",
+		}
+		modify(policy)
+		return policy
+	}
+	tests := []struct {
+		name   string
+		policy *CPUScalingPolicy
+		errMsg string
+	}{
+		{
+			name:   "defaults",
+			policy: newPolicy(func(*CPUScalingPolicy) {}),
+		},
+		{
+			name: "fast scale-up and slow scale-down",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.ScaleUpPercentage = intPtr(150)
+				policy.ScaleDownPercentage = intPtr(20)
+				policy.MaxFrequencyStep = intPtr(200)
+				policy.ScaleDownCooldownPeriod = &metav1.Duration{Duration: 100 * time.Millisecond}
+				policy.BurstUsageThreshold = intPtr(95)
+			}),
+		},
+		{
+			name: "scale percentages with the pid controller",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.Controller = "pid"
+				policy.ScaleDownPercentage = intPtr(20)
+			}),
+			errMsg: "scaleDownPercentage requires the proportional controller",
+		},
+		{
+			name: "step within the allowed frequency difference",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.MaxFrequencyStep = intPtr(25)
+			}),
+			errMsg: "maxFrequencyStep (25) must be larger than allowedFrequencyDifference (25)",
+		},
+		{
+			name: "scale-down cooldown shorter than the sample period",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.ScaleDownCooldownPeriod = &metav1.Duration{Duration: 5 * time.Millisecond}
+			}),
+			errMsg: "scaleDownCooldownPeriod must not be shorter than samplePeriod (10ms)",
+		},
+		{
+			name: "burst threshold within the allowed usage difference",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.BurstUsageThreshold = intPtr(85)
+			}),
+			errMsg: "burstUsageThreshold (85) must be above targetUsage + allowedUsageDifference (85)",
+		},
+		{
+			name: "adaptive gain",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.AdaptiveGain = &AdaptiveGain{Window: &metav1.Duration{Duration: time.Second}, MaxReversals: intPtr(3)}
+			}),
+		},
+		{
+			name: "adaptive gain window too short for the reversals",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.AdaptiveGain = &AdaptiveGain{Window: &metav1.Duration{Duration: 60 * time.Millisecond}, MaxReversals: intPtr(3)}
+			}),
+			errMsg: "window must be at least maxReversals times cooldownPeriod (90ms)",
+		},
+		{
+			name: "idle c-states",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.IdleCStates = &IdleCStates{
+					UsageThreshold: intPtr(10),
+					IdlePeriod:     &metav1.Duration{Duration: time.Second},
+					Names:          map[string]bool{"C6": true},
+				}
+			}),
+		},
+		{
+			name: "idle threshold within the allowed usage difference",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.IdleCStates = &IdleCStates{UsageThreshold: intPtr(75), Names: map[string]bool{"C6": true}}
+			}),
+			errMsg: "usageThreshold (75) must be below targetUsage - allowedUsageDifference (75)",
+		},
+		{
+			name: "idle period shorter than the sample period",
+			policy: newPolicy(func(policy *CPUScalingPolicy) {
+				policy.IdleCStates = &IdleCStates{
+					IdlePeriod: &metav1.Duration{Duration: 5 * time.Millisecond},
+					Names:      map[string]bool{"C6": true},
+				}
+			}),
+			errMsg: "idlePeriod must not be shorter than samplePeriod (10ms)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := testPowerProfile("scaling-prof", false)
+			profile.Spec.CPUScalingPolicy = tc.policy
+			v := &powerProfileValidator{Client: newFakeClient(), Namespace: testNamespace}
+
+			_, createErr := v.ValidateCreate(context.TODO(), profile)
+			_, updateErr := v.ValidateUpdate(context.TODO(), testPowerProfile("scaling-prof", false), profile)
+			if tc.errMsg == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			require.Error(t, createErr)
+			assert.Contains(t, createErr.Error(), tc.errMsg)
+			require.Error(t, updateErr)
+			assert.Contains(t, updateErr.Error(), tc.errMsg)
+		})
+	}
+}
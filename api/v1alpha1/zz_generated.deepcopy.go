@@ -26,6 +26,41 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptiveGain) DeepCopyInto(out *AdaptiveGain) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxReversals != nil {
+		in, out := &in.MaxReversals, &out.MaxReversals
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinGainPercent != nil {
+		in, out := &in.MinGainPercent, &out.MinGainPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxGainPercent != nil {
+		in, out := &in.MaxGainPercent, &out.MaxGainPercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveGain.
+func (in *AdaptiveGain) DeepCopy() *AdaptiveGain {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptiveGain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUPoolsStatus) DeepCopyInto(out *CPUPoolsStatus) {
 	*out = *in
@@ -48,6 +83,13 @@ func (in *CPUPoolsStatus) DeepCopyInto(out *CPUPoolsStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UClamp != nil {
+		in, out := &in.UClamp, &out.UClamp
+		*out = make([]UClampPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPoolsStatus.
@@ -93,11 +135,51 @@ func (in *CPUScalingPolicy) DeepCopyInto(out *CPUScalingPolicy) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ScaleUpPercentage != nil {
+		in, out := &in.ScaleUpPercentage, &out.ScaleUpPercentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleDownPercentage != nil {
+		in, out := &in.ScaleDownPercentage, &out.ScaleDownPercentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxFrequencyStep != nil {
+		in, out := &in.MaxFrequencyStep, &out.MaxFrequencyStep
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleDownCooldownPeriod != nil {
+		in, out := &in.ScaleDownCooldownPeriod, &out.ScaleDownCooldownPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BurstUsageThreshold != nil {
+		in, out := &in.BurstUsageThreshold, &out.BurstUsageThreshold
+		*out = new(int)
+		**out = **in
+	}
 	if in.FallbackFreqPercent != nil {
 		in, out := &in.FallbackFreqPercent, &out.FallbackFreqPercent
 		*out = new(int)
 		**out = **in
 	}
+	if in.PID != nil {
+		in, out := &in.PID, &out.PID
+		*out = new(PIDGains)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdaptiveGain != nil {
+		in, out := &in.AdaptiveGain, &out.AdaptiveGain
+		*out = new(AdaptiveGain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleCStates != nil {
+		in, out := &in.IdleCStates, &out.IdleCStates
+		*out = new(IdleCStates)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUScalingPolicy.
@@ -110,6 +192,35 @@ func (in *CPUScalingPolicy) DeepCopy() *CPUScalingPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUScalingStatus) DeepCopyInto(out *CPUScalingStatus) {
+	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(int)
+		**out = **in
+	}
+	if in.LastChange != nil {
+		in, out := &in.LastChange, &out.LastChange
+		*out = (*in).DeepCopy()
+	}
+	if in.GainPercent != nil {
+		in, out := &in.GainPercent, &out.GainPercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUScalingStatus.
+func (in *CPUScalingStatus) DeepCopy() *CPUScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CStatesConfig) DeepCopyInto(out *CStatesConfig) {
 	*out = *in
@@ -157,6 +268,28 @@ func (in *Container) DeepCopy() *Container {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerScalingStatus) DeepCopyInto(out *ContainerScalingStatus) {
+	*out = *in
+	if in.CPUs != nil {
+		in, out := &in.CPUs, &out.CPUs
+		*out = make([]CPUScalingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerScalingStatus.
+func (in *ContainerScalingStatus) DeepCopy() *ContainerScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DieSelector) DeepCopyInto(out *DieSelector) {
 	*out = *in
@@ -236,6 +369,38 @@ func (in *GuaranteedPod) DeepCopy() *GuaranteedPod {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleCStates) DeepCopyInto(out *IdleCStates) {
+	*out = *in
+	if in.UsageThreshold != nil {
+		in, out := &in.UsageThreshold, &out.UsageThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.IdlePeriod != nil {
+		in, out := &in.IdlePeriod, &out.IdlePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleCStates.
+func (in *IdleCStates) DeepCopy() *IdleCStates {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleCStates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeInfo) DeepCopyInto(out *NodeInfo) {
 	*out = *in
@@ -275,6 +440,11 @@ func (in *NodeUncoreStatus) DeepCopyInto(out *NodeUncoreStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUncoreStatus.
@@ -287,6 +457,36 @@ func (in *NodeUncoreStatus) DeepCopy() *NodeUncoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PIDGains) DeepCopyInto(out *PIDGains) {
+	*out = *in
+	if in.ProportionalGain != nil {
+		in, out := &in.ProportionalGain, &out.ProportionalGain
+		*out = new(int)
+		**out = **in
+	}
+	if in.IntegralGain != nil {
+		in, out := &in.IntegralGain, &out.IntegralGain
+		*out = new(int)
+		**out = **in
+	}
+	if in.DerivativeGain != nil {
+		in, out := &in.DerivativeGain, &out.DerivativeGain
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PIDGains.
+func (in *PIDGains) DeepCopy() *PIDGains {
+	if in == nil {
+		return nil
+	}
+	out := new(PIDGains)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PStatesConfig) DeepCopyInto(out *PStatesConfig) {
 	*out = *in
@@ -300,6 +500,13 @@ func (in *PStatesConfig) DeepCopyInto(out *PStatesConfig) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.GovernorTunables != nil {
+		in, out := &in.GovernorTunables, &out.GovernorTunables
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PStatesConfig.
@@ -427,6 +634,16 @@ func (in *PowerContainer) DeepCopyInto(out *PowerContainer) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(ContainerScalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerContainer.
@@ -509,6 +726,11 @@ func (in *PowerNodeConfigSpec) DeepCopyInto(out *PowerNodeConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SharedIdleInjectionPercent != nil {
+		in, out := &in.SharedIdleInjectionPercent, &out.SharedIdleInjectionPercent
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerNodeConfigSpec.
@@ -544,6 +766,11 @@ func (in *PowerNodeProfileStatus) DeepCopyInto(out *PowerNodeProfileStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerNodeProfileStatus.
@@ -655,6 +882,13 @@ func (in *PowerNodeStateStatus) DeepCopyInto(out *PowerNodeStateStatus) {
 		*out = new(NodeUncoreStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerNodeStateStatus.
@@ -702,6 +936,11 @@ func (in *PowerProfileCPUs) DeepCopyInto(out *PowerProfileCPUs) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerProfileCPUs.
@@ -758,6 +997,11 @@ func (in *PowerProfileSpec) DeepCopyInto(out *PowerProfileSpec) {
 		*out = new(CPUScalingPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UClamp != nil {
+		in, out := &in.UClamp, &out.UClamp
+		*out = new(UClampConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerProfileSpec.
@@ -835,6 +1079,11 @@ func (in *SharedCPUPoolStatus) DeepCopyInto(out *SharedCPUPoolStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedCPUPoolStatus.
@@ -867,6 +1116,88 @@ func (in *StatusErrors) DeepCopy() *StatusErrors {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UClampConfig) DeepCopyInto(out *UClampConfig) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UClampConfig.
+func (in *UClampConfig) DeepCopy() *UClampConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UClampConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UClampContainer) DeepCopyInto(out *UClampContainer) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UClampContainer.
+func (in *UClampContainer) DeepCopy() *UClampContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(UClampContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UClampPodStatus) DeepCopyInto(out *UClampPodStatus) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]UClampContainer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UClampPodStatus.
+func (in *UClampPodStatus) DeepCopy() *UClampPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UClampPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Uncore) DeepCopyInto(out *Uncore) {
 	*out = *in
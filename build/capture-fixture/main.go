@@ -0,0 +1,51 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// capture-fixture records the sysfs and procfs files read by the power library into a tarball, which can be
+// loaded with power.LibConfig.FixturePath to reproduce the library's behaviour on another machine
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+)
+
+func main() {
+	var output string
+	flag.StringVar(&output, "output", "fixture.tar.gz", "Path of the fixture tarball to write.")
+	flag.Parse()
+
+	if err := capture(output); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to capture fixture: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("fixture written to", output)
+}
+
+func capture(output string) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := power.CaptureFixture(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
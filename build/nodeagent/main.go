@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +33,8 @@ import (
 
 	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
 	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+	"github.com/cluster-power-manager/cluster-power-manager/pkg/cgroup"
+	"github.com/cluster-power-manager/cluster-power-manager/pkg/debug"
 	"github.com/cluster-power-manager/cluster-power-manager/pkg/podresourcesclient"
 
 	"github.com/cluster-power-manager/cluster-power-manager/controllers"
@@ -54,7 +57,16 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var telemetryMaxAge time.Duration
+	var scalingStatusInterval time.Duration
+	var usageRecordPath string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":10001", "The address the metric endpoint binds to.")
+	flag.DurationVar(&telemetryMaxAge, "dpdk-telemetry-max-age", scaling.DefaultTelemetryMaxAge,
+		"The age after which a DPDK usage sample is stale and the CPU is set to the fallback frequency.")
+	flag.DurationVar(&scalingStatusInterval, "scaling-status-interval", controllers.DefaultScalingStatusInterval,
+		"The interval at which the dynamic scaling status of containers is refreshed in the PowerNodeState, 0 disables it.")
+	flag.StringVar(&usageRecordPath, "record-usage", "",
+		"Path of a file to append the usage samples of scaled CPUs to, as a trace for the scaling-replay tool.")
 	logOpts := zap.Options{}
 	logOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -101,6 +113,11 @@ func main() {
 		}
 	}
 
+	if err = mgr.AddMetricsServerExtraHandler(debug.HostStatePath, debug.NewHostStateHandler(powerLibrary)); err != nil {
+		setupLog.Error(err, "unable to register debug handler", "path", debug.HostStatePath)
+		os.Exit(1)
+	}
+
 	powerNodeState, err := podstate.NewState()
 	if err != nil {
 		setupLog.Error(err, "unable to create internal state")
@@ -115,10 +132,29 @@ func main() {
 
 	dpdkClient := scaling.NewDPDKTelemetryClient(
 		ctrl.Log.WithName("clients").WithName("DPDKClient"),
+		telemetryMaxAge,
 	)
 	defer dpdkClient.Close()
 
-	cpuScalingMgr := scaling.NewCPUScalingManager(&powerLibrary, dpdkClient)
+	cpuUsageClient := scaling.NewCPUUsageClient(
+		ctrl.Log.WithName("clients").WithName("CPUUsageClient"),
+	)
+	defer cpuUsageClient.Close()
+
+	var usageRecorder *scaling.UsageRecorder
+	if usageRecordPath != "" {
+		usageRecordFile, err := os.OpenFile(usageRecordPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			setupLog.Error(err, "unable to open usage record file", "path", usageRecordPath)
+			//nolint:gocritic // exitAfterDefer: no DPDK connections exist during setup, see the registration of the CPUScalingManager.
+			os.Exit(1)
+		}
+		defer usageRecordFile.Close()
+		setupLog.Info("recording usage samples of scaled CPUs", "path", usageRecordPath)
+		usageRecorder = scaling.NewUsageRecorder(usageRecordFile)
+	}
+
+	cpuScalingMgr := scaling.NewCPUScalingManager(&powerLibrary, usageRecorder)
 	if err = mgr.Add(cpuScalingMgr); err != nil {
 		setupLog.Error(err, "unable to register runnable", "runnable", "CPUScalingManager")
 		//nolint:gocritic // exitAfterDefer: os.Exit calls before mgr.Start() here are during setup/registration, no DPDK connections exist yet,
@@ -145,14 +181,17 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.PowerPodReconciler{
-		Client:              mgr.GetClient(),
-		Log:                 ctrl.Log.WithName("controllers").WithName("PowerPod"),
-		Scheme:              mgr.GetScheme(),
-		State:               powerNodeState,
-		PodResourcesClient:  *podResourcesClient,
-		PowerLibrary:        powerLibrary,
-		DPDKTelemetryClient: dpdkClient,
-		CPUScalingManager:   cpuScalingMgr,
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("PowerPod"),
+		Scheme:                mgr.GetScheme(),
+		State:                 powerNodeState,
+		PodResourcesClient:    *podResourcesClient,
+		PowerLibrary:          powerLibrary,
+		DPDKTelemetryClient:   dpdkClient,
+		CPUUsageClient:        cpuUsageClient,
+		CPUScalingManager:     cpuScalingMgr,
+		UClampWriter:          cgroup.NewUClampWriter(),
+		ScalingStatusInterval: scalingStatusInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PowerPod")
 		os.Exit(1)
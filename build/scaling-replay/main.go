@@ -0,0 +1,167 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// scaling-replay replays a usage trace recorded by the node agent with --record-usage against a CPUScalingPolicy
+// and a simulated CPU, and reports the frequency decisions the scaler takes, to tune a policy offline
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
+	"github.com/cluster-power-manager/cluster-power-manager/controllers"
+	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+)
+
+func main() {
+	var tracePath, policyPath, decisionsPath string
+	var minFreq, maxFreq, freqStep, initialFreq uint
+	flag.StringVar(&tracePath, "trace", "", "Path of the usage trace to replay.")
+	flag.StringVar(&policyPath, "policy", "",
+		"Path of a YAML file with the cpuScalingPolicy of a PowerProfile, defaults are used for unset fields.")
+	flag.StringVar(&decisionsPath, "decisions", "", "Path of a file to write the frequency decisions to as JSONL.")
+	flag.UintVar(&minFreq, "min-freq", 800, "Minimum frequency of the simulated CPU, in MHz.")
+	flag.UintVar(&maxFreq, "max-freq", 3000, "Maximum frequency of the simulated CPU, in MHz.")
+	flag.UintVar(&freqStep, "freq-step", 100, "Granularity of the frequencies the simulated CPU accepts, in MHz.")
+	flag.UintVar(&initialFreq, "initial-freq", 0, "Frequency of the simulated CPU before the first update, in MHz, max-freq if 0.")
+	flag.Parse()
+
+	if tracePath == "" {
+		fmt.Fprintln(os.Stderr, "--trace is required")
+		os.Exit(2)
+	}
+	model := scaling.CPUModel{
+		MinFrequency:     minFreq * 1000,
+		MaxFrequency:     maxFreq * 1000,
+		FrequencyStep:    freqStep * 1000,
+		InitialFrequency: initialFreq * 1000,
+	}
+	if err := replay(tracePath, policyPath, decisionsPath, model); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to replay trace: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func replay(tracePath, policyPath, decisionsPath string, model scaling.CPUModel) error {
+	policy, err := readPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	traceFile, err := os.Open(tracePath)
+	if err != nil {
+		return err
+	}
+	defer traceFile.Close()
+	trace, err := scaling.ReadUsageTrace(traceFile)
+	if err != nil {
+		return err
+	}
+	if len(trace) == 0 {
+		return fmt.Errorf("trace %s has no samples", tracePath)
+	}
+
+	results := scaling.Replay(trace, model, func(cpu power.Cpu, usageSource scaling.UsageSource) scaling.CPUScalingOpts {
+		return controllers.NewCPUScalingOpts(policy, cpu, usageSource)
+	})
+	if decisionsPath != "" {
+		if err := writeDecisions(decisionsPath, results); err != nil {
+			return err
+		}
+	}
+	return printSummary(results)
+}
+
+// readPolicy reads a CPUScalingPolicy and sets the defaults the API server would set for unset fields
+func readPolicy(path string) (*powerv1alpha1.CPUScalingPolicy, error) {
+	policy := &powerv1alpha1.CPUScalingPolicy{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, policy); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+		}
+	}
+	if policy.SamplePeriod == nil {
+		policy.SamplePeriod = &metav1.Duration{Duration: 10 * time.Millisecond}
+	}
+	if policy.CooldownPeriod == nil {
+		policy.CooldownPeriod = &metav1.Duration{Duration: 30 * time.Millisecond}
+	}
+	if policy.TargetUsage == nil {
+		policy.TargetUsage = intPtr(80)
+	}
+	if policy.AllowedUsageDifference == nil {
+		policy.AllowedUsageDifference = intPtr(5)
+	}
+	if policy.AllowedFrequencyDifference == nil {
+		policy.AllowedFrequencyDifference = intPtr(25)
+	}
+	if policy.ScalePercentage == nil {
+		policy.ScalePercentage = intPtr(50)
+	}
+	if policy.FallbackFreqPercent == nil {
+		policy.FallbackFreqPercent = intPtr(0)
+	}
+	return policy, nil
+}
+
+func writeDecisions(path string, results []scaling.ReplayResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	for _, result := range results {
+		for _, decision := range result.Decisions {
+			if err := encoder.Encode(decision); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+	return f.Close()
+}
+
+func printSummary(results []scaling.ReplayResult) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CPU\tDURATION\tABOVE TARGET\tWRITES\tOSCILLATIONS\tAVG FREQ (MHz)\tGAIN")
+	for _, result := range results {
+		aboveTarget := 0.0
+		if result.Duration > 0 {
+			aboveTarget = 100 * result.TimeAboveTarget.Seconds() / result.Duration.Seconds()
+		}
+		gain := "-"
+		if result.GainPercent > 0 {
+			gain = fmt.Sprintf("%d%%", result.GainPercent)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s (%.1f%%)\t%d\t%d\t%d\t%s\n", result.CPU, result.Duration, result.TimeAboveTarget,
+			aboveTarget, result.Writes, result.Oscillations, result.AverageFrequency/1000, gain)
+	}
+	return w.Flush()
+}
+
+func intPtr(v int) *int { return &v }
@@ -17,6 +17,7 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	e "errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -24,11 +25,11 @@ import (
 	"time"
 
 	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
-	"github.com/cluster-power-manager/cluster-power-manager/pkg/util"
 	"github.com/go-logr/logr"
 	"github.com/intel/power-optimization-library/pkg/power"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -49,6 +50,92 @@ func powerProfileFieldManager(profileName string) string {
 	return fmt.Sprintf("%s.%s", FieldOwnerPowerProfileController, profileName)
 }
 
+// errConflict classifies the status errors reported when more than one resource selects a node.
+var errConflict = e.New("conflict")
+
+// newStatusError creates an error classified as kind, where kind is errConflict or one of the power library's
+// sentinel errors, without changing the message reported in PowerNodeState.
+func newStatusError(kind error, format string, args ...any) error {
+	return &power.Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// statusReason maps an error to the reason reported next to it in PowerNodeState.
+func statusReason(err error) powerv1alpha1.StatusReason {
+	switch {
+	case e.Is(err, errConflict):
+		return powerv1alpha1.StatusReasonConflict
+	case e.Is(err, power.ErrUnsupported):
+		return powerv1alpha1.StatusReasonUnsupported
+	case e.Is(err, power.ErrInvalidValue):
+		return powerv1alpha1.StatusReasonInvalidValue
+	case e.Is(err, power.ErrOutOfRange):
+		return powerv1alpha1.StatusReasonOutOfRange
+	case e.Is(err, power.ErrBusy):
+		return powerv1alpha1.StatusReasonBusy
+	case e.Is(err, power.ErrWriteFailed):
+		return powerv1alpha1.StatusReasonWriteFailed
+	default:
+		return powerv1alpha1.StatusReasonUnknown
+	}
+}
+
+// statusErrors converts errors to the parallel Errors and Reasons lists of a PowerNodeState status entry.
+// Joined errors are unpacked so that every error gets its own entry.
+func statusErrors(errs ...error) ([]string, []powerv1alpha1.StatusReason) {
+	var messages []string
+	var reasons []powerv1alpha1.StatusReason
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var joinedErr interface{ Unwrap() []error }
+		if e.As(err, &joinedErr) {
+			joinedMessages, joinedReasons := statusErrors(joinedErr.Unwrap()...)
+			messages = append(messages, joinedMessages...)
+			reasons = append(reasons, joinedReasons...)
+			continue
+		}
+		messages = append(messages, err.Error())
+		reasons = append(reasons, statusReason(err))
+	}
+	return messages, reasons
+}
+
+// appendStatusError adds an error to the Errors and Reasons lists of a PowerNodeState status entry.
+func appendStatusError(messages *[]string, reasons *[]powerv1alpha1.StatusReason, err error) {
+	newMessages, newReasons := statusErrors(err)
+	*messages = append(*messages, newMessages...)
+	*reasons = append(*reasons, newReasons...)
+}
+
+// nodeStateCondition builds a PowerNodeState condition from the errors of a status entry. The condition is
+// False with the reason of the first error if there are any. The transition time of the condition currently
+// recorded on the PowerNodeState is kept while its status does not change.
+func nodeStateCondition(ctx context.Context, c client.Reader, powerNodeStateName string, conditionType string, messages []string, reasons []powerv1alpha1.StatusReason) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             powerv1alpha1.ReasonApplied,
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(messages) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(powerv1alpha1.StatusReasonUnknown)
+		if len(reasons) > 0 {
+			condition.Reason = string(reasons[0])
+		}
+		condition.Message = strings.Join(messages, "; ")
+	}
+
+	current := &powerv1alpha1.PowerNodeState{}
+	if err := c.Get(ctx, client.ObjectKey{Name: powerNodeStateName, Namespace: PowerNamespace}, current); err == nil {
+		if existing := meta.FindStatusCondition(current.Status.Conditions, conditionType); existing != nil && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	return condition
+}
+
 // ValidEppValues defines the valid EPP (Energy Performance Preference) values
 var ValidEppValues = []string{"performance", "balance_performance", "balance_power", "power"}
 
@@ -279,6 +366,9 @@ func addPowerNodeStatusProfileEntry(ctx context.Context, c client.Client, nodeNa
 		"Min: %s, Max: %s, Governor: %s, EPP: %s, C-States: %s",
 		formatIntOrString(profile.Spec.PStates.Min), formatIntOrString(profile.Spec.PStates.Max),
 		profile.Spec.PStates.Governor, profile.Spec.PStates.Epp, cstatesString)
+	if tunables := prettifyGovernorTunables(profile.Spec.PStates.GovernorTunables); tunables != "" {
+		config += ", GovernorTunables: " + tunables
+	}
 
 	scalingStr, err := formatCPUScalingPolicy(profile.Spec.CPUScalingPolicy)
 	if err != nil {
@@ -288,8 +378,8 @@ func addPowerNodeStatusProfileEntry(ctx context.Context, c client.Client, nodeNa
 		config += ", CPUScalingPolicy: " + scalingStr
 	}
 
-	errList := util.UnpackErrsToStrings(profileErrors)
-	profileStatus := powerv1alpha1.PowerNodeProfileStatus{Name: profile.Name, Config: config, Errors: *errList}
+	errList, reasons := statusErrors(profileErrors)
+	profileStatus := powerv1alpha1.PowerNodeProfileStatus{Name: profile.Name, Config: config, Errors: errList, Reasons: reasons}
 	fieldManager := powerProfileFieldManager(profile.Name)
 
 	err = applyPowerNodeStateProfilesStatus(ctx, c, powerNodeStateName, []powerv1alpha1.PowerNodeProfileStatus{profileStatus}, fieldManager)
@@ -306,7 +396,7 @@ func addPowerNodeStatusProfileEntry(ctx context.Context, c client.Client, nodeNa
 	logger.Info("Updated PowerNodeState with profile validation results",
 		"powerNodeState", powerNodeStateName,
 		"profile", profile.Name,
-		"errors", len(*errList))
+		"errors", len(errList))
 
 	return nil
 }
@@ -402,3 +492,13 @@ func prettifyCStatesMap(states map[string]bool) string {
 
 	return strings.Join(parts, "; ")
 }
+
+// prettifyGovernorTunables formats governor tunables as a sorted, comma separated list of name=value pairs.
+func prettifyGovernorTunables(tunables map[string]int) string {
+	parts := make([]string, 0, len(tunables))
+	for name, value := range tunables {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -15,12 +15,15 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"syscall"
 	"testing"
 	"time"
 
 	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"github.com/intel/power-optimization-library/pkg/power"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -168,7 +171,7 @@ func Test_addPowerNodeStatusProfileEntry(t *testing.T) {
 		{
 			testCase:                "Apply profile with errors",
 			nodeName:                "test-node",
-			profileErr:              fmt.Errorf("invalid P-states configuration: max frequency (2000) cannot be lower than the min frequency (3000)"),
+			profileErr:              fmt.Errorf("invalid P-states configuration: %w", &power.Error{Kind: power.ErrInvalidValue, Err: fmt.Errorf("max frequency (2000) cannot be lower than the min frequency (3000)")}),
 			shouldVerifyPatchObject: true,
 			expectError:             false,
 			verifyPatchedObject: func(t *testing.T, pns *powerv1alpha1.PowerNodeState) {
@@ -184,6 +187,7 @@ func Test_addPowerNodeStatusProfileEntry(t *testing.T) {
 				assert.Equal(t, "Min: 2000, Max: 3000, Governor: powersave, EPP: balance_performance, C-States: enabled: C1,C1E; disabled: C6", pns.Status.PowerProfiles[0].Config)
 				assert.Len(t, pns.Status.PowerProfiles[0].Errors, 1, "Should have 1 error")
 				assert.Equal(t, "invalid P-states configuration: max frequency (2000) cannot be lower than the min frequency (3000)", pns.Status.PowerProfiles[0].Errors[0])
+				assert.Equal(t, []powerv1alpha1.StatusReason{powerv1alpha1.StatusReasonInvalidValue}, pns.Status.PowerProfiles[0].Reasons)
 			},
 		},
 		{
@@ -443,6 +447,13 @@ func TestPrettifyCStatesMap(t *testing.T) {
 	}
 }
 
+func TestPrettifyGovernorTunables(t *testing.T) {
+	assert.Equal(t, "", prettifyGovernorTunables(nil))
+	assert.Equal(t, "rate_limit_us=500", prettifyGovernorTunables(map[string]int{"rate_limit_us": 500}))
+	assert.Equal(t, "sampling_rate=10000,up_threshold=80",
+		prettifyGovernorTunables(map[string]int{"up_threshold": 80, "sampling_rate": 10000}))
+}
+
 func Test_removePowerNodeStatusProfileEntry(t *testing.T) {
 	ctx := context.Background()
 	logger := logr.Discard()
@@ -512,6 +523,81 @@ func Test_removePowerNodeStatusProfileEntry(t *testing.T) {
 	}
 }
 
+func Test_statusErrors(t *testing.T) {
+	messages, reasons := statusErrors()
+	assert.Nil(t, messages)
+	assert.Nil(t, reasons)
+
+	messages, reasons = statusErrors(
+		newStatusError(errConflict, "conflicting Uncore: a"),
+		fmt.Errorf("failed to set EPP value for cpu 1: %w", &power.Error{Kind: power.ErrUnsupported, Err: fmt.Errorf("EPP file does not exist")}),
+		errors.Join(
+			&power.Error{Kind: power.ErrBusy, Err: syscall.EBUSY},
+			&power.Error{Kind: power.ErrWriteFailed, Err: fmt.Errorf("permission denied")},
+		),
+		nil,
+		newStatusError(power.ErrInvalidValue, "max lower than min"),
+		&power.Error{Kind: power.ErrOutOfRange, Err: fmt.Errorf("too high")},
+		fmt.Errorf("something else"),
+	)
+	assert.Equal(t, []string{
+		"conflicting Uncore: a",
+		"failed to set EPP value for cpu 1: EPP file does not exist",
+		syscall.EBUSY.Error(),
+		"permission denied",
+		"max lower than min",
+		"too high",
+		"something else",
+	}, messages)
+	assert.Equal(t, []powerv1alpha1.StatusReason{
+		powerv1alpha1.StatusReasonConflict,
+		powerv1alpha1.StatusReasonUnsupported,
+		powerv1alpha1.StatusReasonBusy,
+		powerv1alpha1.StatusReasonWriteFailed,
+		powerv1alpha1.StatusReasonInvalidValue,
+		powerv1alpha1.StatusReasonOutOfRange,
+		powerv1alpha1.StatusReasonUnknown,
+	}, reasons)
+}
+
+func Test_nodeStateCondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = powerv1alpha1.AddToScheme(scheme)
+
+	transitionTime := v1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	pns := &powerv1alpha1.PowerNodeState{
+		ObjectMeta: v1.ObjectMeta{Name: "test-node-power-state", Namespace: PowerNamespace},
+		Status: powerv1alpha1.PowerNodeStateStatus{Conditions: []v1.Condition{{
+			Type:               powerv1alpha1.ConditionUncoreApplied,
+			Status:             v1.ConditionTrue,
+			Reason:             powerv1alpha1.ReasonApplied,
+			LastTransitionTime: transitionTime,
+		}}},
+	}
+	c := fakeClient.NewClientBuilder().WithScheme(scheme).WithObjects(pns).Build()
+
+	// unchanged status keeps the transition time
+	condition := nodeStateCondition(ctx, c, pns.Name, powerv1alpha1.ConditionUncoreApplied, nil, nil)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, powerv1alpha1.ReasonApplied, condition.Reason)
+	assert.Equal(t, transitionTime, condition.LastTransitionTime)
+
+	// errors flip the condition with the reason of the first error
+	condition = nodeStateCondition(ctx, c, pns.Name, powerv1alpha1.ConditionUncoreApplied,
+		[]string{"too high", "conflicting Uncore: a"},
+		[]powerv1alpha1.StatusReason{powerv1alpha1.StatusReasonOutOfRange, powerv1alpha1.StatusReasonConflict})
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, string(powerv1alpha1.StatusReasonOutOfRange), condition.Reason)
+	assert.Equal(t, "too high; conflicting Uncore: a", condition.Message)
+	assert.True(t, condition.LastTransitionTime.After(transitionTime.Time))
+
+	// missing PowerNodeState
+	condition = nodeStateCondition(ctx, c, "missing", powerv1alpha1.ConditionCPUPoolsApplied, nil, nil)
+	assert.Equal(t, powerv1alpha1.ConditionCPUPoolsApplied, condition.Type)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+}
+
 // Helper function to create IntOrString from int
 func intStrFromInt(val int) *intstr.IntOrString {
 	result := intstr.FromInt(val)
@@ -57,6 +57,7 @@ func setupMockHostForSharedPool(profileName string, sharedCPUs []uint) *hostMock
 	h.On("GetSharedPool").Return(sp)
 	h.On("GetReservedPool").Return(rp)
 	h.On("GetAllExclusivePools").Return(&power.PoolList{})
+	h.On("GetIdleInjection").Return(nil, uint(0))
 	ep.On("GetPowerProfile").Return(pm)
 	sp.On("SetPowerProfile", pm).Return(nil)
 	rp.On("SetCpuIDs", []uint{}).Return(nil)
@@ -164,6 +165,7 @@ func TestEnvTest_Reconcile_WithReservedCPUs(t *testing.T) {
 
 	// configureReservedPools mocks
 	h.On("GetAllExclusivePools").Return(&power.PoolList{})
+	h.On("GetIdleInjection").Return(nil, uint(0))
 	sp.On("MoveCpuIDs", []uint{0, 1}).Return(nil)
 	h.On("AddExclusivePool", fmt.Sprintf("%s-reserved-%v", nodeName, []uint{0, 1})).Return(pseudoPool, nil)
 	h.On("GetExclusivePool", "perf-prof").Return(perfPoolEP)
@@ -306,9 +308,10 @@ func TestEnvTest_Reconcile_CleanupWhenConfigDeleted(t *testing.T) {
 	require.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "my-config", Namespace: PowerNamespace}, config))
 	require.NoError(t, cl.Delete(ctx, config))
 
-	// Set up a fresh mock for cleanup — the cleanup path calls GetSharedPool().Cpus(),
+	// Set up a fresh mock for cleanup — the cleanup path calls GetIdleInjection(), GetSharedPool().Cpus(),
 	// GetAllExclusivePools(), and GetReservedPool().MoveCpus().
 	h2 := new(hostMock)
+	h2.On("GetIdleInjection").Return(nil, uint(0))
 	sp2 := createMockPoolWithCPUs([]uint{2, 3, 4, 5})
 	rp2 := new(poolMock)
 	rp2.On("MoveCpus", mock.Anything).Return(nil)
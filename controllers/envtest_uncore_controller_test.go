@@ -156,7 +156,7 @@ func TestSSA_UncoreUpdateOverwritesPrevious(t *testing.T) {
 
 	// Apply different config — same field manager, should overwrite.
 	err = r.updateUncoreInPowerNodeState(ctx, nodeName, "config-b", "Package 0: Min 1300000, Max 2200000",
-		[]string{"conflicting Uncore: config-a"}, &logger)
+		[]error{newStatusError(errConflict, "conflicting Uncore: config-a")}, &logger)
 	require.NoError(t, err)
 
 	// Verify the latest config is present.
@@ -161,7 +161,7 @@ func (r *PowerNodeConfigReconciler) applyPowerNodeConfig(
 	// settings — the controller records an error and requeues, but does not tear down the pools.
 	if err := r.validatePowerNodeConfigProfiles(ctx, config, nodeName, logger); err != nil {
 		// Record the validation error in PowerNodeState so users can see why the config isn't applied.
-		if statusErr := r.updatePowerNodeStatusPools(ctx, nodeName, config.Name, config.Spec.SharedPowerProfile, "", nil, []string{err.Error()}, logger); statusErr != nil {
+		if statusErr := r.updatePowerNodeStatusPools(ctx, nodeName, config.Name, config.Spec.SharedPowerProfile, "", 0, nil, []error{err}, logger); statusErr != nil {
 			logger.Error(statusErr, "failed to update PowerNodeState with validation error")
 		}
 		logger.Error(err, "profile validation failed, requeueing")
@@ -175,15 +175,21 @@ func (r *PowerNodeConfigReconciler) applyPowerNodeConfig(
 	reservedProfileCPUs, reservedErrors := r.configureReservedPools(config, nodeName)
 
 	// Collect all status errors.
-	var statusErrors []string
-	statusErrors = append(statusErrors, conflictErrors...)
-	for _, err := range reservedErrors {
-		statusErrors = append(statusErrors, err.Error())
+	var poolErrors []error
+	for _, conflict := range conflictErrors {
+		poolErrors = append(poolErrors, newStatusError(errConflict, "%s", conflict))
+	}
+	poolErrors = append(poolErrors, reservedErrors...)
+
+	// Idle injection is applied last so the cpumask covers the final shared pool membership.
+	idlePercent, err := r.configureIdleInjection(config, logger)
+	if err != nil {
+		poolErrors = append(poolErrors, err)
 	}
 
 	// Read current shared CPUs from POL and update status.
 	sharedCPUIDs := prettifyCoreList(r.PowerLibrary.GetSharedPool().Cpus().IDs())
-	if err := r.updatePowerNodeStatusPools(ctx, nodeName, config.Name, config.Spec.SharedPowerProfile, sharedCPUIDs, reservedProfileCPUs, statusErrors, logger); err != nil {
+	if err := r.updatePowerNodeStatusPools(ctx, nodeName, config.Name, config.Spec.SharedPowerProfile, sharedCPUIDs, idlePercent, reservedProfileCPUs, poolErrors, logger); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -206,12 +212,12 @@ func (r *PowerNodeConfigReconciler) validatePowerNodeConfigProfiles(
 	sharedProfile := &powerv1alpha1.PowerProfile{}
 	if err := r.Get(ctx, client.ObjectKey{Name: config.Spec.SharedPowerProfile, Namespace: PowerNamespace}, sharedProfile); err != nil {
 		if errors.IsNotFound(err) {
-			return fmt.Errorf("PowerProfile '%s' not found", config.Spec.SharedPowerProfile)
+			return newStatusError(power.ErrInvalidValue, "PowerProfile '%s' not found", config.Spec.SharedPowerProfile)
 		}
 		return err
 	}
 	if !sharedProfile.Spec.Shared {
-		return fmt.Errorf("PowerProfile '%s' is not a shared profile (spec.shared must be true)", config.Spec.SharedPowerProfile)
+		return newStatusError(power.ErrInvalidValue, "PowerProfile '%s' is not a shared profile (spec.shared must be true)", config.Spec.SharedPowerProfile)
 	}
 
 	// Validate reserved CPU entries are disjoint (no core appears in multiple entries).
@@ -219,7 +225,7 @@ func (r *PowerNodeConfigReconciler) validatePowerNodeConfigProfiles(
 	for _, rc := range config.Spec.ReservedCPUs {
 		for _, core := range rc.Cores {
 			if _, exists := seenCores[core]; exists {
-				return fmt.Errorf("reserved CPU %d is listed in multiple reservedCPUs entries", core)
+				return newStatusError(power.ErrInvalidValue, "reserved CPU %d is listed in multiple reservedCPUs entries", core)
 			}
 			seenCores[core] = struct{}{}
 		}
@@ -238,7 +244,7 @@ func (r *PowerNodeConfigReconciler) validatePowerNodeConfigProfiles(
 			return err
 		}
 		if !available {
-			return fmt.Errorf("PowerProfile '%s' not available on node %s", name, nodeName)
+			return newStatusError(power.ErrUnsupported, "PowerProfile '%s' not available on node %s", name, nodeName)
 		}
 	}
 	return nil
@@ -297,10 +303,12 @@ func (r *PowerNodeConfigReconciler) configureReservedPools(
 				if err := r.PowerLibrary.GetReservedPool().MoveCpuIDs(rc.Cores); err != nil {
 					return reservedProfileCPUs, []error{fmt.Errorf("failed to move cores to reserved: %w", err)}
 				}
+				errList, reasons := statusErrors(err)
 				reservedProfileCPUs = append(reservedProfileCPUs, powerv1alpha1.PowerProfileCPUs{
 					PowerProfile: rc.PowerProfile,
 					CPUIDs:       prettifyCoreList(rc.Cores),
-					Errors:       []string{err.Error()},
+					Errors:       errList,
+					Reasons:      reasons,
 				})
 			} else {
 				reservedProfileCPUs = append(reservedProfileCPUs, powerv1alpha1.PowerProfileCPUs{
@@ -320,9 +328,34 @@ func (r *PowerNodeConfigReconciler) configureReservedPools(
 	return reservedProfileCPUs, reservedErrors
 }
 
+// configureIdleInjection applies the config's idle injection percentage to the shared pool.
+// Returns the percentage in effect, which is 0 if idle injection could not be applied.
+func (r *PowerNodeConfigReconciler) configureIdleInjection(config *powerv1alpha1.PowerNodeConfig, logger *logr.Logger) (int, error) {
+	percent := 0
+	if config.Spec.SharedIdleInjectionPercent != nil {
+		percent = *config.Spec.SharedIdleInjectionPercent
+	}
+	// Nothing to disable if idle injection was never applied, e.g. on nodes without intel_powerclamp.
+	if percent == 0 {
+		if pool, _ := r.PowerLibrary.GetIdleInjection(); pool == nil {
+			return 0, nil
+		}
+	}
+	if err := r.PowerLibrary.SetIdleInjection(r.PowerLibrary.GetSharedPool(), uint(percent)); err != nil {
+		return 0, fmt.Errorf("failed to set shared pool idle injection: %w", err)
+	}
+	logger.V(5).Info("configured shared pool idle injection", "percent", percent)
+	return percent, nil
+}
+
 // cleanupPowerNodeConfigPools moves all shared and reserved CPUs back to the default
 // reserved pool, removes pseudo-reserved pools, and clears PowerNodeState status.
 func (r *PowerNodeConfigReconciler) cleanupPowerNodeConfigPools(ctx context.Context, nodeName string, logger *logr.Logger) error {
+	if pool, _ := r.PowerLibrary.GetIdleInjection(); pool != nil {
+		if err := r.PowerLibrary.SetIdleInjection(pool, 0); err != nil {
+			return fmt.Errorf("failed to disable shared pool idle injection: %w", err)
+		}
+	}
 	movedCores := *r.PowerLibrary.GetSharedPool().Cpus()
 	pools := r.PowerLibrary.GetAllExclusivePools()
 	for _, p := range *pools {
@@ -372,18 +405,22 @@ func (r *PowerNodeConfigReconciler) updatePowerNodeStatusPools(
 	configName string,
 	profileName string,
 	sharedCPUIDs string,
+	idleInjectionPercent int,
 	reservedProfileCPUs []powerv1alpha1.PowerProfileCPUs,
-	statusErrors []string,
+	poolErrors []error,
 	logger *logr.Logger,
 ) error {
 	powerNodeStateName := fmt.Sprintf("%s-power-state", nodeName)
 
+	errList, reasons := statusErrors(poolErrors...)
 	cpuPools := &powerv1alpha1.CPUPoolsStatus{
 		Shared: &powerv1alpha1.SharedCPUPoolStatus{
-			PowerProfile:    profileName,
-			PowerNodeConfig: configName,
-			CPUIDs:          sharedCPUIDs,
-			Errors:          statusErrors,
+			PowerProfile:         profileName,
+			PowerNodeConfig:      configName,
+			CPUIDs:               sharedCPUIDs,
+			IdleInjectionPercent: idleInjectionPercent,
+			Errors:               errList,
+			Reasons:              reasons,
 		},
 	}
 	if len(reservedProfileCPUs) > 0 {
@@ -406,6 +443,9 @@ func (r *PowerNodeConfigReconciler) updatePowerNodeStatusPools(
 		},
 		Status: powerv1alpha1.PowerNodeStateStatus{
 			CPUPools: cpuPools,
+			Conditions: []metav1.Condition{
+				nodeStateCondition(ctx, r.Client, powerNodeStateName, powerv1alpha1.ConditionCPUPoolsApplied, errList, reasons),
+			},
 		},
 	}
 
@@ -581,6 +581,82 @@ func TestConfigureReservedPools(t *testing.T) {
 	}
 }
 
+// --- configureIdleInjection ---
+
+func TestConfigureIdleInjection(t *testing.T) {
+	percent := func(p int) *int { return &p }
+	tcases := []struct {
+		name            string
+		percent         *int
+		setupMock       func() *hostMock
+		expectedPercent int
+		expectErr       bool
+	}{
+		{
+			name: "not requested",
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				h.On("GetIdleInjection").Return(nil, uint(0))
+				return h
+			},
+		},
+		{
+			name:    "disabled after being applied",
+			percent: percent(0),
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				sp := new(poolMock)
+				h.On("GetIdleInjection").Return(sp, uint(20))
+				h.On("GetSharedPool").Return(sp)
+				h.On("SetIdleInjection", sp, uint(0)).Return(nil)
+				return h
+			},
+		},
+		{
+			name:    "applied to shared pool",
+			percent: percent(20),
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				sp := new(poolMock)
+				h.On("GetSharedPool").Return(sp)
+				h.On("SetIdleInjection", sp, uint(20)).Return(nil)
+				return h
+			},
+			expectedPercent: 20,
+		},
+		{
+			name:    "set error",
+			percent: percent(20),
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				sp := new(poolMock)
+				h.On("GetSharedPool").Return(sp)
+				h.On("SetIdleInjection", sp, uint(20)).Return(assert.AnError)
+				return h
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			hostMk := tc.setupMock()
+			config := newPowerNodeConfig("c", "p", nil, nil, time.Now())
+			config.Spec.SharedIdleInjectionPercent = tc.percent
+			r := &PowerNodeConfigReconciler{PowerLibrary: hostMk}
+			logger := testLogger()
+			applied, err := r.configureIdleInjection(config, &logger)
+			if tc.expectErr {
+				assert.ErrorContains(t, err, "failed to set shared pool idle injection")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedPercent, applied)
+			hostMk.AssertExpectations(t)
+		})
+	}
+}
+
 // --- createReservedPool ---
 
 func TestCreateReservedPool(t *testing.T) {
@@ -702,6 +778,7 @@ func TestCleanupPowerNodeConfigPools(t *testing.T) {
 				sp := createMockPoolWithCPUs([]uint{2, 3})
 				rp := new(poolMock)
 				prp := createMockPoolWithCPUs([]uint{0, 1})
+				h.On("GetIdleInjection").Return(nil, uint(0))
 				h.On("GetSharedPool").Return(sp)
 				h.On("GetReservedPool").Return(rp)
 				h.On("GetAllExclusivePools").Return(&power.PoolList{prp})
@@ -719,6 +796,7 @@ func TestCleanupPowerNodeConfigPools(t *testing.T) {
 				sp := createMockPoolWithCPUs([]uint{2, 3})
 				rp := new(poolMock)
 				ep := new(poolMock)
+				h.On("GetIdleInjection").Return(nil, uint(0))
 				h.On("GetSharedPool").Return(sp)
 				h.On("GetReservedPool").Return(rp)
 				h.On("GetAllExclusivePools").Return(&power.PoolList{ep})
@@ -728,6 +806,34 @@ func TestCleanupPowerNodeConfigPools(t *testing.T) {
 			},
 			objs: []runtime.Object{newPowerNodeState("test-node", "config-a")},
 		},
+		{
+			name: "idle injection disabled",
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				sp := createMockPoolWithCPUs([]uint{2, 3})
+				rp := new(poolMock)
+				h.On("GetIdleInjection").Return(sp, uint(20))
+				h.On("SetIdleInjection", sp, uint(0)).Return(nil)
+				h.On("GetSharedPool").Return(sp)
+				h.On("GetReservedPool").Return(rp)
+				h.On("GetAllExclusivePools").Return(&power.PoolList{})
+				rp.On("MoveCpus", mock.Anything).Return(nil)
+				return h
+			},
+			objs: []runtime.Object{newPowerNodeState("test-node", "config-a")},
+		},
+		{
+			name: "idle injection disable error",
+			setupMock: func() *hostMock {
+				h := new(hostMock)
+				sp := new(poolMock)
+				h.On("GetIdleInjection").Return(sp, uint(20))
+				h.On("SetIdleInjection", sp, uint(0)).Return(assert.AnError)
+				return h
+			},
+			objs:      []runtime.Object{newPowerNodeState("test-node", "config-a")},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range tcases {
@@ -790,6 +896,7 @@ func TestApplyPowerNodeConfig(t *testing.T) {
 				h.On("GetSharedPool").Return(sp)
 				h.On("GetReservedPool").Return(rp)
 				h.On("GetAllExclusivePools").Return(&power.PoolList{})
+				h.On("GetIdleInjection").Return(nil, uint(0))
 				ep.On("GetPowerProfile").Return(pm)
 				sp.On("SetPowerProfile", pm).Return(nil)
 				rp.On("SetCpuIDs", []uint{}).Return(nil)
@@ -19,11 +19,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	e "errors"
 
@@ -44,19 +47,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/cluster-power-manager/cluster-power-manager/pkg/cgroup"
 	"github.com/cluster-power-manager/cluster-power-manager/pkg/podresourcesclient"
 	"github.com/cluster-power-manager/cluster-power-manager/pkg/podstate"
 )
 
 const (
-	PowerProfileAnnotation  = "PowerProfile"
-	ResourcePrefix          = "power.cluster-power-manager.github.io/"
-	CPUResource             = "cpu"
-	WorkloadTypePollingDPDK = "polling-dpdk"
-	PowerNamespace          = "power-manager"
+	PowerProfileAnnotation     = "PowerProfile"
+	ResourcePrefix             = "power.cluster-power-manager.github.io/"
+	CPUResource                = "cpu"
+	WorkloadTypePollingDPDK    = "polling-dpdk"
+	WorkloadTypeCPUUtilisation = "cpu-utilisation"
+	ScalingControllerPID       = "pid"
+	ScalingActuatorMaxFreq     = "max-freq"
+	ScalingActuatorEPP         = "epp"
+	PowerNamespace             = "power-manager"
+
+	// UClampProfileAnnotation names a PowerProfile whose uclamp values are applied to the pod's shared pool containers
+	UClampProfileAnnotation = ResourcePrefix + "uclamp-profile"
+	// UClampMinAnnotation and UClampMaxAnnotation set uclamp values in percent, overriding the profile's values
+	UClampMinAnnotation = ResourcePrefix + "uclamp-min"
+	UClampMaxAnnotation = ResourcePrefix + "uclamp-max"
+
+	// DPDKTelemetrySocketAnnotation sets the DPDK telemetry socket relative to the pod's DPDK directory
+	DPDKTelemetrySocketAnnotation = ResourcePrefix + "dpdk-telemetry-socket"
+	// DPDKFilePrefixAnnotation sets the --file-prefix of the DPDK application, naming its runtime directory
+	DPDKFilePrefixAnnotation = ResourcePrefix + "dpdk-file-prefix"
 )
 
 // PowerPodReconciler reconciles a Pod object
@@ -68,7 +88,12 @@ type PowerPodReconciler struct {
 	PodResourcesClient  podresourcesclient.PodResourcesClient
 	PowerLibrary        power.Host
 	DPDKTelemetryClient scaling.DPDKTelemetryClient
+	CPUUsageClient      scaling.CPUUsageClient
 	CPUScalingManager   scaling.CPUScalingManager
+	UClampWriter        cgroup.UClampWriter
+	// ScalingStatusInterval is the interval at which the scaling status of containers is refreshed in the
+	// PowerNodeState, the status is not reported if it is zero
+	ScalingStatusInterval time.Duration
 }
 
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
@@ -134,11 +159,13 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				}
 			}
 
-			// Tear down DPDK telemetry and scaling for this pod's CPUs.
-			// No-op for non-DPDK pods: CloseConnection and RemoveCPUScaling
+			// Tear down usage sampling and scaling for this pod's CPUs.
+			// No-op for pods without scaling: CloseConnection and RemoveCPUScaling
 			// safely ignore entries that don't exist.
-			if r.DPDKTelemetryClient != nil && r.CPUScalingManager != nil {
-				r.DPDKTelemetryClient.CloseConnection(string(pod.GetUID()))
+			if r.CPUScalingManager != nil {
+				for _, source := range r.usageSources() {
+					source.CloseConnection(string(pod.GetUID()))
+				}
 				r.CPUScalingManager.RemoveCPUScaling(deletedCPUIDs)
 			}
 		}
@@ -147,6 +174,12 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		if err := r.removePowerNodeStatusExclusiveEntry(ctx, nodeName, string(pod.GetUID()), &logger); err != nil {
 			return ctrl.Result{}, err
 		}
+		// The container cgroups are removed together with the pod, only the status entry is left.
+		if r.UClampWriter != nil && hasUClampRequest(pod) {
+			if err := r.removePowerNodeStatusUClampEntry(ctx, nodeName, string(pod.GetUID()), &logger); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 
 		return ctrl.Result{}, nil
 	}
@@ -160,10 +193,26 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, podNotRunningErr
 	}
 
+	// Apply uclamp values to containers running in the shared pool, or reset them once the annotations are removed.
+	var uclampErrs []error
+	if r.UClampWriter != nil {
+		if hasUClampRequest(pod) {
+			uclampErrs, err = r.reconcileUClamp(ctx, pod, nodeName, &logger)
+		} else {
+			err = r.resetUClamp(ctx, pod, nodeName, &logger)
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Get the Containers of the Pod that are requesting exclusive CPUs.
 	admissibleContainers := getAdmissibleContainers(pod, r.PodResourcesClient, &logger)
 	if len(admissibleContainers) == 0 {
 		logger.Info("no containers are requesting exclusive CPUs")
+		if wrappedErrs := e.Join(uclampErrs...); wrappedErrs != nil {
+			return ctrl.Result{}, fmt.Errorf("recoverable errors encountered: %w", wrappedErrs)
+		}
 		return ctrl.Result{}, nil
 	}
 	podUID := pod.GetUID()
@@ -175,13 +224,12 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	// Get the power containers requested by containers in the pod.
 	powerContainers, recoveryErrs := r.getPowerProfileRequestsFromContainers(ctx, admissibleContainers, pod, &logger)
+	recoveryErrs = append(recoveryErrs, uclampErrs...)
 	logger.V(5).Info("retrieved power profiles and containers from pod requests")
 
-	// dpdkContainerAssigned tracks whether a DPDK container in this pod has already
-	// been assigned telemetry and scaling. Only one DPDK container per pod is supported.
-	dpdkContainerAssigned := false
 	// Reconcile CPU pools and track errors per container.
 	// Skip containers that already have errors (e.g., profile unavailability).
+	scaledContainers := map[string]bool{}
 	for i := range powerContainers {
 		container := &powerContainers[i]
 		// Skip CPU pool reconciliation for containers with existing errors.
@@ -194,7 +242,7 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		if exclusivePool == nil {
 			err := fmt.Errorf("exclusive pool for profile %s not found", container.PowerProfile)
 			logger.Error(err, "failed to get exclusive pool", "container", container.Name)
-			container.Errors = append(container.Errors, err.Error())
+			appendStatusError(&container.Errors, &container.Reasons, err)
 			recoveryErrs = append(recoveryErrs, err)
 			continue
 		}
@@ -215,14 +263,14 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			logger.V(5).Info("moving CPUs to exclusive pool", "profile", container.PowerProfile, "container", container.Name, "cpus", coresToAdd)
 			if err := exclusivePool.MoveCpuIDs(coresToAdd); err != nil {
 				logger.Error(err, "failed to move CPUs to exclusive pool", "profile", container.PowerProfile, "container", container.Name)
-				container.Errors = append(container.Errors, err.Error())
+				appendStatusError(&container.Errors, &container.Reasons, err)
 				recoveryErrs = append(recoveryErrs, err)
 				continue
 			}
 		}
 
-		// Set up DPDK telemetry and scaling if the profile has a CPUScalingPolicy.
-		if r.DPDKTelemetryClient == nil || r.CPUScalingManager == nil {
+		// Set up usage sampling and scaling if the profile has a CPUScalingPolicy.
+		if r.CPUScalingManager == nil || len(r.usageSources()) == 0 {
 			continue
 		}
 		profile := &powerv1alpha1.PowerProfile{}
@@ -230,38 +278,67 @@ func (r *PowerPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			if errors.IsNotFound(err) {
 				// Unlikely: profile was validated moments ago, but handle deletion between checks.
 				errMsg := fmt.Sprintf("PowerProfile '%s' not found", container.PowerProfile)
-				container.Errors = append(container.Errors, errMsg)
+				appendStatusError(&container.Errors, &container.Reasons, newStatusError(power.ErrInvalidValue, "%s", errMsg))
 				recoveryErrs = append(recoveryErrs, errors.NewServiceUnavailable(errMsg))
 				continue
 			}
 			return ctrl.Result{}, fmt.Errorf("failed to get PowerProfile: %w", err)
 		}
-		if profile.Spec.CPUScalingPolicy != nil && profile.Spec.CPUScalingPolicy.WorkloadType == WorkloadTypePollingDPDK {
-			if dpdkContainerAssigned {
-				container.Errors = append(container.Errors,
-					"DPDK dynamic frequency scaling is only supported for a single container per pod; this container is skipped")
-			} else {
-				dpdkContainerAssigned = true
-				// Ensure a DPDK telemetry connection exists for this pod.
-				r.DPDKTelemetryClient.EnsureConnection(&scaling.DPDKTelemetryConnectionData{
-					PodUID:      string(podUID),
-					WatchedCPUs: container.CPUIDs,
-				})
-				// Build per-CPU scaling options.
-				scalingOpts, err := r.generateCPUScalingOpts(profile.Spec.CPUScalingPolicy, container.CPUIDs)
-				if err != nil {
-					msg := "some CPUs could not be configured for DPDK scaling"
-					logger.Error(err, msg, "container", container.Name)
-					container.Errors = append(container.Errors, fmt.Sprintf("%s: %v", msg, err))
-				}
-				if len(scalingOpts) > 0 {
-					r.CPUScalingManager.AddCPUScaling(scalingOpts)
-				}
+		scalingPolicy := profile.Spec.CPUScalingPolicy
+		if scalingPolicy == nil {
+			continue
+		}
+		if scalingPolicy.Actuator == ScalingActuatorEPP && !power.IsFeatureSupported(power.EPPFeature) {
+			err := newStatusError(power.ErrUnsupported, "EPP is not supported, PowerProfile '%s' cannot scale with the epp actuator", container.PowerProfile)
+			logger.Error(err, "cannot scale the frequency of the container", "container", container.Name)
+			appendStatusError(&container.Errors, &container.Reasons, err)
+			continue
+		}
+		var usageSource scaling.UsageSource
+		switch {
+		case scalingPolicy.WorkloadType == WorkloadTypePollingDPDK && r.DPDKTelemetryClient != nil:
+			socketPath, err := dpdkTelemetrySocketPath(pod)
+			if err != nil {
+				// Invalid annotations are fixed by updating the pod, which triggers a new reconcile.
+				logger.Error(err, "invalid DPDK telemetry socket", "container", container.Name)
+				appendStatusError(&container.Errors, &container.Reasons, err)
+				continue
 			}
+			// Ensure the DPDK telemetry connection of this pod watches the container's CPUs.
+			// Containers of the same pod share the telemetry sockets of its DPDK processes.
+			r.DPDKTelemetryClient.EnsureConnection(&scaling.DPDKTelemetryConnectionData{
+				PodUID:        string(podUID),
+				ContainerName: container.Name,
+				WatchedCPUs:   container.CPUIDs,
+				SocketPath:    socketPath,
+			})
+			usageSource = r.DPDKTelemetryClient
+		case scalingPolicy.WorkloadType == WorkloadTypeCPUUtilisation && r.CPUUsageClient != nil:
+			// Sample the busy time of the container's exclusive CPUs.
+			r.CPUUsageClient.EnsureConnection(&scaling.CPUUsageConnectionData{
+				PodUID:        string(podUID),
+				ContainerName: container.Name,
+				WatchedCPUs:   container.CPUIDs,
+			})
+			usageSource = r.CPUUsageClient
+		default:
+			continue
+		}
+		scaledContainers[container.Name] = true
+		// Build per-CPU scaling options.
+		scalingOpts, err := r.generateCPUScalingOpts(scalingPolicy, container.CPUIDs, usageSource)
+		if err != nil {
+			msg := "some CPUs could not be configured for dynamic scaling"
+			logger.Error(err, msg, "container", container.Name)
+			appendStatusError(&container.Errors, &container.Reasons, fmt.Errorf("%s: %w", msg, err))
+		}
+		if len(scalingOpts) > 0 {
+			r.CPUScalingManager.AddCPUScaling(scalingOpts)
 		}
 	}
 
 	// Update PowerNodeState status with container info (errors are already on each PowerContainer).
+	r.keepScalingStatus(ctx, nodeName, string(podUID), powerContainers, scaledContainers)
 	if err := r.addPowerNodeStatusExclusiveEntry(ctx, nodeName, string(podUID), pod.Name, powerContainers, &logger); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -310,11 +387,13 @@ func (r *PowerPodReconciler) getPowerProfileRequestsFromContainers(ctx context.C
 			// Pod spec validation errors are not recoverable (pod spec is immutable).
 			// Store the error in PowerNodeState for visibility but don't trigger requeue.
 			logger.Error(err, "pod spec validation error", "container", container.Name)
+			errList, reasons := statusErrors(newStatusError(power.ErrInvalidValue, "%w", err))
 			powerContainers = append(powerContainers, powerv1alpha1.PowerContainer{
-				Name:   container.Name,
-				ID:     containerID,
-				CPUIDs: []uint{},
-				Errors: []string{err.Error()},
+				Name:    container.Name,
+				ID:      containerID,
+				CPUIDs:  []uint{},
+				Errors:  errList,
+				Reasons: reasons,
 			})
 			continue
 		}
@@ -341,6 +420,7 @@ func (r *PowerPodReconciler) getPowerProfileRequestsFromContainers(ctx context.C
 				PowerProfile: profileName,
 				CPUIDs:       []uint{},
 				Errors:       []string{errMsg},
+				Reasons:      []powerv1alpha1.StatusReason{powerv1alpha1.StatusReasonUnsupported},
 			})
 			continue
 		}
@@ -526,6 +606,9 @@ func PowerReleventPodPredicate(obj client.Object) bool {
 	if pod.Spec.NodeName != os.Getenv("NODE_NAME") {
 		return false
 	}
+	if hasUClampRequest(pod) {
+		return true
+	}
 	for _, c := range allPodContainers(pod) {
 		// No need to check the limits, as if the requests are present,
 		// the limits must be present as well.
@@ -543,7 +626,13 @@ func PowerReleventPodPredicate(obj client.Object) bool {
 // ownership — each pod should use a unique field manager (e.g., "powerpod-controller.pod-uid")
 // so that SSA can track ownership at the element level for the map-type Exclusive list.
 func (r *PowerPodReconciler) applyPowerNodeStateExclusiveStatus(ctx context.Context, powerNodeStateName string, exclusive []powerv1alpha1.ExclusiveCPUPoolStatus, fieldManager string) error {
-	patchNodeState := &powerv1alpha1.PowerNodeState{
+	patchNodeState := newExclusiveStatusPatch(powerNodeStateName, exclusive)
+	return r.Status().Patch(ctx, patchNodeState, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// newExclusiveStatusPatch returns a PowerNodeState to apply the given exclusive CPU pool entries with
+func newExclusiveStatusPatch(powerNodeStateName string, exclusive []powerv1alpha1.ExclusiveCPUPoolStatus) *powerv1alpha1.PowerNodeState {
+	return &powerv1alpha1.PowerNodeState{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: powerv1alpha1.GroupVersion.String(),
 			Kind:       PowerNodeStateKind,
@@ -558,8 +647,6 @@ func (r *PowerPodReconciler) applyPowerNodeStateExclusiveStatus(ctx context.Cont
 			},
 		},
 	}
-
-	return r.Status().Patch(ctx, patchNodeState, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
 }
 
 // addPowerNodeStatusExclusiveEntry updates the PowerNodeState status with exclusive CPU pool
@@ -624,6 +711,234 @@ func (r *PowerPodReconciler) removePowerNodeStatusExclusiveEntry(
 	return nil
 }
 
+// dpdkTelemetrySocketPath returns the DPDK telemetry socket requested by the pod's annotations, relative to the
+// pod's DPDK directory. An empty path lets the telemetry client discover the sockets of all DPDK processes of the pod.
+func dpdkTelemetrySocketPath(pod *corev1.Pod) (string, error) {
+	socketPath, hasSocket := pod.Annotations[DPDKTelemetrySocketAnnotation]
+	filePrefix, hasPrefix := pod.Annotations[DPDKFilePrefixAnnotation]
+	switch {
+	case hasSocket && hasPrefix:
+		return "", newStatusError(power.ErrInvalidValue, "annotations %s and %s are mutually exclusive",
+			DPDKTelemetrySocketAnnotation, DPDKFilePrefixAnnotation)
+	case hasSocket:
+		if !filepath.IsLocal(socketPath) {
+			return "", newStatusError(power.ErrInvalidValue, "annotation %s must be a relative path within the pod's DPDK directory, got '%s'",
+				DPDKTelemetrySocketAnnotation, socketPath)
+		}
+		return filepath.Clean(socketPath), nil
+	case hasPrefix:
+		if !filepath.IsLocal(filePrefix) || strings.ContainsRune(filePrefix, filepath.Separator) {
+			return "", newStatusError(power.ErrInvalidValue, "annotation %s must be a single directory name, got '%s'",
+				DPDKFilePrefixAnnotation, filePrefix)
+		}
+		return filepath.Join(filePrefix, scaling.TelemetrySocketName), nil
+	}
+	return "", nil
+}
+
+// hasUClampRequest returns true if the pod requests uclamp values through annotations.
+func hasUClampRequest(pod *corev1.Pod) bool {
+	for _, annotation := range []string{UClampProfileAnnotation, UClampMinAnnotation, UClampMaxAnnotation} {
+		if _, ok := pod.Annotations[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveUClamp returns the uclamp values requested by the pod. Values of the profile named by
+// UClampProfileAnnotation are overridden by UClampMinAnnotation and UClampMaxAnnotation, unset
+// values default to no clamping.
+func (r *PowerPodReconciler) resolveUClamp(ctx context.Context, pod *corev1.Pod) (cgroup.UClamp, error) {
+	uclamp := cgroup.UClamp{Min: 0, Max: 100}
+	if profileName, ok := pod.Annotations[UClampProfileAnnotation]; ok {
+		profile := &powerv1alpha1.PowerProfile{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: PowerNamespace, Name: profileName}, profile); err != nil {
+			if errors.IsNotFound(err) {
+				return uclamp, errors.NewServiceUnavailable(fmt.Sprintf("PowerProfile '%s' not found", profileName))
+			}
+			return uclamp, err
+		}
+		if profile.Spec.UClamp == nil {
+			return uclamp, newStatusError(power.ErrInvalidValue, "PowerProfile '%s' has no uclamp configuration", profileName)
+		}
+		if profile.Spec.UClamp.Min != nil {
+			uclamp.Min = *profile.Spec.UClamp.Min
+		}
+		if profile.Spec.UClamp.Max != nil {
+			uclamp.Max = *profile.Spec.UClamp.Max
+		}
+	}
+	overrides := []struct {
+		annotation string
+		value      *int
+	}{{UClampMinAnnotation, &uclamp.Min}, {UClampMaxAnnotation, &uclamp.Max}}
+	for _, override := range overrides {
+		annotation, value := override.annotation, override.value
+		raw, ok := pod.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		percent, err := strconv.Atoi(raw)
+		if err != nil || percent < 0 || percent > 100 {
+			return uclamp, newStatusError(power.ErrInvalidValue, "annotation %s must be a percentage between 0 and 100, got '%s'", annotation, raw)
+		}
+		*value = percent
+	}
+	if uclamp.Min > uclamp.Max {
+		return uclamp, newStatusError(power.ErrInvalidValue, "uclamp min %d is greater than max %d", uclamp.Min, uclamp.Max)
+	}
+	return uclamp, nil
+}
+
+// sharedPoolContainers returns the containers of a pod running in the shared pool, i.e. the containers
+// which neither request a PowerProfile nor are given exclusive CPUs by the CPU manager.
+func sharedPoolContainers(pod *corev1.Pod, logger *logr.Logger) []corev1.Container {
+	containers := []corev1.Container{}
+	for _, container := range pod.Spec.Containers {
+		if profileName, _, _ := getContainerProfileFromRequests(container, logger); profileName != "" {
+			continue
+		}
+		if doesContainerRequireExclusiveCPUs(pod, &container, logger) {
+			continue
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// reconcileUClamp writes the pod's uclamp values into the cgroups of its containers running in the
+// shared pool, and records the result in PowerNodeState.
+// Returns the errors that should be retried, the status update error is returned separately.
+func (r *PowerPodReconciler) reconcileUClamp(ctx context.Context, pod *corev1.Pod, nodeName string, logger *logr.Logger) ([]error, error) {
+	var recoverableErrs []error
+	entry := powerv1alpha1.UClampPodStatus{
+		PodUID:     string(pod.GetUID()),
+		Pod:        pod.GetName(),
+		Containers: []powerv1alpha1.UClampContainer{},
+	}
+
+	uclamp, err := r.resolveUClamp(ctx, pod)
+	if err != nil {
+		// Invalid annotations are fixed by updating the pod, which triggers a new reconcile.
+		logger.Error(err, "failed to resolve uclamp values")
+		appendStatusError(&entry.Errors, &entry.Reasons, err)
+		if errors.IsServiceUnavailable(err) {
+			recoverableErrs = append(recoverableErrs, err)
+		}
+	} else {
+		entry.Min, entry.Max = uclamp.Min, uclamp.Max
+		for _, container := range sharedPoolContainers(pod, logger) {
+			containerStatus := powerv1alpha1.UClampContainer{
+				Name: container.Name,
+				ID:   cgroup.StripContainerRuntime(getContainerID(pod, container.Name)),
+			}
+			if err := r.UClampWriter.SetContainerUClamp(string(pod.GetUID()), containerStatus.ID, uclamp); e.Is(err, cgroup.ErrUnsupported) {
+				// Retrying does not help until the node agent can write the pod cgroups.
+				logger.Info("uclamp is not supported on the node", "container", container.Name, "reason", err.Error())
+				appendStatusError(&containerStatus.Errors, &containerStatus.Reasons, &power.Error{Kind: power.ErrUnsupported, Err: err})
+			} else if err != nil {
+				logger.Error(err, "failed to apply uclamp values", "container", container.Name)
+				appendStatusError(&containerStatus.Errors, &containerStatus.Reasons, &power.Error{Kind: power.ErrWriteFailed, Err: err})
+				recoverableErrs = append(recoverableErrs, err)
+			} else {
+				logger.V(5).Info("applied uclamp values", "container", container.Name, "min", uclamp.Min, "max", uclamp.Max)
+			}
+			entry.Containers = append(entry.Containers, containerStatus)
+		}
+	}
+
+	fieldManager := fmt.Sprintf("powerpod-controller-uclamp.%s", pod.GetUID())
+	if err := r.applyPowerNodeStateUClampStatus(ctx, fmt.Sprintf("%s-power-state", nodeName), []powerv1alpha1.UClampPodStatus{entry}, fieldManager); err != nil {
+		logger.Error(err, "failed to update PowerNodeState uclamp status")
+		return recoverableErrs, err
+	}
+	return recoverableErrs, nil
+}
+
+// resetUClamp restores the default uclamp values of the shared pool containers of a pod whose uclamp annotations
+// were removed, i.e. which still has a uclamp entry in PowerNodeState, and removes the entry once all are restored.
+func (r *PowerPodReconciler) resetUClamp(ctx context.Context, pod *corev1.Pod, nodeName string, logger *logr.Logger) error {
+	powerNodeStateName := fmt.Sprintf("%s-power-state", nodeName)
+	nodeState := &powerv1alpha1.PowerNodeState{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: PowerNamespace, Name: powerNodeStateName}, nodeState); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	podUID := string(pod.GetUID())
+	if nodeState.Status.CPUPools == nil || !slices.ContainsFunc(nodeState.Status.CPUPools.UClamp, func(entry powerv1alpha1.UClampPodStatus) bool {
+		return entry.PodUID == podUID
+	}) {
+		return nil
+	}
+
+	var errs []error
+	for _, container := range sharedPoolContainers(pod, logger) {
+		containerID := cgroup.StripContainerRuntime(getContainerID(pod, container.Name))
+		// cpu.uclamp.min 0 and cpu.uclamp.max max are the defaults of the kernel
+		err := r.UClampWriter.SetContainerUClamp(podUID, containerID, cgroup.UClamp{Min: 0, Max: 100})
+		if e.Is(err, cgroup.ErrUnsupported) {
+			// nothing was written to the cgroup, there is nothing to reset
+			logger.V(5).Info("uclamp is not supported on the node, skipping reset", "container", container.Name)
+			continue
+		}
+		if err != nil {
+			logger.Error(err, "failed to reset uclamp values", "container", container.Name)
+			errs = append(errs, err)
+			continue
+		}
+		logger.V(5).Info("reset uclamp values", "container", container.Name)
+	}
+	if err := e.Join(errs...); err != nil {
+		return err
+	}
+	return r.removePowerNodeStatusUClampEntry(ctx, nodeName, podUID, logger)
+}
+
+// applyPowerNodeStateUClampStatus applies the given uclamp entries to the PowerNodeState status
+// using Server-Side Apply. Like exclusive entries, each pod uses its own field manager.
+func (r *PowerPodReconciler) applyPowerNodeStateUClampStatus(ctx context.Context, powerNodeStateName string, uclamp []powerv1alpha1.UClampPodStatus, fieldManager string) error {
+	patchNodeState := &powerv1alpha1.PowerNodeState{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: powerv1alpha1.GroupVersion.String(),
+			Kind:       PowerNodeStateKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      powerNodeStateName,
+			Namespace: PowerNamespace,
+		},
+		Status: powerv1alpha1.PowerNodeStateStatus{
+			CPUPools: &powerv1alpha1.CPUPoolsStatus{
+				UClamp: uclamp,
+			},
+		},
+	}
+
+	return r.Status().Patch(ctx, patchNodeState, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// removePowerNodeStatusUClampEntry removes a pod's uclamp entry from the PowerNodeState status.
+func (r *PowerPodReconciler) removePowerNodeStatusUClampEntry(
+	ctx context.Context,
+	nodeName string,
+	podUID string,
+	logger *logr.Logger,
+) error {
+	powerNodeStateName := fmt.Sprintf("%s-power-state", nodeName)
+	fieldManager := fmt.Sprintf("powerpod-controller-uclamp.%s", podUID)
+
+	logger.V(5).Info("removing uclamp entry from PowerNodeState via SSA", "fieldManager", fieldManager)
+	if err := r.applyPowerNodeStateUClampStatus(ctx, powerNodeStateName, []powerv1alpha1.UClampPodStatus{}, fieldManager); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(5).Info("PowerNodeState not found, skipping uclamp status cleanup", "powerNodeState", powerNodeStateName)
+			return nil
+		}
+		logger.Error(err, "failed to remove uclamp entry from PowerNodeState status")
+		return err
+	}
+
+	return nil
+}
+
 // areCPUsInSharedPool checks if all specified CPUs are currently in the shared pool.
 // Returns false if any CPU is still in the reserved pool (shared workload not yet processed).
 func (r *PowerPodReconciler) areCPUsInSharedPool(cpuIDs []uint) bool {
@@ -636,9 +951,23 @@ func (r *PowerPodReconciler) areCPUsInSharedPool(cpuIDs []uint) bool {
 	return true
 }
 
+// usageSources returns the configured sources of CPU usage for dynamic scaling
+func (r *PowerPodReconciler) usageSources() []scaling.UsageSource {
+	sources := []scaling.UsageSource{}
+	if r.DPDKTelemetryClient != nil {
+		sources = append(sources, r.DPDKTelemetryClient)
+	}
+	if r.CPUUsageClient != nil {
+		sources = append(sources, r.CPUUsageClient)
+	}
+	return sources
+}
+
 // generateCPUScalingOpts translates a CPUScalingPolicy and a set of CPUs
 // into a list of per-CPU scaling options used by the CPUScalingManager.
-func (r *PowerPodReconciler) generateCPUScalingOpts(scalingPolicy *powerv1alpha1.CPUScalingPolicy, cpuIDs []uint) ([]scaling.CPUScalingOpts, error) {
+func (r *PowerPodReconciler) generateCPUScalingOpts(
+	scalingPolicy *powerv1alpha1.CPUScalingPolicy, cpuIDs []uint, usageSource scaling.UsageSource,
+) ([]scaling.CPUScalingOpts, error) {
 	allCpus := r.PowerLibrary.GetAllCpus()
 	optsList := make([]scaling.CPUScalingOpts, 0, len(cpuIDs))
 
@@ -649,26 +978,7 @@ func (r *PowerPodReconciler) generateCPUScalingOpts(scalingPolicy *powerv1alpha1
 			missingCPUs = append(missingCPUs, id)
 			continue
 		}
-
-		// Get the min and max frequency of this CPU and calculate the fallback frequency.
-		minFreq, maxFreq := cpu.GetAbsMinMax()
-		fallbackFreqPct := uint(*scalingPolicy.FallbackFreqPercent)
-		fallbackFreq := minFreq + (maxFreq-minFreq)*(fallbackFreqPct)/100
-
-		opts := scaling.CPUScalingOpts{
-			CPU:                        cpu,
-			SamplePeriod:               scalingPolicy.SamplePeriod.Duration,
-			CooldownPeriod:             scalingPolicy.CooldownPeriod.Duration,
-			TargetUsage:                *scalingPolicy.TargetUsage,
-			AllowedUsageDifference:     *scalingPolicy.AllowedUsageDifference,
-			AllowedFrequencyDifference: *scalingPolicy.AllowedFrequencyDifference * 1000,
-			HWMaxFrequency:             int(maxFreq),
-			HWMinFrequency:             int(minFreq),
-			CurrentTargetFrequency:     scaling.FrequencyNotYetSet,
-			ScaleFactor:                float64(*scalingPolicy.ScalePercentage) / 100.0,
-			FallbackFreq:               int(fallbackFreq),
-		}
-		optsList = append(optsList, opts)
+		optsList = append(optsList, NewCPUScalingOpts(scalingPolicy, cpu, usageSource))
 	}
 
 	var err error
@@ -678,6 +988,126 @@ func (r *PowerPodReconciler) generateCPUScalingOpts(scalingPolicy *powerv1alpha1
 	return optsList, err
 }
 
+// NewCPUScalingOpts translates a CPUScalingPolicy into the scaling options of a single CPU,
+// sampling its usage from usageSource
+func NewCPUScalingOpts(scalingPolicy *powerv1alpha1.CPUScalingPolicy, cpu power.Cpu, usageSource scaling.UsageSource) scaling.CPUScalingOpts {
+	// Scale within the effective frequency limits of this CPU, which the P-states of the profile and any
+	// other active constraints resolve to, and calculate the fallback frequency relative to them.
+	// The scaler follows later changes of the limits.
+	minFreq, maxFreq := cpu.GetFrequencyLimitsExcluding(power.ScalerRequester)
+	fallbackFreqPct := *scalingPolicy.FallbackFreqPercent
+
+	opts := scaling.CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                usageSource,
+		SamplePeriod:               scalingPolicy.SamplePeriod.Duration,
+		CooldownPeriod:             scalingPolicy.CooldownPeriod.Duration,
+		TargetUsage:                *scalingPolicy.TargetUsage,
+		AllowedUsageDifference:     *scalingPolicy.AllowedUsageDifference,
+		AllowedFrequencyDifference: *scalingPolicy.AllowedFrequencyDifference * 1000,
+		MaxFrequency:               int(maxFreq),
+		MinFrequency:               int(minFreq),
+		CurrentTargetFrequency:     scaling.FrequencyNotYetSet,
+		ScaleFactor:                float64(*scalingPolicy.ScalePercentage) / 100.0,
+		FallbackFreq:               scaling.FallbackFrequency(minFreq, maxFreq, fallbackFreqPct),
+		FallbackFreqPercent:        fallbackFreqPct,
+	}
+	// asymmetric scaling falls back to the symmetric settings for unset fields
+	if scalingPolicy.ScaleUpPercentage != nil {
+		opts.ScaleFactor = float64(*scalingPolicy.ScaleUpPercentage) / 100.0
+	}
+	if scalingPolicy.ScaleDownPercentage != nil {
+		opts.ScaleDownFactor = float64(*scalingPolicy.ScaleDownPercentage) / 100.0
+	}
+	if scalingPolicy.MaxFrequencyStep != nil {
+		opts.MaxFrequencyStep = *scalingPolicy.MaxFrequencyStep * 1000
+	}
+	if scalingPolicy.ScaleDownCooldownPeriod != nil {
+		opts.ScaleDownCooldownPeriod = scalingPolicy.ScaleDownCooldownPeriod.Duration
+	}
+	if scalingPolicy.BurstUsageThreshold != nil {
+		opts.BurstUsage = *scalingPolicy.BurstUsageThreshold
+	}
+	switch scalingPolicy.Actuator {
+	case ScalingActuatorMaxFreq:
+		opts.Actuator = scaling.ActuatorMaxFreq
+	case ScalingActuatorEPP:
+		opts.Actuator = scaling.ActuatorEPP
+	}
+	if scalingPolicy.Controller == ScalingControllerPID {
+		// each CPU runs its own controller
+		opts.PID = newPIDOpts(scalingPolicy.PID)
+	}
+	if scalingPolicy.AdaptiveGain != nil {
+		// and adapts its own gain
+		opts.AdaptiveGain = newAdaptiveGainOpts(scalingPolicy.AdaptiveGain)
+	}
+	if scalingPolicy.IdleCStates != nil {
+		opts.IdleCStates = newIdleCStatesOpts(scalingPolicy.IdleCStates)
+	}
+	return opts
+}
+
+// newIdleCStatesOpts converts the idle C-states of a CPUScalingPolicy, using the defaults for unset fields
+func newIdleCStatesOpts(idleCStates *powerv1alpha1.IdleCStates) *scaling.IdleCStatesOpts {
+	usageThreshold, idlePeriod := 10, time.Second
+	if idleCStates.UsageThreshold != nil {
+		usageThreshold = *idleCStates.UsageThreshold
+	}
+	if idleCStates.IdlePeriod != nil {
+		idlePeriod = idleCStates.IdlePeriod.Duration
+	}
+	return &scaling.IdleCStatesOpts{
+		UsageThreshold: usageThreshold,
+		IdlePeriod:     idlePeriod,
+		States:         maps.Clone(idleCStates.Names),
+	}
+}
+
+// newAdaptiveGainOpts converts the adaptive gain settings of a CPUScalingPolicy, using the defaults for unset fields
+func newAdaptiveGainOpts(adaptiveGain *powerv1alpha1.AdaptiveGain) *scaling.AdaptiveGainOpts {
+	window, maxReversals, minGain, maxGain := time.Second, 3, 25, 200
+	if adaptiveGain.Window != nil {
+		window = adaptiveGain.Window.Duration
+	}
+	if adaptiveGain.MaxReversals != nil {
+		maxReversals = *adaptiveGain.MaxReversals
+	}
+	if adaptiveGain.MinGainPercent != nil {
+		minGain = *adaptiveGain.MinGainPercent
+	}
+	if adaptiveGain.MaxGainPercent != nil {
+		maxGain = *adaptiveGain.MaxGainPercent
+	}
+	return &scaling.AdaptiveGainOpts{
+		Window:       window,
+		MaxReversals: maxReversals,
+		MinGain:      float64(minGain) / 100.0,
+		MaxGain:      float64(maxGain) / 100.0,
+	}
+}
+
+// newPIDOpts converts the gains of a CPUScalingPolicy from percent, using the defaults for unset gains
+func newPIDOpts(gains *powerv1alpha1.PIDGains) *scaling.PIDOpts {
+	proportional, integral, derivative := 50, 10, 0
+	if gains != nil {
+		if gains.ProportionalGain != nil {
+			proportional = *gains.ProportionalGain
+		}
+		if gains.IntegralGain != nil {
+			integral = *gains.IntegralGain
+		}
+		if gains.DerivativeGain != nil {
+			derivative = *gains.DerivativeGain
+		}
+	}
+	return &scaling.PIDOpts{
+		ProportionalGain: float64(proportional) / 100.0,
+		IntegralGain:     float64(integral) / 100.0,
+		DerivativeGain:   float64(derivative) / 100.0,
+	}
+}
+
 func (r *PowerPodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Register a field index on Pod.spec.nodeName so that profileToPodRequests
 	// can efficiently list pods on this node when a profile's CPUScalingPolicy changes.
@@ -687,11 +1117,23 @@ func (r *PowerPodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}); err != nil {
 		return fmt.Errorf("failed to create pod node name field index: %w", err)
 	}
+	if r.CPUScalingManager != nil && r.ScalingStatusInterval > 0 {
+		if err := mgr.Add(manager.RunnableFunc(r.reportScalingStatus)); err != nil {
+			return fmt.Errorf("failed to add the scaling status reporter: %w", err)
+		}
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{},
-			builder.WithPredicates(
-				predicate.NewPredicateFuncs(PowerReleventPodPredicate))).
+			builder.WithPredicates(predicate.Or(
+				predicate.NewPredicateFuncs(PowerReleventPodPredicate),
+				// Pods whose uclamp annotations were removed have their uclamp values reset.
+				predicate.Funcs{
+					UpdateFunc:  func(e event.UpdateEvent) bool { return PowerReleventPodPredicate(e.ObjectOld) },
+					CreateFunc:  func(e event.CreateEvent) bool { return false },
+					GenericFunc: func(ge event.GenericEvent) bool { return false },
+					DeleteFunc:  func(de event.DeleteEvent) bool { return false },
+				}))).
 		Watches(&powerv1alpha1.PowerProfile{},
 			handler.EnqueueRequestsFromMapFunc(r.powerProfileToPodRequests),
 			builder.WithPredicates(predicate.Funcs{
@@ -699,6 +1141,10 @@ func (r *PowerPodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					oldProfile := e.ObjectOld.(*powerv1alpha1.PowerProfile)
 					newProfile := e.ObjectNew.(*powerv1alpha1.PowerProfile)
 
+					// Shared pool pods may reference any profile for uclamp values.
+					if !reflect.DeepEqual(oldProfile.Spec.UClamp, newProfile.Spec.UClamp) {
+						return true
+					}
 					if newProfile.Spec.Shared {
 						return false
 					}
@@ -714,7 +1160,8 @@ func (r *PowerPodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // powerProfileToPodRequests returns reconcile requests for all pods on this node
 // that use the given PowerProfile. This re-reconciles their DPDK scaling
-// when a profile's CPUScalingPolicy changes.
+// when a profile's CPUScalingPolicy changes, and their uclamp values when a
+// profile's UClamp changes.
 func (r *PowerPodReconciler) powerProfileToPodRequests(ctx context.Context, obj client.Object) []reconcile.Request {
 	requests := []reconcile.Request{}
 
@@ -731,6 +1178,15 @@ func (r *PowerPodReconciler) powerProfileToPodRequests(ctx context.Context, obj
 
 	profileName := obj.GetName()
 	for _, pod := range podList.Items {
+		if pod.Annotations[UClampProfileAnnotation] == profileName {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      pod.Name,
+					Namespace: pod.Namespace,
+				},
+			})
+			continue
+		}
 		for _, c := range allPodContainers(&pod) {
 			for rn := range c.Resources.Requests {
 				if string(rn) == ResourcePrefix+profileName {
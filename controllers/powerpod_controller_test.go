@@ -24,6 +24,7 @@ import (
 
 	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
 	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+	"github.com/cluster-power-manager/cluster-power-manager/pkg/cgroup"
 	"github.com/cluster-power-manager/cluster-power-manager/pkg/podresourcesclient"
 	"github.com/cluster-power-manager/cluster-power-manager/pkg/podstate"
 	"github.com/go-logr/logr"
@@ -2061,6 +2062,15 @@ func TestPowerReleventPodPredicate(t *testing.T) {
 			}(),
 			want: true,
 		},
+		{
+			name: "uclamp annotation without power requests returns true",
+			obj: func() client.Object {
+				pod := makePod(PowerNamespace, "TestNode", nil, cpuMemReq, false)
+				pod.Annotations = map[string]string{UClampMaxAnnotation: "50"}
+				return pod
+			}(),
+			want: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -2252,15 +2262,16 @@ func TestPowerPod_DetectCoresAdded(t *testing.T) {
 }
 
 func TestPowerPod_generateCPUScalingOpts(t *testing.T) {
-	minFreq := uint(1000000)
-	maxFreq := uint(3700000)
+	// effective limits of a profile narrowing the P-states of the CPUs
+	minFreq := uint(1500000)
+	maxFreq := uint(3000000)
 
 	// Build a CpuList with mock CPUs 0, 1, 2.
 	cpuList := make(power.CpuList, 3)
 	for i, id := range []uint{0, 1, 2} {
 		cpu := new(coreMock)
 		cpu.On("GetID").Return(id)
-		cpu.On("GetAbsMinMax").Return(minFreq, maxFreq)
+		cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(minFreq, maxFreq)
 		cpuList[i] = cpu
 	}
 
@@ -2302,8 +2313,9 @@ func TestPowerPod_generateCPUScalingOpts(t *testing.T) {
 			mockHost.On("GetAllCpus").Return(&cpuList)
 
 			r := &PowerPodReconciler{PowerLibrary: mockHost}
+			usageSource := new(DPDKTelemetryClientMock)
 
-			opts, err := r.generateCPUScalingOpts(policy, tc.cpuIDs)
+			opts, err := r.generateCPUScalingOpts(policy, tc.cpuIDs, usageSource)
 
 			if tc.expectError {
 				require.Error(t, err)
@@ -2323,6 +2335,7 @@ func TestPowerPod_generateCPUScalingOpts(t *testing.T) {
 
 			// Verify scaling parameters on returned opts.
 			for _, o := range opts {
+				assert.Same(t, usageSource, o.UsageSource)
 				assert.Equal(t, 10*time.Millisecond, o.SamplePeriod)
 				assert.Equal(t, 30*time.Millisecond, o.CooldownPeriod)
 				assert.Equal(t, 80, o.TargetUsage)
@@ -2331,13 +2344,223 @@ func TestPowerPod_generateCPUScalingOpts(t *testing.T) {
 				assert.Equal(t, 1.0, o.ScaleFactor)
 				assert.Equal(t, scaling.FrequencyNotYetSet, o.CurrentTargetFrequency)
 
+				// the scaling range is the effective range of the profile's P-states
+				assert.Equal(t, int(minFreq), o.MinFrequency)
+				assert.Equal(t, int(maxFreq), o.MaxFrequency)
 				expectedFallback := minFreq + (maxFreq-minFreq)*50/100
 				assert.Equal(t, int(expectedFallback), o.FallbackFreq)
+				assert.Equal(t, 50, o.FallbackFreqPercent)
 			}
 		})
 	}
 }
 
+func TestPowerPod_generateCPUScalingOpts_pid(t *testing.T) {
+	cpuList := make(power.CpuList, 2)
+	for i := range cpuList {
+		cpu := new(coreMock)
+		cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(uint(1000000), uint(3700000))
+		cpuList[i] = cpu
+	}
+	cpuList[0].(*coreMock).On("GetID").Return(uint(0))
+	cpuList[1].(*coreMock).On("GetID").Return(uint(1))
+	mockHost := new(hostMock)
+	mockHost.On("GetAllCpus").Return(&cpuList)
+	r := &PowerPodReconciler{PowerLibrary: mockHost}
+
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+		TargetUsage:                intPtr(80),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(50),
+		ScalePercentage:            intPtr(100),
+		Controller:                 "proportional",
+	}
+	opts, err := r.generateCPUScalingOpts(policy, []uint{0, 1}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 2)
+	assert.Nil(t, opts[0].PID)
+
+	// unset gains use the defaults
+	policy.Controller = ScalingControllerPID
+	policy.PID = &powerv1alpha1.PIDGains{IntegralGain: intPtr(20)}
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0, 1}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 2)
+	assert.Equal(t, &scaling.PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2}, opts[0].PID)
+	// each CPU keeps its own controller state
+	assert.NotSame(t, opts[0].PID, opts[1].PID)
+
+	policy.PID = nil
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &scaling.PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.1}, opts[0].PID)
+}
+
+func TestPowerPod_generateCPUScalingOpts_actuator(t *testing.T) {
+	cpu := new(coreMock)
+	cpu.On("GetID").Return(uint(0))
+	cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(uint(1000000), uint(3700000))
+	cpuList := power.CpuList{cpu}
+	mockHost := new(hostMock)
+	mockHost.On("GetAllCpus").Return(&cpuList)
+	r := &PowerPodReconciler{PowerLibrary: mockHost}
+
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+		TargetUsage:                intPtr(80),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(50),
+		ScalePercentage:            intPtr(100),
+	}
+	for actuator, expected := range map[string]scaling.Actuator{
+		"setspeed":             scaling.ActuatorSetSpeed,
+		ScalingActuatorMaxFreq: scaling.ActuatorMaxFreq,
+		ScalingActuatorEPP:     scaling.ActuatorEPP,
+	} {
+		policy.Actuator = actuator
+		opts, err := r.generateCPUScalingOpts(policy, []uint{0}, nil)
+		assert.NoError(t, err)
+		require.Len(t, opts, 1)
+		assert.Equal(t, expected, opts[0].Actuator, actuator)
+	}
+}
+
+func TestPowerPod_generateCPUScalingOpts_asymmetric(t *testing.T) {
+	cpu := new(coreMock)
+	cpu.On("GetID").Return(uint(0))
+	cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(uint(1000000), uint(3700000))
+	cpuList := power.CpuList{cpu}
+	mockHost := new(hostMock)
+	mockHost.On("GetAllCpus").Return(&cpuList)
+	r := &PowerPodReconciler{PowerLibrary: mockHost}
+
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+		TargetUsage:                intPtr(80),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(50),
+		ScalePercentage:            intPtr(50),
+	}
+	// symmetric scaling without the asymmetric settings
+	opts, err := r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, 0.5, opts[0].ScaleFactor)
+	assert.Zero(t, opts[0].ScaleDownFactor)
+	assert.Zero(t, opts[0].MaxFrequencyStep)
+	assert.Zero(t, opts[0].ScaleDownCooldownPeriod)
+	assert.Zero(t, opts[0].BurstUsage)
+
+	policy.ScaleUpPercentage = intPtr(150)
+	policy.ScaleDownPercentage = intPtr(20)
+	policy.MaxFrequencyStep = intPtr(200)
+	policy.ScaleDownCooldownPeriod = &metav1.Duration{Duration: 100 * time.Millisecond}
+	policy.BurstUsageThreshold = intPtr(95)
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, 1.5, opts[0].ScaleFactor)
+	assert.Equal(t, 0.2, opts[0].ScaleDownFactor)
+	assert.Equal(t, 200000, opts[0].MaxFrequencyStep)
+	assert.Equal(t, 100*time.Millisecond, opts[0].ScaleDownCooldownPeriod)
+	assert.Equal(t, 95, opts[0].BurstUsage)
+}
+
+func TestPowerPod_generateCPUScalingOpts_adaptiveGain(t *testing.T) {
+	cpu := new(coreMock)
+	cpu.On("GetID").Return(uint(0))
+	cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(uint(1000000), uint(3700000))
+	cpuList := power.CpuList{cpu}
+	mockHost := new(hostMock)
+	mockHost.On("GetAllCpus").Return(&cpuList)
+	r := &PowerPodReconciler{PowerLibrary: mockHost}
+
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+		TargetUsage:                intPtr(80),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(50),
+		ScalePercentage:            intPtr(50),
+	}
+	opts, err := r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Nil(t, opts[0].AdaptiveGain)
+
+	// defaults apply to unset fields
+	policy.AdaptiveGain = &powerv1alpha1.AdaptiveGain{}
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, &scaling.AdaptiveGainOpts{Window: time.Second, MaxReversals: 3, MinGain: 0.25, MaxGain: 2}, opts[0].AdaptiveGain)
+
+	policy.AdaptiveGain = &powerv1alpha1.AdaptiveGain{
+		Window:         &metav1.Duration{Duration: 500 * time.Millisecond},
+		MaxReversals:   intPtr(4),
+		MinGainPercent: intPtr(50),
+		MaxGainPercent: intPtr(150),
+	}
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, &scaling.AdaptiveGainOpts{Window: 500 * time.Millisecond, MaxReversals: 4, MinGain: 0.5, MaxGain: 1.5}, opts[0].AdaptiveGain)
+}
+
+func TestPowerPod_generateCPUScalingOpts_idleCStates(t *testing.T) {
+	cpu := new(coreMock)
+	cpu.On("GetID").Return(uint(0))
+	cpu.On("GetFrequencyLimitsExcluding", power.ScalerRequester).Return(uint(1000000), uint(3700000))
+	cpuList := power.CpuList{cpu}
+	mockHost := new(hostMock)
+	mockHost.On("GetAllCpus").Return(&cpuList)
+	r := &PowerPodReconciler{PowerLibrary: mockHost}
+
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		SamplePeriod:               &metav1.Duration{Duration: 10 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 30 * time.Millisecond},
+		TargetUsage:                intPtr(80),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(50),
+		ScalePercentage:            intPtr(50),
+	}
+	opts, err := r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Nil(t, opts[0].IdleCStates)
+
+	// defaults apply to unset fields
+	policy.IdleCStates = &powerv1alpha1.IdleCStates{Names: map[string]bool{"C6": true}}
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, &scaling.IdleCStatesOpts{UsageThreshold: 10, IdlePeriod: time.Second, States: map[string]bool{"C6": true}},
+		opts[0].IdleCStates)
+
+	policy.IdleCStates = &powerv1alpha1.IdleCStates{
+		UsageThreshold: intPtr(20),
+		IdlePeriod:     &metav1.Duration{Duration: 500 * time.Millisecond},
+		Names:          map[string]bool{"C1E": true, "C6": false},
+	}
+	opts, err = r.generateCPUScalingOpts(policy, []uint{0}, nil)
+	assert.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, &scaling.IdleCStatesOpts{
+		UsageThreshold: 20,
+		IdlePeriod:     500 * time.Millisecond,
+		States:         map[string]bool{"C1E": true, "C6": false},
+	}, opts[0].IdleCStates)
+}
+
 func TestPowerPod_Reconcile_WithCPUScalingPolicy(t *testing.T) {
 	testNode := "TestNode"
 	t.Setenv("NODE_NAME", testNode)
@@ -2474,8 +2697,9 @@ func TestPowerPod_Reconcile_WithCPUScalingPolicy(t *testing.T) {
 
 			dpdkmk := new(DPDKTelemetryClientMock)
 			dpdkmk.On("EnsureConnection", &scaling.DPDKTelemetryConnectionData{
-				PodUID:      tc.podUID,
-				WatchedCPUs: tc.cpuIDs,
+				PodUID:        tc.podUID,
+				ContainerName: "dpdk-container",
+				WatchedCPUs:   tc.cpuIDs,
 			}).Return().Once()
 			r.DPDKTelemetryClient = dpdkmk
 
@@ -2509,15 +2733,16 @@ func TestPowerPod_Reconcile_WithCPUScalingPolicy(t *testing.T) {
 				assert.Equal(t, float64(tc.scalePct)/100, actualScalingOpts[0].ScaleFactor)
 
 				cpu := host.GetAllCpus().ByID(tc.cpuIDs[0])
-				minFreq, maxFreq := cpu.GetAbsMinMax()
+				minFreq, maxFreq := cpu.GetFrequencyLimitsExcluding(power.ScalerRequester)
 				fallbackFreq := minFreq + (maxFreq-minFreq)*(uint(tc.fallbackPct))/100
 				assert.Equal(t, int(fallbackFreq), actualScalingOpts[0].FallbackFreq)
+				assert.Equal(t, tc.fallbackPct, actualScalingOpts[0].FallbackFreqPercent)
 			}
 		})
 	}
 }
 
-func TestPowerPod_Reconcile_MultipleDPDKContainersRejected(t *testing.T) {
+func TestPowerPod_Reconcile_MultipleDPDKContainers(t *testing.T) {
 	testNode := "TestNode"
 	t.Setenv("NODE_NAME", testNode)
 
@@ -2607,24 +2832,33 @@ func TestPowerPod_Reconcile_MultipleDPDKContainersRejected(t *testing.T) {
 	assert.NoError(t, err)
 	r.PowerLibrary = host
 
-	// Only one EnsureConnection and one AddCPUScaling call expected (for the first container).
+	// Each container gets its CPUs watched and scaled.
 	dpdkmk := new(DPDKTelemetryClientMock)
 	dpdkmk.On("EnsureConnection", &scaling.DPDKTelemetryConnectionData{
-		PodUID:      "multi-dpdk-uid",
-		WatchedCPUs: []uint{0, 1},
+		PodUID:        "multi-dpdk-uid",
+		ContainerName: "dpdk-container-1",
+		WatchedCPUs:   []uint{0, 1},
+	}).Return().Once()
+	dpdkmk.On("EnsureConnection", &scaling.DPDKTelemetryConnectionData{
+		PodUID:        "multi-dpdk-uid",
+		ContainerName: "dpdk-container-2",
+		WatchedCPUs:   []uint{2, 3},
 	}).Return().Once()
 	r.DPDKTelemetryClient = dpdkmk
 
+	scaledCPUs := func(expected []uint) interface{} {
+		return mock.MatchedBy(func(opts []scaling.CPUScalingOpts) bool {
+			ids := make([]uint, 0, len(opts))
+			for _, o := range opts {
+				ids = append(ids, o.CPU.GetID())
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			return reflect.DeepEqual(ids, expected)
+		})
+	}
 	scalingMgrMock := new(ScalingMgrMock)
-	scalingMgrMock.On("AddCPUScaling", mock.MatchedBy(func(opts []scaling.CPUScalingOpts) bool {
-		// Only the first container's CPUs (0, 1) should be scaled.
-		if len(opts) != 2 {
-			return false
-		}
-		ids := []uint{opts[0].CPU.GetID(), opts[1].CPU.GetID()}
-		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
-		return reflect.DeepEqual(ids, []uint{0, 1})
-	})).Return().Once()
+	scalingMgrMock.On("AddCPUScaling", scaledCPUs([]uint{0, 1})).Return().Once()
+	scalingMgrMock.On("AddCPUScaling", scaledCPUs([]uint{2, 3})).Return().Once()
 	r.CPUScalingManager = scalingMgrMock
 
 	_, err = r.Reconcile(context.TODO(), reconcile.Request{
@@ -2635,7 +2869,7 @@ func TestPowerPod_Reconcile_MultipleDPDKContainersRejected(t *testing.T) {
 	dpdkmk.AssertExpectations(t)
 	scalingMgrMock.AssertExpectations(t)
 
-	// Verify the second container has an error in PowerNodeState.
+	// Verify neither container reports an error in PowerNodeState.
 	pns := &powerv1alpha1.PowerNodeState{}
 	err = r.Client.Get(context.TODO(), client.ObjectKey{
 		Name:      testNode + "-power-state",
@@ -2647,14 +2881,540 @@ func TestPowerPod_Reconcile_MultipleDPDKContainersRejected(t *testing.T) {
 	require.Len(t, pns.Status.CPUPools.Exclusive, 1)
 	containers := pns.Status.CPUPools.Exclusive[0].PowerContainers
 	require.Len(t, containers, 2)
-
-	// Find the second container and verify it has the expected error.
 	for _, pc := range containers {
-		if pc.Name == "dpdk-container-2" {
-			require.Len(t, pc.Errors, 1)
-			assert.Contains(t, pc.Errors[0], "DPDK dynamic frequency scaling is only supported for a single container per pod")
-		} else {
-			assert.Empty(t, pc.Errors, "first DPDK container should have no errors")
+		assert.Empty(t, pc.Errors, "DPDK container %s should have no errors", pc.Name)
+	}
+}
+
+func TestPowerPod_Reconcile_CPUUtilisationScaling(t *testing.T) {
+	testNode := "TestNode"
+	t.Setenv("NODE_NAME", testNode)
+
+	profileName := "latency-profile"
+	policy := &powerv1alpha1.CPUScalingPolicy{
+		WorkloadType:               WorkloadTypeCPUUtilisation,
+		SamplePeriod:               &metav1.Duration{Duration: 100 * time.Millisecond},
+		CooldownPeriod:             &metav1.Duration{Duration: 200 * time.Millisecond},
+		TargetUsage:                intPtr(60),
+		AllowedUsageDifference:     intPtr(5),
+		AllowedFrequencyDifference: intPtr(25),
+		FallbackFreqPercent:        intPtr(100),
+		ScalePercentage:            intPtr(100),
+	}
+	profile := &powerv1alpha1.PowerProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: profileName, Namespace: PowerNamespace},
+		Spec:       powerv1alpha1.PowerProfileSpec{CPUScalingPolicy: policy},
+	}
+
+	profileResourceName := corev1.ResourceName(ResourcePrefix + profileName)
+	resourceList := corev1.ResourceList{
+		corev1.ResourceCPU:  *resource.NewQuantity(2, resource.DecimalSI),
+		"memory":            *resource.NewQuantity(200, resource.DecimalSI),
+		profileResourceName: *resource.NewQuantity(2, resource.DecimalSI),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "latency-pod",
+			Namespace: PowerNamespace,
+			UID:       "latency-uid",
+			// keeps the deleted pod around for the second reconcile
+			Finalizers: []string{"test"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: testNode,
+			Containers: []corev1.Container{
+				{Name: "app", Resources: corev1.ResourceRequirements{Limits: resourceList, Requests: resourceList}},
+			},
+			EphemeralContainers: []corev1.EphemeralContainer{},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSGuaranteed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ContainerID: "docker://app"},
+			},
+		},
+	}
+
+	podResourcesClient := createFakePodResourcesListerClient([]*podresourcesapi.PodResources{{
+		Name:      "latency-pod",
+		Namespace: PowerNamespace,
+		Containers: []*podresourcesapi.ContainerResources{
+			{Name: "app", CpuIds: []int64{2, 3}},
+		},
+	}})
+
+	host, teardown, err := fullDummySystem()
+	assert.NoError(t, err)
+	t.Cleanup(teardown)
+	assert.NoError(t, host.GetSharedPool().SetCpuIDs([]uint{0, 1, 2, 3}))
+	pool, err := host.AddExclusivePool(profileName)
+	assert.NoError(t, err)
+	assert.NoError(t, pool.SetCpuIDs([]uint{2, 3}))
+
+	r, err := createPodReconcilerObject(
+		[]runtime.Object{
+			profile, pod, defaultPowerNodeState,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		},
+		podResourcesClient,
+	)
+	assert.NoError(t, err)
+	r.PowerLibrary = host
+
+	// The usage of the container's CPUs is sampled from /proc/stat, DPDK telemetry is not used.
+	dpdkmk := new(DPDKTelemetryClientMock)
+	r.DPDKTelemetryClient = dpdkmk
+	usagemk := new(CPUUsageClientMock)
+	usagemk.On("EnsureConnection", &scaling.CPUUsageConnectionData{
+		PodUID:        "latency-uid",
+		ContainerName: "app",
+		WatchedCPUs:   []uint{2, 3},
+	}).Return().Once()
+	r.CPUUsageClient = usagemk
+
+	scalingMgrMock := new(ScalingMgrMock)
+	scalingMgrMock.On("AddCPUScaling", mock.MatchedBy(func(opts []scaling.CPUScalingOpts) bool {
+		for _, o := range opts {
+			if o.UsageSource != usagemk || o.TargetUsage != 60 {
+				return false
+			}
 		}
+		return len(opts) == 2
+	})).Return().Once()
+	r.CPUScalingManager = scalingMgrMock
+
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: "latency-pod", Namespace: PowerNamespace}}
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	dpdkmk.AssertExpectations(t)
+	usagemk.AssertExpectations(t)
+	scalingMgrMock.AssertExpectations(t)
+
+	// Deleting the pod stops sampling and scaling of its CPUs.
+	dpdkmk.On("CloseConnection", "latency-uid").Return().Once()
+	usagemk.On("CloseConnection", "latency-uid").Return().Once()
+	scalingMgrMock.On("RemoveCPUScaling", []uint{2, 3}).Return().Once()
+	assert.NoError(t, r.Client.Delete(context.TODO(), pod))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	dpdkmk.AssertExpectations(t)
+	usagemk.AssertExpectations(t)
+	scalingMgrMock.AssertExpectations(t)
+}
+
+func TestPowerPod_Reconcile_EPPActuatorUnsupported(t *testing.T) {
+	testNode := "TestNode"
+	t.Setenv("NODE_NAME", testNode)
+
+	profileName := "epp-profile"
+	profile := &powerv1alpha1.PowerProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: profileName, Namespace: PowerNamespace},
+		Spec: powerv1alpha1.PowerProfileSpec{CPUScalingPolicy: &powerv1alpha1.CPUScalingPolicy{
+			WorkloadType:               WorkloadTypeCPUUtilisation,
+			SamplePeriod:               &metav1.Duration{Duration: 100 * time.Millisecond},
+			CooldownPeriod:             &metav1.Duration{Duration: 200 * time.Millisecond},
+			TargetUsage:                intPtr(60),
+			AllowedUsageDifference:     intPtr(5),
+			AllowedFrequencyDifference: intPtr(25),
+			FallbackFreqPercent:        intPtr(100),
+			ScalePercentage:            intPtr(100),
+			Actuator:                   ScalingActuatorEPP,
+		}},
+	}
+
+	profileResourceName := corev1.ResourceName(ResourcePrefix + profileName)
+	resourceList := corev1.ResourceList{
+		corev1.ResourceCPU:  *resource.NewQuantity(2, resource.DecimalSI),
+		"memory":            *resource.NewQuantity(200, resource.DecimalSI),
+		profileResourceName: *resource.NewQuantity(2, resource.DecimalSI),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "epp-pod", Namespace: PowerNamespace, UID: "epp-uid"},
+		Spec: corev1.PodSpec{
+			NodeName: testNode,
+			Containers: []corev1.Container{
+				{Name: "app", Resources: corev1.ResourceRequirements{Limits: resourceList, Requests: resourceList}},
+			},
+			EphemeralContainers: []corev1.EphemeralContainer{},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSGuaranteed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ContainerID: "docker://app"},
+			},
+		},
+	}
+	podResourcesClient := createFakePodResourcesListerClient([]*podresourcesapi.PodResources{{
+		Name:      "epp-pod",
+		Namespace: PowerNamespace,
+		Containers: []*podresourcesapi.ContainerResources{
+			{Name: "app", CpuIds: []int64{2, 3}},
+		},
+	}})
+
+	// the driver does not expose energy_performance_preference
+	host, teardown, err := setupDummyFiles(4, 1, 2, map[string]string{
+		"driver": "acpi-cpufreq", "max": "3700000", "min": "1000000", "governor": "powersave",
+		"available_governors": "powersave performance userspace",
+	})
+	assert.ErrorContains(t, err, "EPP file")
+	require.NotNil(t, host)
+	t.Cleanup(teardown)
+	assert.NoError(t, host.GetSharedPool().SetCpuIDs([]uint{0, 1, 2, 3}))
+	_, err = host.AddExclusivePool(profileName)
+	assert.NoError(t, err)
+
+	r, err := createPodReconcilerObject(
+		[]runtime.Object{
+			profile, pod, defaultPowerNodeState,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}},
+		},
+		podResourcesClient,
+	)
+	assert.NoError(t, err)
+	r.PowerLibrary = host
+	usagemk := new(CPUUsageClientMock)
+	r.CPUUsageClient = usagemk
+	scalingMgrMock := new(ScalingMgrMock)
+	r.CPUScalingManager = scalingMgrMock
+
+	_, err = r.Reconcile(context.TODO(), reconcile.Request{
+		NamespacedName: client.ObjectKey{Name: "epp-pod", Namespace: PowerNamespace},
+	})
+	assert.NoError(t, err)
+
+	// the CPUs are not scaled and the container reports the error
+	usagemk.AssertNotCalled(t, "EnsureConnection", mock.Anything)
+	scalingMgrMock.AssertNotCalled(t, "AddCPUScaling", mock.Anything)
+	powerNodeState := &powerv1alpha1.PowerNodeState{}
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{
+		Name:      testNode + "-power-state",
+		Namespace: PowerNamespace,
+	}, powerNodeState))
+	require.NotNil(t, powerNodeState.Status.CPUPools)
+	require.Len(t, powerNodeState.Status.CPUPools.Exclusive, 1)
+	containers := powerNodeState.Status.CPUPools.Exclusive[0].PowerContainers
+	require.Len(t, containers, 1)
+	assert.ElementsMatch(t, []uint{2, 3}, containers[0].CPUIDs)
+	require.Len(t, containers[0].Errors, 1)
+	assert.Contains(t, containers[0].Errors[0], "cannot scale with the epp actuator")
+}
+
+func TestPowerPod_resolveUClamp(t *testing.T) {
+	uclampProfile := &powerv1alpha1.PowerProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "background", Namespace: PowerNamespace},
+		Spec: powerv1alpha1.PowerProfileSpec{
+			Shared: true,
+			UClamp: &powerv1alpha1.UClampConfig{Max: intPtr(40)},
+		},
+	}
+	tcases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedUClamp cgroup.UClamp
+		errContains    string
+	}{
+		{
+			name:           "profile values",
+			annotations:    map[string]string{UClampProfileAnnotation: "background"},
+			expectedUClamp: cgroup.UClamp{Min: 0, Max: 40},
+		},
+		{
+			name:           "annotations override profile",
+			annotations:    map[string]string{UClampProfileAnnotation: "background", UClampMinAnnotation: "10", UClampMaxAnnotation: "60"},
+			expectedUClamp: cgroup.UClamp{Min: 10, Max: 60},
+		},
+		{
+			name:           "annotations only",
+			annotations:    map[string]string{UClampMinAnnotation: "30"},
+			expectedUClamp: cgroup.UClamp{Min: 30, Max: 100},
+		},
+		{
+			name:        "profile not found",
+			annotations: map[string]string{UClampProfileAnnotation: "missing"},
+			errContains: "PowerProfile 'missing' not found",
+		},
+		{
+			name:        "profile without uclamp",
+			annotations: map[string]string{UClampProfileAnnotation: "performance"},
+			errContains: "has no uclamp configuration",
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{UClampMaxAnnotation: "150"},
+			errContains: "must be a percentage between 0 and 100",
+		},
+		{
+			name:        "min above max",
+			annotations: map[string]string{UClampProfileAnnotation: "background", UClampMinAnnotation: "50"},
+			errContains: "uclamp min 50 is greater than max 40",
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := createPodReconcilerObject([]runtime.Object{uclampProfile, defaultProfile}, createFakePodResourcesListerClient(nil))
+			require.NoError(t, err)
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: PowerNamespace, Annotations: tc.annotations}}
+			uclamp, err := r.resolveUClamp(context.TODO(), pod)
+			if tc.errContains != "" {
+				assert.ErrorContains(t, err, tc.errContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUClamp, uclamp)
+		})
+	}
+}
+
+func TestPowerPod_dpdkTelemetrySocketPath(t *testing.T) {
+	tcases := []struct {
+		name         string
+		annotations  map[string]string
+		expectedPath string
+		errContains  string
+	}{
+		{
+			name: "discovered",
+		},
+		{
+			name:         "socket path",
+			annotations:  map[string]string{DPDKTelemetrySocketAnnotation: "app/./dpdk_telemetry.v2"},
+			expectedPath: "app/dpdk_telemetry.v2",
+		},
+		{
+			name:         "file prefix",
+			annotations:  map[string]string{DPDKFilePrefixAnnotation: "server"},
+			expectedPath: "server/dpdk_telemetry.v2",
+		},
+		{
+			name:        "socket path outside of the pod's directory",
+			annotations: map[string]string{DPDKTelemetrySocketAnnotation: "../other-pod/dpdk/rte/dpdk_telemetry.v2"},
+			errContains: "must be a relative path within the pod's DPDK directory",
+		},
+		{
+			name:        "file prefix with a path",
+			annotations: map[string]string{DPDKFilePrefixAnnotation: "app/rte"},
+			errContains: "must be a single directory name",
+		},
+		{
+			name:        "both annotations",
+			annotations: map[string]string{DPDKTelemetrySocketAnnotation: "app/dpdk_telemetry.v2", DPDKFilePrefixAnnotation: "app"},
+			errContains: "are mutually exclusive",
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: PowerNamespace, Annotations: tc.annotations}}
+			socketPath, err := dpdkTelemetrySocketPath(pod)
+			if tc.errContains != "" {
+				assert.ErrorContains(t, err, tc.errContains)
+				assert.ErrorIs(t, err, power.ErrInvalidValue)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, socketPath)
+		})
+	}
+}
+
+func TestPowerPod_Reconcile_UClamp(t *testing.T) {
+	testNode := "TestNode"
+	t.Setenv("NODE_NAME", testNode)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared-pod",
+			Namespace:   PowerNamespace,
+			UID:         "shared-uid",
+			Annotations: map[string]string{UClampMinAnnotation: "20", UClampMaxAnnotation: "70"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: testNode,
+			Containers: []corev1.Container{
+				{Name: "app"},
+				{Name: "sidecar"},
+				{Name: "exclusive", Resources: defaultResources},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ContainerID: "containerd://app-id"},
+				{Name: "sidecar", ContainerID: "containerd://sidecar-id"},
+				{Name: "exclusive", ContainerID: "containerd://exclusive-id"},
+			},
+		},
+	}
+	r, err := createPodReconcilerObject(
+		[]runtime.Object{pod, defaultProfile, defaultPowerNodeState, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}}},
+		createFakePodResourcesListerClient(nil),
+	)
+	require.NoError(t, err)
+
+	// the exclusive container is not in the shared pool and must not be clamped
+	writer := new(UClampWriterMock)
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 20, Max: 70}).Return(nil).Once()
+	writer.On("SetContainerUClamp", "shared-uid", "sidecar-id", cgroup.UClamp{Min: 20, Max: 70}).Return(assert.AnError).Once()
+	r.UClampWriter = writer
+
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: "shared-pod", Namespace: PowerNamespace}}
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.ErrorContains(t, err, "recoverable errors encountered")
+	writer.AssertExpectations(t)
+
+	pns := &powerv1alpha1.PowerNodeState{}
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	require.NotNil(t, pns.Status.CPUPools)
+	require.Len(t, pns.Status.CPUPools.UClamp, 1)
+	entry := pns.Status.CPUPools.UClamp[0]
+	assert.Equal(t, "shared-uid", entry.PodUID)
+	assert.Equal(t, 20, entry.Min)
+	assert.Equal(t, 70, entry.Max)
+	require.Len(t, entry.Containers, 2)
+	assert.Equal(t, "app-id", entry.Containers[0].ID)
+	assert.Empty(t, entry.Containers[0].Errors)
+	assert.Len(t, entry.Containers[1].Errors, 1)
+
+	// the entry is removed once the pod is deleted
+	pod.Status.Phase = corev1.PodSucceeded
+	require.NoError(t, r.Client.Status().Update(context.TODO(), pod))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	assert.Empty(t, pns.Status.CPUPools.UClamp)
+}
+
+func TestPowerPod_Reconcile_UClampUnsupported(t *testing.T) {
+	testNode := "TestNode"
+	t.Setenv("NODE_NAME", testNode)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared-pod",
+			Namespace:   PowerNamespace,
+			UID:         "shared-uid",
+			Annotations: map[string]string{UClampMaxAnnotation: "40"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   testNode,
+			Containers: []corev1.Container{{Name: "app"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			QOSClass:          corev1.PodQOSBestEffort,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", ContainerID: "containerd://app-id"}},
+		},
+	}
+	r, err := createPodReconcilerObject(
+		[]runtime.Object{pod, defaultPowerNodeState, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}}},
+		createFakePodResourcesListerClient(nil),
+	)
+	require.NoError(t, err)
+
+	// read-only pod cgroups are reported in the status without retrying
+	unsupported := fmt.Errorf("%w, the pod cgroups are mounted read-only", cgroup.ErrUnsupported)
+	writer := new(UClampWriterMock)
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 0, Max: 40}).Return(unsupported).Once()
+	r.UClampWriter = writer
+
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: "shared-pod", Namespace: PowerNamespace}}
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	pns := &powerv1alpha1.PowerNodeState{}
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	require.Len(t, pns.Status.CPUPools.UClamp, 1)
+	require.Len(t, pns.Status.CPUPools.UClamp[0].Containers, 1)
+	assert.Equal(t, []powerv1alpha1.StatusReason{powerv1alpha1.StatusReasonUnsupported}, pns.Status.CPUPools.UClamp[0].Containers[0].Reasons)
+
+	// nothing was written, removing the annotations only removes the entry
+	pod.Annotations = nil
+	require.NoError(t, r.Client.Update(context.TODO(), pod))
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 0, Max: 100}).Return(unsupported).Once()
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	writer.AssertExpectations(t)
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	assert.Empty(t, pns.Status.CPUPools.UClamp)
+}
+
+func TestPowerPod_Reconcile_UClampRemoved(t *testing.T) {
+	testNode := "TestNode"
+	t.Setenv("NODE_NAME", testNode)
+
+	cpuRequest := func(cpu string) corev1.ResourceRequirements {
+		resources := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		return corev1.ResourceRequirements{Requests: resources, Limits: resources}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared-pod",
+			Namespace:   PowerNamespace,
+			UID:         "shared-uid",
+			Annotations: map[string]string{UClampMaxAnnotation: "40"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: testNode,
+			Containers: []corev1.Container{
+				{Name: "app", Resources: cpuRequest("500m")},
+				{Name: "pinned", Resources: cpuRequest("2")},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSGuaranteed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ContainerID: "containerd://app-id"},
+				{Name: "pinned", ContainerID: "containerd://pinned-id"},
+			},
+		},
 	}
+	r, err := createPodReconcilerObject(
+		[]runtime.Object{pod, defaultPowerNodeState, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNode}}},
+		createFakePodResourcesListerClient(nil),
+	)
+	require.NoError(t, err)
+
+	// the pinned container has exclusive CPUs from the CPU manager and is not clamped
+	writer := new(UClampWriterMock)
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 0, Max: 40}).Return(nil).Once()
+	r.UClampWriter = writer
+
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: "shared-pod", Namespace: PowerNamespace}}
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	writer.AssertExpectations(t)
+	pns := &powerv1alpha1.PowerNodeState{}
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	require.Len(t, pns.Status.CPUPools.UClamp, 1)
+	require.Len(t, pns.Status.CPUPools.UClamp[0].Containers, 1)
+	assert.Equal(t, "app-id", pns.Status.CPUPools.UClamp[0].Containers[0].ID)
+
+	// removing the annotations resets the values, the entry is kept until the reset succeeds
+	pod.Annotations = nil
+	require.NoError(t, r.Client.Update(context.TODO(), pod))
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 0, Max: 100}).Return(assert.AnError).Once()
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.ErrorIs(t, err, assert.AnError)
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	assert.Len(t, pns.Status.CPUPools.UClamp, 1)
+
+	writer.On("SetContainerUClamp", "shared-uid", "app-id", cgroup.UClamp{Min: 0, Max: 100}).Return(nil).Once()
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	writer.AssertExpectations(t)
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: testNode + "-power-state", Namespace: PowerNamespace}, pns))
+	assert.Empty(t, pns.Status.CPUPools.UClamp)
+
+	// pods without uclamp values are left alone
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	writer.AssertNumberOfCalls(t, "SetContainerUClamp", 3)
 }
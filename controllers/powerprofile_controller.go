@@ -186,7 +186,7 @@ func (r *PowerProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		isValid := isValidEpp(profile.Spec.PStates.Epp)
 
 		if !isValid {
-			err = errors.NewServiceUnavailable(fmt.Sprintf("EPP value not allowed: %v", profile.Spec.PStates.Epp))
+			err = newStatusError(power.ErrInvalidValue, "%w", errors.NewServiceUnavailable(fmt.Sprintf("EPP value not allowed: %v", profile.Spec.PStates.Epp)))
 			logger.Error(err, "error reconciling the power profile")
 
 			return ctrl.Result{}, err
@@ -196,7 +196,7 @@ func (r *PowerProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Validate the EPP value.
 	actualEpp := profile.Spec.PStates.Epp
 	if !power.IsFeatureSupported(power.EPPFeature) && actualEpp != "" {
-		err = fmt.Errorf("EPP is not supported but %s provides one, setting EPP to ''", profile.Name)
+		err = newStatusError(power.ErrUnsupported, "EPP is not supported but %s provides one, setting EPP to ''", profile.Name)
 		logger.Error(err, "invalid EPP")
 		actualEpp = ""
 	}
@@ -205,7 +205,7 @@ func (r *PowerProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	powerProfile, err := power.NewPowerProfile(
 		profile.Name, profile.Spec.PStates.Min, profile.Spec.PStates.Max,
 		profile.Spec.PStates.Governor, actualEpp,
-		profile.Spec.CStates.Names, profile.Spec.CStates.MaxLatencyUs)
+		profile.Spec.CStates.Names, profile.Spec.CStates.MaxLatencyUs, profile.Spec.PStates.GovernorTunables)
 	if err != nil {
 		logger.Error(err, "could not create the power profile")
 		return ctrl.Result{}, err
@@ -1906,7 +1906,7 @@ func TestPowerProfile_Reconcile_ProfileUpdateAffectsAllPools(t *testing.T) {
 				}
 				initialProfile, err := power.NewPowerProfile(
 					profileName, &intstr.IntOrString{Type: intstr.Int, IntVal: 2000}, &intstr.IntOrString{Type: intstr.Int, IntVal: 3000}, "powersave", "power",
-					map[string]bool{"C0": true, "C1": true, "C1E": false, "C3": true}, nil)
+					map[string]bool{"C0": true, "C1": true, "C1E": false, "C3": true}, nil, nil)
 				assert.Nil(t, err)
 				err = sharedPool.SetPowerProfile(initialProfile)
 				assert.Nil(t, err)
@@ -1919,7 +1919,7 @@ func TestPowerProfile_Reconcile_ProfileUpdateAffectsAllPools(t *testing.T) {
 				assert.Nil(t, err)
 				initialProfile, err := power.NewPowerProfile(
 					tc.setupReservedPoolProfile, &intstr.IntOrString{Type: intstr.Int, IntVal: 2000}, &intstr.IntOrString{Type: intstr.Int, IntVal: 3000}, "powersave", "balance_performance",
-					map[string]bool{"C0": true, "C1": false, "C1E": false, "C3": false}, nil)
+					map[string]bool{"C0": true, "C1": false, "C1E": false, "C3": false}, nil, nil)
 				assert.Nil(t, err)
 				err = reservedPool.SetPowerProfile(initialProfile)
 				assert.Nil(t, err)
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
+	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+)
+
+// DefaultScalingStatusInterval is the interval at which the scaling status of containers is refreshed
+const DefaultScalingStatusInterval = 30 * time.Second
+
+// reportScalingStatus refreshes the scaling status of the containers in the PowerNodeState every
+// ScalingStatusInterval until the context is cancelled
+func (r *PowerPodReconciler) reportScalingStatus(ctx context.Context) error {
+	nodeName := os.Getenv("NODE_NAME")
+	logger := r.Log.WithName("scaling-status")
+	ticker := time.NewTicker(r.ScalingStatusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.refreshScalingStatus(ctx, nodeName); err != nil {
+				logger.Error(err, "failed to refresh the scaling status")
+			}
+		}
+	}
+}
+
+// refreshScalingStatus updates the scaling status of the exclusive containers of each pod in the PowerNodeState.
+// Entries are only written if their status changed, and are skipped if the PowerPod controller updated the
+// PowerNodeState in the meantime, to be refreshed at the next interval
+func (r *PowerPodReconciler) refreshScalingStatus(ctx context.Context, nodeName string) error {
+	powerNodeStateName := fmt.Sprintf("%s-power-state", nodeName)
+	nodeState := &powerv1alpha1.PowerNodeState{}
+	if err := r.Get(ctx, client.ObjectKey{Name: powerNodeStateName, Namespace: PowerNamespace}, nodeState); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if nodeState.Status.CPUPools == nil {
+		return nil
+	}
+
+	resourceVersion := nodeState.ResourceVersion
+	for _, entry := range nodeState.Status.CPUPools.Exclusive {
+		changed := false
+		containers := make([]powerv1alpha1.PowerContainer, len(entry.PowerContainers))
+		for i := range entry.PowerContainers {
+			entry.PowerContainers[i].DeepCopyInto(&containers[i])
+			status := r.containerScalingStatus(entry.PodUID, &containers[i])
+			if !equality.Semantic.DeepEqual(status, containers[i].Scaling) {
+				containers[i].Scaling = status
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		// the entry is applied by the field manager of the pod, which owns it as a whole
+		patchNodeState := newExclusiveStatusPatch(powerNodeStateName, []powerv1alpha1.ExclusiveCPUPoolStatus{{
+			PodUID:          entry.PodUID,
+			Pod:             entry.Pod,
+			PowerContainers: containers,
+		}})
+		patchNodeState.ResourceVersion = resourceVersion
+		fieldManager := fmt.Sprintf("powerpod-controller.%s", entry.PodUID)
+		if err := r.Status().Patch(ctx, patchNodeState, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+			if errors.IsConflict(err) {
+				r.Log.V(5).Info("PowerNodeState changed, scaling status is refreshed later", "podUID", entry.PodUID)
+				return nil
+			}
+			return err
+		}
+		resourceVersion = patchNodeState.ResourceVersion
+	}
+	return nil
+}
+
+// containerScalingStatus returns the scaling status of a container, or nil if its CPUs are not scaled
+func (r *PowerPodReconciler) containerScalingStatus(podUID string, container *powerv1alpha1.PowerContainer) *powerv1alpha1.ContainerScalingStatus {
+	if r.CPUScalingManager == nil {
+		return nil
+	}
+	connection, scaled := r.scalingConnectionState(podUID, container.Name)
+	if !scaled {
+		return nil
+	}
+
+	status := &powerv1alpha1.ContainerScalingStatus{Connection: connection}
+	for _, cpu := range r.CPUScalingManager.GetCPUScalingStatus(container.CPUIDs) {
+		cpuStatus := powerv1alpha1.CPUScalingStatus{
+			ID:               cpu.CPUID,
+			CurrentFrequency: cpu.CurrentFrequency,
+			Idle:             cpu.Idle,
+		}
+		if cpu.TargetFrequency != scaling.FrequencyNotYetSet {
+			cpuStatus.TargetFrequency = uint(cpu.TargetFrequency)
+		}
+		switch {
+		case cpu.UsageErr != nil:
+			status.LastError = cpu.UsageErr.Error()
+		case !cpu.LastUpdate.IsZero():
+			usage := cpu.Usage
+			cpuStatus.Usage = &usage
+		}
+		if !cpu.LastChange.IsZero() {
+			lastChange := metav1.NewTime(cpu.LastChange)
+			cpuStatus.LastChange = &lastChange
+		}
+		if cpu.GainPercent > 0 {
+			gainPercent := cpu.GainPercent
+			cpuStatus.GainPercent = &gainPercent
+		}
+		status.CPUs = append(status.CPUs, cpuStatus)
+	}
+	return status
+}
+
+// scalingConnectionState returns the state of the usage source sampling the CPUs of a container,
+// and whether the container is scaled at all
+func (r *PowerPodReconciler) scalingConnectionState(podUID, containerName string) (powerv1alpha1.ScalingConnectionState, bool) {
+	if r.DPDKTelemetryClient != nil {
+		for _, connection := range r.DPDKTelemetryClient.ListConnections() {
+			if connection.PodUID != podUID || connection.ContainerName != containerName {
+				continue
+			}
+			switch {
+			case connection.Degraded:
+				return powerv1alpha1.ScalingDegraded, true
+			case connection.Connected:
+				return powerv1alpha1.ScalingConnected, true
+			default:
+				return powerv1alpha1.ScalingDisconnected, true
+			}
+		}
+	}
+	if r.CPUUsageClient != nil {
+		for _, connection := range r.CPUUsageClient.ListConnections() {
+			// /proc/stat is always available
+			if connection.PodUID == podUID && connection.ContainerName == containerName {
+				return powerv1alpha1.ScalingConnected, true
+			}
+		}
+	}
+	return "", false
+}
+
+// keepScalingStatus carries the scaling status of the scaled containers of a pod over from the PowerNodeState,
+// so applying the pod's entry does not clear it until the next refresh
+func (r *PowerPodReconciler) keepScalingStatus(ctx context.Context, nodeName, podUID string, powerContainers []powerv1alpha1.PowerContainer, scaledContainers map[string]bool) {
+	if len(scaledContainers) == 0 {
+		return
+	}
+	nodeState := &powerv1alpha1.PowerNodeState{}
+	if err := r.Get(ctx, client.ObjectKey{Name: fmt.Sprintf("%s-power-state", nodeName), Namespace: PowerNamespace}, nodeState); err != nil {
+		return
+	}
+	if nodeState.Status.CPUPools == nil {
+		return
+	}
+	for _, entry := range nodeState.Status.CPUPools.Exclusive {
+		if entry.PodUID != podUID {
+			continue
+		}
+		for i := range powerContainers {
+			if !scaledContainers[powerContainers[i].Name] {
+				continue
+			}
+			for _, previous := range entry.PowerContainers {
+				if previous.Name == powerContainers[i].Name {
+					powerContainers[i].Scaling = previous.Scaling.DeepCopy()
+				}
+			}
+		}
+	}
+}
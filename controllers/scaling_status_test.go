@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	powerv1alpha1 "github.com/cluster-power-manager/cluster-power-manager/api/v1alpha1"
+	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+)
+
+func TestPowerPod_refreshScalingStatus(t *testing.T) {
+	testNode := "TestNode"
+	nodeState := &powerv1alpha1.PowerNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: testNode + "-power-state", Namespace: PowerNamespace},
+		Status: powerv1alpha1.PowerNodeStateStatus{
+			CPUPools: &powerv1alpha1.CPUPoolsStatus{
+				Exclusive: []powerv1alpha1.ExclusiveCPUPoolStatus{{
+					PodUID: "dpdk-uid",
+					Pod:    "dpdk-pod",
+					PowerContainers: []powerv1alpha1.PowerContainer{
+						{Name: "dpdk", ID: "dpdk-id", PowerProfile: "performance", CPUIDs: []uint{2, 3}},
+						{Name: "sidecar", ID: "sidecar-id", PowerProfile: "performance", CPUIDs: []uint{4}},
+						{Name: "app", ID: "app-id", PowerProfile: "performance", CPUIDs: []uint{5}},
+					},
+				}},
+			},
+		},
+	}
+	r, err := createPodReconcilerObject([]runtime.Object{nodeState}, createFakePodResourcesListerClient(nil))
+	require.NoError(t, err)
+
+	dpdkmk := new(DPDKTelemetryClientMock)
+	dpdkmk.On("ListConnections").Return([]scaling.DPDKTelemetryConnectionData{
+		{PodUID: "dpdk-uid", ContainerName: "dpdk", WatchedCPUs: []uint{2, 3}, Connected: true},
+	})
+	r.DPDKTelemetryClient = dpdkmk
+	usagemk := new(CPUUsageClientMock)
+	usagemk.On("ListConnections").Return([]scaling.CPUUsageConnectionData{
+		{PodUID: "dpdk-uid", ContainerName: "app", WatchedCPUs: []uint{5}},
+	})
+	r.CPUUsageClient = usagemk
+
+	lastChange := time.Unix(1000, 0)
+	scalingMgrMock := new(ScalingMgrMock)
+	scalingMgrMock.On("GetCPUScalingStatus", []uint{2, 3}).Return([]scaling.CPUScalingStatus{
+		{CPUID: 2, TargetFrequency: 2000000, CurrentFrequency: 1900000, Usage: 5, GainPercent: 50, LastChange: lastChange, LastUpdate: lastChange, Idle: true},
+		{CPUID: 3, TargetFrequency: scaling.FrequencyNotYetSet, CurrentFrequency: 1500000, UsageErr: scaling.ErrDPDKMetricStale, LastUpdate: lastChange},
+	})
+	// not updated yet
+	scalingMgrMock.On("GetCPUScalingStatus", []uint{5}).Return([]scaling.CPUScalingStatus{
+		{CPUID: 5, TargetFrequency: scaling.FrequencyNotYetSet, CurrentFrequency: 1500000},
+	})
+	r.CPUScalingManager = scalingMgrMock
+
+	require.NoError(t, r.refreshScalingStatus(context.TODO(), testNode))
+
+	updated := &powerv1alpha1.PowerNodeState{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(nodeState), updated))
+	require.Len(t, updated.Status.CPUPools.Exclusive, 1)
+	containers := map[string]powerv1alpha1.PowerContainer{}
+	for _, container := range updated.Status.CPUPools.Exclusive[0].PowerContainers {
+		containers[container.Name] = container
+	}
+	usage, gain := 5, 50
+	metaLastChange := metav1.NewTime(lastChange)
+	assert.Equal(t, &powerv1alpha1.ContainerScalingStatus{
+		Connection: powerv1alpha1.ScalingConnected,
+		LastError:  scaling.ErrDPDKMetricStale.Error(),
+		CPUs: []powerv1alpha1.CPUScalingStatus{
+			{ID: 2, TargetFrequency: 2000000, CurrentFrequency: 1900000, Usage: &usage, GainPercent: &gain, LastChange: &metaLastChange, Idle: true},
+			{ID: 3, CurrentFrequency: 1500000},
+		},
+	}, containers["dpdk"].Scaling)
+	assert.Nil(t, containers["sidecar"].Scaling)
+	assert.Equal(t, &powerv1alpha1.ContainerScalingStatus{
+		Connection: powerv1alpha1.ScalingConnected,
+		CPUs:       []powerv1alpha1.CPUScalingStatus{{ID: 5, CurrentFrequency: 1500000}},
+	}, containers["app"].Scaling)
+	assert.Equal(t, []uint{2, 3}, containers["dpdk"].CPUIDs)
+	assert.Equal(t, "dpdk-id", containers["dpdk"].ID)
+
+	// an unchanged status is not written again
+	require.NoError(t, r.refreshScalingStatus(context.TODO(), testNode))
+	unchanged := &powerv1alpha1.PowerNodeState{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(nodeState), unchanged))
+	assert.Equal(t, updated.ResourceVersion, unchanged.ResourceVersion)
+
+	// the state of the connection is reported
+	dpdkmk.ExpectedCalls = nil
+	dpdkmk.On("ListConnections").Return([]scaling.DPDKTelemetryConnectionData{
+		{PodUID: "dpdk-uid", ContainerName: "dpdk", WatchedCPUs: []uint{2, 3}, Connected: true, Degraded: true},
+	})
+	require.NoError(t, r.refreshScalingStatus(context.TODO(), testNode))
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(nodeState), updated))
+	for _, container := range updated.Status.CPUPools.Exclusive[0].PowerContainers {
+		if container.Name == "dpdk" {
+			assert.Equal(t, powerv1alpha1.ScalingDegraded, container.Scaling.Connection)
+		}
+	}
+}
+
+func TestPowerPod_keepScalingStatus(t *testing.T) {
+	testNode := "TestNode"
+	status := &powerv1alpha1.ContainerScalingStatus{Connection: powerv1alpha1.ScalingConnected}
+	nodeState := &powerv1alpha1.PowerNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: testNode + "-power-state", Namespace: PowerNamespace},
+		Status: powerv1alpha1.PowerNodeStateStatus{
+			CPUPools: &powerv1alpha1.CPUPoolsStatus{
+				Exclusive: []powerv1alpha1.ExclusiveCPUPoolStatus{{
+					PodUID: "dpdk-uid",
+					Pod:    "dpdk-pod",
+					PowerContainers: []powerv1alpha1.PowerContainer{
+						{Name: "dpdk", CPUIDs: []uint{2, 3}, Scaling: status},
+						{Name: "other", CPUIDs: []uint{4}, Scaling: status},
+					},
+				}},
+			},
+		},
+	}
+	r, err := createPodReconcilerObject([]runtime.Object{nodeState}, createFakePodResourcesListerClient(nil))
+	require.NoError(t, err)
+
+	// only containers still scaled keep their status
+	powerContainers := []powerv1alpha1.PowerContainer{
+		{Name: "dpdk", CPUIDs: []uint{2, 3}},
+		{Name: "other", CPUIDs: []uint{4}},
+	}
+	r.keepScalingStatus(context.TODO(), testNode, "dpdk-uid", powerContainers, map[string]bool{"dpdk": true})
+	assert.Equal(t, status, powerContainers[0].Scaling)
+	assert.Nil(t, powerContainers[1].Scaling)
+}
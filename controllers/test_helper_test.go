@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"context"
 
 	"github.com/cluster-power-manager/cluster-power-manager/internal/scaling"
+	"github.com/cluster-power-manager/cluster-power-manager/pkg/cgroup"
 	"github.com/go-logr/logr"
 	"github.com/intel/power-optimization-library/pkg/power"
 	"github.com/stretchr/testify/mock"
@@ -103,6 +105,19 @@ func (m *hostMock) GetAllCpus() *power.CpuList {
 	}
 }
 
+func (m *hostMock) SetIdleInjection(pool power.Pool, idlePercent uint) error {
+	return m.Called(pool, idlePercent).Error(0)
+}
+
+func (m *hostMock) GetIdleInjection() (power.Pool, uint) {
+	args := m.Called()
+	retPool := args.Get(0)
+	if retPool == nil {
+		return nil, args.Get(1).(uint)
+	}
+	return retPool.(power.Pool), args.Get(1).(uint)
+}
+
 type poolMock struct {
 	mock.Mock
 	power.Pool
@@ -222,6 +237,11 @@ func (m *coreMock) GetAbsMinMax() (uint, uint) {
 	return args.Get(0).(uint), args.Get(1).(uint)
 }
 
+func (m *coreMock) GetFrequencyLimitsExcluding(requester power.FrequencyRequester) (uint, uint) {
+	args := m.Called(requester)
+	return args.Get(0).(uint), args.Get(1).(uint)
+}
+
 func (m *coreMock) SetPool(pool power.Pool) error {
 	return m.Called(pool).Error(0)
 }
@@ -479,6 +499,10 @@ func (m *ScalingMgrMock) RemoveCPUScaling(cpuIDs []uint) {
 	m.Called(cpuIDs)
 }
 
+func (m *ScalingMgrMock) GetCPUScalingStatus(cpuIDs []uint) []scaling.CPUScalingStatus {
+	return m.Called(cpuIDs).Get(0).([]scaling.CPUScalingStatus)
+}
+
 // Satisfy manager.Runnable
 func (m *ScalingMgrMock) Start(ctx context.Context) error { return nil }
 
@@ -508,6 +532,33 @@ func (cl *DPDKTelemetryClientMock) GetUsagePercent(cpuID uint) (int, error) {
 
 func (cl *DPDKTelemetryClientMock) Close() { cl.Called() }
 
+// CPUUsageClient mock
+type CPUUsageClientMock struct {
+	scaling.CPUUsageClient
+	mock.Mock
+}
+
+func (cl *CPUUsageClientMock) EnsureConnection(data *scaling.CPUUsageConnectionData) {
+	cl.Called(data)
+}
+
+func (cl *CPUUsageClientMock) CloseConnection(podUID string) {
+	cl.Called(podUID)
+}
+
+func (cl *CPUUsageClientMock) ListConnections() []scaling.CPUUsageConnectionData {
+	return cl.Called().Get(0).([]scaling.CPUUsageConnectionData)
+}
+
+// UClampWriter mock
+type UClampWriterMock struct {
+	mock.Mock
+}
+
+func (m *UClampWriterMock) SetContainerUClamp(podUID string, containerID string, uclamp cgroup.UClamp) error {
+	return m.Called(podUID, containerID, uclamp).Error(0)
+}
+
 func intPtr(v int) *int { return &v }
 
 func setupDummyFiles(cores int, packages int, diesPerPackage int, cpufiles map[string]string) (power.Host, func(), error) {
@@ -623,13 +674,38 @@ func setupDummyFiles(cores int, packages int, diesPerPackage int, cpufiles map[s
 
 	originalGetFromLscpu := power.GetFromLscpu
 	power.GetFromLscpu = power.TestGetFromLscpu
-	host, err := power.CreateInstanceWithConf("test-node", power.LibConfig{CpuPath: "testing/cpus", ModulePath: "testing/proc.modules", Cores: uint(cores)})
+	// the dummy system has no msr devices
+	power.SetMSRReader(&dummyMSRReader{})
+	coolingDir := "testing/thermal/cooling_device0"
+	os.MkdirAll(coolingDir, os.ModePerm)
+	os.WriteFile(filepath.Join(coolingDir, "type"), []byte("intel_powerclamp\n"), 0o644)
+	os.WriteFile(filepath.Join(coolingDir, "max_state"), []byte("50\n"), 0o644)
+	os.WriteFile(filepath.Join(coolingDir, "cur_state"), []byte("0\n"), 0o644)
+	os.MkdirAll("testing/powerclamp", os.ModePerm)
+	os.WriteFile("testing/powerclamp/cpumask", []byte("0\n"), 0o644)
+	host, err := power.CreateInstanceWithConf("test-node", power.LibConfig{
+		CpuPath: "testing/cpus", ModulePath: "testing/proc.modules", Cores: uint(cores),
+		ThermalPath: "testing/thermal", PowerclampParamsPath: "testing/powerclamp",
+	})
 	return host, func() {
 		os.RemoveAll(strings.Split(path, "/")[0])
 		power.GetFromLscpu = originalGetFromLscpu
 	}, err
 }
 
+// dummyMSRReader returns steadily increasing counters so APERF/MPERF are readable
+type dummyMSRReader struct {
+	mutex sync.Mutex
+	value uint64
+}
+
+func (r *dummyMSRReader) ReadMSR(cpuID uint, register uint32) (uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.value += 1000
+	return r.value, nil
+}
+
 // default dummy file system to be used in standard tests
 func fullDummySystem() (power.Host, func(), error) {
 	return setupDummyFiles(86, 1, 2, map[string]string{
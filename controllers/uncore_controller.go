@@ -110,11 +110,11 @@ func (r *UncoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	// Build conflict errors for non-selected configs.
-	var conflictErrors []string
+	var conflictErrors []error
 	if len(matches) > 1 {
 		for _, m := range matches {
 			if m.Name != selected.Name {
-				conflictErrors = append(conflictErrors, fmt.Sprintf("conflicting Uncore: %s", m.Name))
+				conflictErrors = append(conflictErrors, newStatusError(errConflict, "conflicting Uncore: %s", m.Name))
 			}
 		}
 	}
@@ -159,7 +159,7 @@ func (r *UncoreReconciler) applyUncoreConfig(
 	ctx context.Context,
 	uncore *powerv1alpha1.Uncore,
 	nodeName string,
-	conflictErrors []string,
+	conflictErrors []error,
 	logger *logr.Logger,
 ) (ctrl.Result, error) {
 	logger.Info("applying Uncore config", "config", uncore.Name)
@@ -170,11 +170,11 @@ func (r *UncoreReconciler) applyUncoreConfig(
 	hasSysWide := spec.SysMax != nil && spec.SysMin != nil
 	hasDieSelectors := spec.DieSelectors != nil && len(*spec.DieSelectors) > 0
 	if !hasSysWide && !hasDieSelectors {
-		validationErr := "no valid uncore configuration: requires either both sysMin and sysMax, or non-empty dieSelectors"
-		if err := r.updateUncoreInPowerNodeState(ctx, nodeName, uncore.Name, "", []string{validationErr}, logger); err != nil {
+		validationErr := newStatusError(power.ErrInvalidValue, "no valid uncore configuration: requires either both sysMin and sysMax, or non-empty dieSelectors")
+		if err := r.updateUncoreInPowerNodeState(ctx, nodeName, uncore.Name, "", []error{validationErr}, logger); err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, fmt.Errorf("%s", validationErr)
+		return ctrl.Result{}, validationErr
 	}
 
 	// Reset all uncore settings before applying new config.
@@ -182,16 +182,16 @@ func (r *UncoreReconciler) applyUncoreConfig(
 		return ctrl.Result{}, err
 	}
 
-	var applyErrors []string
+	var applyErrors []error
 	var configParts []string
 
 	// Apply system-wide uncore settings.
 	if spec.SysMax != nil && spec.SysMin != nil {
 		pUncore, err := power.NewUncore(*spec.SysMin, *spec.SysMax)
 		if err != nil {
-			applyErrors = append(applyErrors, fmt.Sprintf("error creating system uncore: %v", err))
+			applyErrors = append(applyErrors, fmt.Errorf("error creating system uncore: %w", err))
 		} else if err := r.PowerLibrary.Topology().SetUncore(pUncore); err != nil {
-			applyErrors = append(applyErrors, fmt.Sprintf("error setting system uncore: %v", err))
+			applyErrors = append(applyErrors, fmt.Errorf("error setting system uncore: %w", err))
 		} else {
 			configParts = append(configParts, fmt.Sprintf("SysMin: %d, SysMax: %d", *spec.SysMin, *spec.SysMax))
 		}
@@ -201,23 +201,23 @@ func (r *UncoreReconciler) applyUncoreConfig(
 	if spec.DieSelectors != nil {
 		for _, dieselect := range *spec.DieSelectors {
 			if dieselect.Max == nil || dieselect.Min == nil || dieselect.Package == nil {
-				applyErrors = append(applyErrors, "die selector max, min and package fields must not be empty")
+				applyErrors = append(applyErrors, newStatusError(power.ErrInvalidValue, "die selector max, min and package fields must not be empty"))
 				continue
 			}
 			pUncore, err := power.NewUncore(*dieselect.Min, *dieselect.Max)
 			if err != nil {
-				applyErrors = append(applyErrors, fmt.Sprintf("error creating uncore for package %d: %v", *dieselect.Package, err))
+				applyErrors = append(applyErrors, fmt.Errorf("error creating uncore for package %d: %w", *dieselect.Package, err))
 				continue
 			}
 			if dieselect.Die == nil {
 				// Package-level tuning.
 				pkg := r.PowerLibrary.Topology().Package(*dieselect.Package)
 				if pkg == nil {
-					applyErrors = append(applyErrors, fmt.Sprintf("invalid package: %d", *dieselect.Package))
+					applyErrors = append(applyErrors, newStatusError(power.ErrInvalidValue, "invalid package: %d", *dieselect.Package))
 					continue
 				}
 				if err := pkg.SetUncore(pUncore); err != nil {
-					applyErrors = append(applyErrors, fmt.Sprintf("error setting uncore for package %d: %v", *dieselect.Package, err))
+					applyErrors = append(applyErrors, fmt.Errorf("error setting uncore for package %d: %w", *dieselect.Package, err))
 					continue
 				}
 				configParts = append(configParts, fmt.Sprintf("Package %d: Min %d, Max %d", *dieselect.Package, *dieselect.Min, *dieselect.Max))
@@ -225,16 +225,16 @@ func (r *UncoreReconciler) applyUncoreConfig(
 				// Die-level tuning.
 				pkg := r.PowerLibrary.Topology().Package(*dieselect.Package)
 				if pkg == nil {
-					applyErrors = append(applyErrors, fmt.Sprintf("invalid package: %d", *dieselect.Package))
+					applyErrors = append(applyErrors, newStatusError(power.ErrInvalidValue, "invalid package: %d", *dieselect.Package))
 					continue
 				}
 				die := pkg.Die(*dieselect.Die)
 				if die == nil {
-					applyErrors = append(applyErrors, fmt.Sprintf("invalid die: %d", *dieselect.Die))
+					applyErrors = append(applyErrors, newStatusError(power.ErrInvalidValue, "invalid die: %d", *dieselect.Die))
 					continue
 				}
 				if err := die.SetUncore(pUncore); err != nil {
-					applyErrors = append(applyErrors, fmt.Sprintf("error setting uncore for package %d die %d: %v", *dieselect.Package, *dieselect.Die, err))
+					applyErrors = append(applyErrors, fmt.Errorf("error setting uncore for package %d die %d: %w", *dieselect.Package, *dieselect.Die, err))
 					continue
 				}
 				configParts = append(configParts, fmt.Sprintf("Package %d Die %d: Min %d, Max %d", *dieselect.Package, *dieselect.Die, *dieselect.Min, *dieselect.Max))
@@ -243,17 +243,18 @@ func (r *UncoreReconciler) applyUncoreConfig(
 	}
 
 	// Merge conflict errors and apply errors.
-	var statusErrors []string
-	statusErrors = append(statusErrors, conflictErrors...)
-	statusErrors = append(statusErrors, applyErrors...)
+	var uncoreErrors []error
+	uncoreErrors = append(uncoreErrors, conflictErrors...)
+	uncoreErrors = append(uncoreErrors, applyErrors...)
 
 	configString := strings.Join(configParts, "; ")
-	if err := r.updateUncoreInPowerNodeState(ctx, nodeName, uncore.Name, configString, statusErrors, logger); err != nil {
+	if err := r.updateUncoreInPowerNodeState(ctx, nodeName, uncore.Name, configString, uncoreErrors, logger); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if len(applyErrors) > 0 {
-		return ctrl.Result{}, fmt.Errorf("errors applying uncore config: %s", strings.Join(applyErrors, "; "))
+		errList, _ := statusErrors(applyErrors...)
+		return ctrl.Result{}, fmt.Errorf("errors applying uncore config: %s", strings.Join(errList, "; "))
 	}
 	return ctrl.Result{}, nil
 }
@@ -293,10 +294,11 @@ func (r *UncoreReconciler) updateUncoreInPowerNodeState(
 	nodeName string,
 	crName string,
 	configString string,
-	statusErrors []string,
+	uncoreErrors []error,
 	logger *logr.Logger,
 ) error {
 	powerNodeStateName := fmt.Sprintf("%s-power-state", nodeName)
+	errList, reasons := statusErrors(uncoreErrors...)
 
 	patchNodeState := &powerv1alpha1.PowerNodeState{
 		TypeMeta: metav1.TypeMeta{
@@ -309,9 +311,13 @@ func (r *UncoreReconciler) updateUncoreInPowerNodeState(
 		},
 		Status: powerv1alpha1.PowerNodeStateStatus{
 			Uncore: &powerv1alpha1.NodeUncoreStatus{
-				Name:   crName,
-				Config: configString,
-				Errors: statusErrors,
+				Name:    crName,
+				Config:  configString,
+				Errors:  errList,
+				Reasons: reasons,
+			},
+			Conditions: []metav1.Condition{
+				nodeStateCondition(ctx, r.Client, powerNodeStateName, powerv1alpha1.ConditionUncoreApplied, errList, reasons),
 			},
 		},
 	}
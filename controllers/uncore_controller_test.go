@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -173,26 +174,31 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 		name        string
 		spec        powerv1alpha1.UncoreSpec
 		errContains string
+		reason      powerv1alpha1.StatusReason
 	}{
 		{
 			name:        "empty spec",
 			spec:        powerv1alpha1.UncoreSpec{},
 			errContains: "no valid uncore configuration: requires either both sysMin and sysMax, or non-empty dieSelectors",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name:        "missing min in die selector",
 			spec:        powerv1alpha1.UncoreSpec{DieSelectors: &[]powerv1alpha1.DieSelector{{Package: &pkg, Max: &max}}},
 			errContains: "max, min and package fields must not be empty",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name:        "missing max in die selector",
 			spec:        powerv1alpha1.UncoreSpec{DieSelectors: &[]powerv1alpha1.DieSelector{{Package: &pkg, Die: &die, Min: &min}}},
 			errContains: "max, min and package fields must not be empty",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name:        "missing package in die selector",
 			spec:        powerv1alpha1.UncoreSpec{DieSelectors: &[]powerv1alpha1.DieSelector{{Die: &die, Max: &max, Min: &min}}},
 			errContains: "max, min and package fields must not be empty",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name: "invalid package ID",
@@ -200,6 +206,7 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 				{Package: uintPtr(100000), Die: &die, Max: &max, Min: &min},
 			}},
 			errContains: "invalid package",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name: "invalid package ID (package-level tuning)",
@@ -207,6 +214,7 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 				{Package: uintPtr(100000), Max: &max, Min: &min},
 			}},
 			errContains: "invalid package",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name: "invalid die ID",
@@ -214,11 +222,13 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 				{Package: &pkg, Die: uintPtr(100000), Max: &max, Min: &min},
 			}},
 			errContains: "invalid die",
+			reason:      powerv1alpha1.StatusReasonInvalidValue,
 		},
 		{
 			name:        "frequency exceeds hardware limits (system-wide)",
 			spec:        powerv1alpha1.UncoreSpec{SysMax: uintPtr(20000000000), SysMin: &min},
 			errContains: "specified Max frequency is higher than",
+			reason:      powerv1alpha1.StatusReasonOutOfRange,
 		},
 		{
 			name: "frequency exceeds hardware limits (package-level)",
@@ -226,6 +236,7 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 				{Package: &pkg, Max: uintPtr(20000000000), Min: &min},
 			}},
 			errContains: "specified Max frequency is higher than",
+			reason:      powerv1alpha1.StatusReasonOutOfRange,
 		},
 		{
 			name: "frequency exceeds hardware limits (die-level)",
@@ -233,6 +244,7 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 				{Package: &pkg, Die: &die, Max: uintPtr(20000000000), Min: &min},
 			}},
 			errContains: "specified Max frequency is higher than",
+			reason:      powerv1alpha1.StatusReasonOutOfRange,
 		},
 	}
 
@@ -254,6 +266,12 @@ func TestUncore_Reconcile_InvalidSpecs(t *testing.T) {
 			assert.Nil(t, r.Get(context.TODO(), client.ObjectKey{Name: nodeName + "-power-state", Namespace: PowerNamespace}, pns))
 			assert.NotNil(t, pns.Status.Uncore)
 			assert.NotEmpty(t, pns.Status.Uncore.Errors)
+			assert.Equal(t, tc.reason, pns.Status.Uncore.Reasons[0])
+			condition := meta.FindStatusCondition(pns.Status.Conditions, powerv1alpha1.ConditionUncoreApplied)
+			if assert.NotNil(t, condition) {
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, string(tc.reason), condition.Reason)
+			}
 		})
 	}
 }
@@ -0,0 +1,44 @@
+package scaling
+
+import (
+	"github.com/intel/power-optimization-library/pkg/power"
+)
+
+// eppLevels are the energy performance preferences from the most power saving to the most performant,
+// accepted by both intel_pstate and amd-pstate-epp
+var eppLevels = []string{"power", "balance_power", "balance_performance", "performance"}
+
+// applyFrequency applies a frequency target to the CPU with the actuator of its options
+func applyFrequency(opts *CPUScalingOpts, frequency int) error {
+	switch opts.Actuator {
+	case ActuatorMaxFreq:
+		return opts.CPU.SetFrequencyConstraint(power.ScalerRequester, power.FrequencyConstraint{Max: uint(frequency)})
+	case ActuatorEPP:
+		return opts.CPU.SetEPPOverride(eppForFrequency(opts, frequency))
+	default:
+		return opts.CPU.SetCPUFrequency(uint(frequency))
+	}
+}
+
+// eppForFrequency splits the frequency range into one band per EPP level and returns the level
+// of the band the frequency falls in
+func eppForFrequency(opts *CPUScalingOpts, frequency int) string {
+	freqRange := opts.MaxFrequency - opts.MinFrequency
+	if freqRange <= 0 {
+		return eppLevels[len(eppLevels)-1]
+	}
+	level := (frequency - opts.MinFrequency) * len(eppLevels) / freqRange
+	return eppLevels[min(max(level, 0), len(eppLevels)-1)]
+}
+
+// releaseActuator removes the frequency cap or EPP the actuator placed on a CPU which is no longer scaled
+func releaseActuator(opts *CPUScalingOpts) error {
+	switch opts.Actuator {
+	case ActuatorMaxFreq:
+		return opts.CPU.ClearFrequencyConstraint(power.ScalerRequester)
+	case ActuatorEPP:
+		return opts.CPU.ClearEPPOverride()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package scaling
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEPPForFrequency(t *testing.T) {
+	opts := &CPUScalingOpts{MinFrequency: 1000000, MaxFrequency: 3000000}
+
+	assert.Equal(t, "power", eppForFrequency(opts, 1000000))
+	assert.Equal(t, "power", eppForFrequency(opts, 1499999))
+	assert.Equal(t, "balance_power", eppForFrequency(opts, 1500000))
+	assert.Equal(t, "balance_performance", eppForFrequency(opts, 2499999))
+	assert.Equal(t, "performance", eppForFrequency(opts, 2500000))
+	assert.Equal(t, "performance", eppForFrequency(opts, 3000000))
+	// out of range targets are bounded
+	assert.Equal(t, "power", eppForFrequency(opts, 500000))
+	assert.Equal(t, "performance", eppForFrequency(opts, 3500000))
+
+	// a single frequency has no range to save power in
+	opts.MinFrequency = 3000000
+	assert.Equal(t, "performance", eppForFrequency(opts, 3000000))
+}
+
+func TestApplyFrequency(t *testing.T) {
+	host, teardown, err := setupScalingTestFiles(1, map[string]string{
+		"governor": "powersave",
+		"max":      "3000000",
+		"min":      "1000000",
+		"epp":      "balance_power",
+	})
+	assert.NoError(t, err)
+	defer teardown()
+
+	cpu := host.GetAllCpus().ByID(0)
+	readFile := func(name string) string {
+		value, err := os.ReadFile(filepath.Join("testing", "cpus", "cpu0", "cpufreq", name))
+		assert.NoError(t, err)
+		return strings.TrimSpace(string(value))
+	}
+	opts := &CPUScalingOpts{CPU: cpu, MinFrequency: 1000000, MaxFrequency: 3000000}
+
+	// the userspace governor sets the frequency
+	assert.NoError(t, applyFrequency(opts, 2000000))
+	assert.Equal(t, "2000000", readFile("scaling_setspeed"))
+	assert.NoError(t, releaseActuator(opts))
+
+	// scaling_max_freq is capped, within the limits of the other requesters
+	opts.Actuator = ActuatorMaxFreq
+	assert.NoError(t, cpu.SetFrequencyConstraint(power.ProfileRequester, power.FrequencyConstraint{Min: 1200000, Max: 2800000}))
+	assert.NoError(t, applyFrequency(opts, 1800000))
+	assert.Equal(t, "1800000", readFile("scaling_max_freq"))
+	assert.Equal(t, "1200000", readFile("scaling_min_freq"))
+	minFreq, maxFreq := cpu.GetFrequencyLimitsExcluding(power.ScalerRequester)
+	assert.Equal(t, uint(1200000), minFreq)
+	assert.Equal(t, uint(2800000), maxFreq)
+	assert.NoError(t, releaseActuator(opts))
+	assert.Equal(t, "2800000", readFile("scaling_max_freq"))
+	assert.NotContains(t, cpu.GetFrequencyConstraints(), power.ScalerRequester)
+
+	// the EPP level of the frequency band is set
+	opts.Actuator = ActuatorEPP
+	assert.NoError(t, applyFrequency(opts, 2900000))
+	assert.Equal(t, "performance", readFile("energy_performance_preference"))
+	assert.NoError(t, applyFrequency(opts, 1100000))
+	assert.Equal(t, "power", readFile("energy_performance_preference"))
+	assert.NoError(t, releaseActuator(opts))
+	assert.Empty(t, cpu.GetEPPOverride())
+}
@@ -0,0 +1,56 @@
+package scaling
+
+import "sync"
+
+var (
+	// consecutive failed polls of a telemetry socket after which its CPUs are degraded to the fallback frequency
+	breakerTripPolls = 10
+	// consecutive fresh polls of a degraded telemetry socket after which its CPUs are scaled again
+	breakerRecoveryPolls = 10
+)
+
+// circuitBreaker tracks the health of a telemetry socket. It opens after breakerTripPolls consecutive
+// failed polls, and closes again after breakerRecoveryPolls consecutive fresh polls, so a socket
+// flapping between stale and fresh samples stays degraded instead of toggling the scaling of its CPUs
+type circuitBreaker struct {
+	mutex    sync.Mutex
+	open     bool
+	failures int
+	fresh    int
+}
+
+// recordFailure counts a failed poll and reports whether it opened the breaker
+func (b *circuitBreaker) recordFailure() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.fresh = 0
+	b.failures++
+	if b.open || b.failures < breakerTripPolls {
+		return false
+	}
+	b.open = true
+	return true
+}
+
+// recordFresh counts a poll with fresh samples and reports whether it closed the breaker
+func (b *circuitBreaker) recordFresh() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures = 0
+	if !b.open {
+		return false
+	}
+	b.fresh++
+	if b.fresh < breakerRecoveryPolls {
+		return false
+	}
+	b.open = false
+	b.fresh = 0
+	return true
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.open
+}
@@ -1,6 +1,7 @@
 package scaling
 
 import (
+	"slices"
 	"time"
 
 	"github.com/intel/power-optimization-library/pkg/power"
@@ -8,16 +9,102 @@ import (
 
 const FrequencyNotYetSet int = -1
 
+// Actuator selects how the frequency targets of a CPU are applied
+type Actuator int
+
+const (
+	// ActuatorSetSpeed sets the frequency with the userspace governor
+	ActuatorSetSpeed Actuator = iota
+	// ActuatorMaxFreq caps scaling_max_freq, the governor selects the frequency below the cap
+	ActuatorMaxFreq
+	// ActuatorEPP steers the energy performance preference, the hardware selects the frequency
+	ActuatorEPP
+)
+
+// UsageSource reports the usage of the CPUs of pods whose frequency is scaled
+type UsageSource interface {
+	GetUsagePercent(cpuID uint) (int, error)
+	CloseConnection(podUID string)
+	Close()
+}
+
 type CPUScalingOpts struct {
 	CPU                        power.Cpu
+	UsageSource                UsageSource
 	SamplePeriod               time.Duration
 	CooldownPeriod             time.Duration
 	TargetUsage                int
 	AllowedUsageDifference     int
 	AllowedFrequencyDifference int
-	HWMaxFrequency             int
-	HWMinFrequency             int
-	CurrentTargetFrequency     int
-	ScaleFactor                float64
-	FallbackFreq               int
+	// MinFrequency and MaxFrequency bound the scaled frequency. They follow the effective frequency limits of
+	// the CPU, i.e. the P-states of its profile and any other active frequency constraints but the scaler's own
+	MaxFrequency           int
+	MinFrequency           int
+	CurrentTargetFrequency int
+	// ScaleFactor applies to the usage error when scaling up, and when scaling down unless ScaleDownFactor is set
+	ScaleFactor     float64
+	ScaleDownFactor float64
+	// MaxFrequencyStep limits the change of the target frequency per update, it is not limited if 0
+	MaxFrequencyStep int
+	// ScaleDownCooldownPeriod replaces CooldownPeriod after the target frequency was lowered, if set
+	ScaleDownCooldownPeriod time.Duration
+	// BurstUsage is the usage at or above which the maximum frequency is set at once, disabled if 0
+	BurstUsage int
+	// FallbackFreq is set when the usage is unavailable, at FallbackFreqPercent of the frequency range
+	FallbackFreq        int
+	FallbackFreqPercent int
+	Actuator            Actuator
+	// PID selects the PID controller instead of the proportional rule
+	PID *PIDOpts
+	// AdaptiveGain adapts the gains of the controller to the workload if set
+	AdaptiveGain *AdaptiveGainOpts
+	// IdleCStates overrides the C-states of the CPU while it is idle if set
+	IdleCStates *IdleCStatesOpts
+
+	// outcome of the last update, reported in the status of the CPU
+	usage      int
+	usageErr   error
+	lastChange time.Time
+}
+
+// CPUScalingStatus is the state of a scaled CPU after its last update
+type CPUScalingStatus struct {
+	CPUID uint
+	// TargetFrequency is the frequency last set by the scaler, FrequencyNotYetSet before the first change
+	TargetFrequency  int
+	CurrentFrequency uint
+	// Usage is the last usage sample of the CPU, unless reading it failed with UsageErr
+	Usage      int
+	UsageErr   error
+	LastChange time.Time
+	// LastUpdate is the time of the last update, zero before the first one
+	LastUpdate time.Time
+	// GainPercent is the gain adapted to the workload in percent of the configured gains, 0 if it is not adapted
+	GainPercent int
+	// Idle is set while the C-states of the CPU are overridden because it is idle
+	Idle bool
+}
+
+// carryState takes over the state of prev, the options the CPU was scaled with before they were updated, so
+// reconciling an unchanged policy does not reset the scaling. The target frequency and the PID controller are kept if
+// the actuator is the same, the state of each controller and the idle C-states if their settings did not change
+func (o *CPUScalingOpts) carryState(prev *CPUScalingOpts) {
+	o.usage, o.usageErr = prev.usage, prev.usageErr
+	if o.Actuator == prev.Actuator {
+		o.CurrentTargetFrequency = prev.CurrentTargetFrequency
+		o.lastChange = prev.lastChange
+		o.PID.carryState(prev.PID)
+	}
+	o.AdaptiveGain.carryState(prev.AdaptiveGain)
+	o.IdleCStates.carryState(prev.IdleCStates)
+}
+
+// mergeContainerCPUs returns the sorted CPUs of all containers of a pod
+func mergeContainerCPUs(containers map[string][]uint) []uint {
+	cpus := []uint{}
+	for _, containerCPUs := range containers {
+		cpus = append(cpus, containerCPUs...)
+	}
+	slices.Sort(cpus)
+	return slices.Compact(cpus)
 }
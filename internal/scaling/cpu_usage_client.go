@@ -0,0 +1,281 @@
+package scaling
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// number of cpu time fields of /proc/stat used, up to steal
+const procStatCPUFields = 8
+
+var (
+	ErrCPUUsageMissing     = errors.New("no cpu usage entry found for this cpu")
+	ErrCPUUsageNotProvided = errors.New("cpu usage was not sampled for this cpu")
+
+	procStatPath = "/proc/stat"
+	// /proc/stat accounts cpu time in USER_HZ ticks of usually 10ms, so the usage is sampled
+	// over several ticks to keep its resolution usable
+	cpuUsageSamplePeriod = 100 * time.Millisecond
+
+	testHookNewCPUUsagePod func(pod *cpuUsagePod)
+)
+
+// cpuTimes holds the cumulative busy and total time of a cpu in USER_HZ
+type cpuTimes struct {
+	total uint64
+	busy  uint64
+}
+
+// CPUUsageConnectionData describes the CPUs of a container whose usage is sampled from the busy time
+// the kernel accounts to them
+type CPUUsageConnectionData struct {
+	PodUID        string
+	ContainerName string
+	WatchedCPUs   []uint
+}
+
+// CPUUsageClient reports the usage of exclusive CPUs from /proc/stat, for workloads which leave
+// the CPU idle when they run out of work instead of polling
+type CPUUsageClient interface {
+	UsageSource
+	EnsureConnection(data *CPUUsageConnectionData)
+	ListConnections() []CPUUsageConnectionData
+}
+
+type cpuUsageClientImpl struct {
+	log        logr.Logger
+	pods       sync.Map // sampled CPUs of each pod, keyed by pod UID
+	deltaUsage sync.Map
+}
+
+func NewCPUUsageClient(logger logr.Logger) CPUUsageClient {
+	c := &cpuUsageClientImpl{
+		log: logger,
+	}
+
+	return c
+}
+
+func (cl *cpuUsageClientImpl) EnsureConnection(data *CPUUsageConnectionData) {
+	cl.log.V(4).Info("ensuring connection", "podUID", data.PodUID, "container", data.ContainerName,
+		"watchedCPUs", data.WatchedCPUs)
+	podUID := data.PodUID
+	containers := map[string][]uint{data.ContainerName: data.WatchedCPUs}
+
+	if existing, present := cl.pods.Load(podUID); present {
+		pod := existing.(*cpuUsagePod)
+		if cpus, found := pod.containers[data.ContainerName]; found && slices.Equal(cpus, data.WatchedCPUs) {
+			return
+		}
+		for name, cpus := range pod.containers {
+			if name != data.ContainerName {
+				containers[name] = cpus
+			}
+		}
+		cl.log.V(4).Info("watched cpus changed, restarting sampling", "podUID", podUID)
+		pod.close()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	newPod := &cpuUsagePod{
+		podUID:     podUID,
+		containers: containers,
+		deltaUsage: &cl.deltaUsage,
+		log:        cl.log.WithValues("podUID", podUID),
+		cancelFunc: cancel,
+	}
+	cl.pods.Store(podUID, newPod)
+	if testHookNewCPUUsagePod != nil {
+		testHookNewCPUUsagePod(newPod)
+	} else {
+		newPod.waitGroup.Add(1)
+		go newPod.sampleLoop(ctx)
+	}
+}
+
+func (cl *cpuUsageClientImpl) ListConnections() []CPUUsageConnectionData {
+	dataList := make([]CPUUsageConnectionData, 0)
+
+	cl.pods.Range(func(key, value any) bool {
+		pod := value.(*cpuUsagePod)
+		for name, cpus := range pod.containers {
+			dataList = append(dataList, CPUUsageConnectionData{
+				PodUID:        pod.podUID,
+				ContainerName: name,
+				WatchedCPUs:   cpus,
+			})
+		}
+		return true
+	})
+
+	return dataList
+}
+
+func (cl *cpuUsageClientImpl) CloseConnection(podUID string) {
+	if pod, found := cl.pods.LoadAndDelete(podUID); found {
+		pod.(*cpuUsagePod).close()
+		cl.log.V(4).Info("stopped sampling.", "podUID", podUID)
+	} else {
+		cl.log.V(4).Info("connection does not exist.", "podUID", podUID)
+	}
+}
+
+func (cl *cpuUsageClientImpl) GetUsagePercent(cpuID uint) (int, error) {
+	if value, found := cl.deltaUsage.Load(cpuID); found {
+		r := value.(telemetryResult)
+		return r.percent, r.err
+	}
+
+	return 0, ErrCPUUsageMissing
+}
+
+func (cl *cpuUsageClientImpl) Close() {
+	cl.log.V(4).Info("stopping all sampling loops.")
+
+	cl.pods.Range(func(key, value any) bool {
+		value.(*cpuUsagePod).close()
+		cl.pods.Delete(key.(string))
+		return true
+	})
+
+	cl.log.V(4).Info("all sampling loops stopped.")
+}
+
+// cpuUsagePod samples the usage of the CPUs of a pod's containers
+type cpuUsagePod struct {
+	podUID     string
+	containers map[string][]uint // CPUs watched for each container of the pod
+	deltaUsage *sync.Map         // latest windowed usage percent per CPU
+	prevTimes  map[uint]cpuTimes // last cumulative cpu times per CPU
+	failing    bool              // /proc/stat could not be read in the last sample
+	log        logr.Logger
+	waitGroup  sync.WaitGroup
+	cancelFunc func()
+}
+
+func (p *cpuUsagePod) close() {
+	p.cancelFunc()
+	p.waitGroup.Wait()
+	for _, cpuID := range mergeContainerCPUs(p.containers) {
+		p.deltaUsage.Delete(cpuID)
+	}
+}
+
+// sampleLoop samples the usage of the pod's CPUs every cpuUsageSamplePeriod until the context is cancelled
+func (p *cpuUsagePod) sampleLoop(ctx context.Context) {
+	defer p.waitGroup.Done()
+
+	cpuIDs := mergeContainerCPUs(p.containers)
+	ticker := time.NewTicker(cpuUsageSamplePeriod)
+	defer ticker.Stop()
+	for {
+		p.sample(cpuIDs)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample computes the usage of each CPU over the last sample period from the cumulative
+// cpu times in /proc/stat, and updates deltaUsage. If a sample is unavailable for a CPU,
+// stores an error for that CPU.
+func (p *cpuUsagePod) sample(cpuIDs []uint) {
+	if p.prevTimes == nil {
+		p.prevTimes = map[uint]cpuTimes{}
+	}
+	times, err := readProcStat()
+	if err != nil {
+		if !p.failing {
+			p.log.Error(err, "failed to read cpu times")
+		}
+		p.failing = true
+		clear(p.prevTimes)
+		for _, cpuID := range cpuIDs {
+			p.deltaUsage.Store(cpuID, telemetryResult{err: err})
+		}
+		return
+	}
+	p.failing = false
+
+	for _, cpuID := range cpuIDs {
+		result := telemetryResult{}
+		curr, found := times[cpuID]
+		if !found {
+			// offline CPUs are not listed
+			delete(p.prevTimes, cpuID)
+			p.deltaUsage.Store(cpuID, telemetryResult{err: ErrCPUUsageMissing})
+			continue
+		}
+
+		prev, ok := p.prevTimes[cpuID]
+		switch {
+		case !ok:
+			// First observation for this CPU. Need a baseline before reporting.
+			result.err = ErrCPUUsageNotProvided
+		case curr.total < prev.total || curr.busy < prev.busy:
+			// Counters went backwards, e.g. after a CPU hotplug. Skip this sample.
+			result.err = ErrCPUUsageNotProvided
+		case curr.total == prev.total:
+			result.percent = 0
+		default:
+			percent := (curr.busy - prev.busy) * 100 / (curr.total - prev.total)
+			result.percent = int(min(percent, 100))
+		}
+		p.prevTimes[cpuID] = curr
+		p.deltaUsage.Store(cpuID, result)
+	}
+}
+
+// readProcStat returns the cumulative cpu times of each online cpu. Idle and iowait time count
+// as idle, every other state up to steal as busy. Guest time is already included in user time
+func readProcStat() (map[uint]cpuTimes, error) {
+	file, err := os.Open(procStatPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	times := map[uint]cpuTimes{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// the aggregated "cpu" line and other statistics are skipped
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") || fields[0] == "cpu" {
+			continue
+		}
+		cpuID, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "cpu"), 10, 0)
+		if err != nil {
+			continue
+		}
+		if len(fields) < procStatCPUFields+1 {
+			return nil, fmt.Errorf("%s: too few fields for cpu %d", procStatPath, cpuID)
+		}
+		var counters [procStatCPUFields]uint64
+		for i := range counters {
+			if counters[i], err = strconv.ParseUint(fields[i+1], 10, 64); err != nil {
+				return nil, fmt.Errorf("%s: invalid time of cpu %d: %w", procStatPath, cpuID, err)
+			}
+		}
+		// user nice system idle iowait irq softirq steal
+		idle := counters[3] + counters[4]
+		busy := counters[0] + counters[1] + counters[2] + counters[5] + counters[6] + counters[7]
+		times[uint(cpuID)] = cpuTimes{total: busy + idle, busy: busy}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return times, nil
+}
@@ -0,0 +1,146 @@
+package scaling
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func setupProcStat(t *testing.T) func(content string) {
+	origProcStatPath := procStatPath
+	t.Cleanup(func() {
+		procStatPath = origProcStatPath
+	})
+	procStatPath = filepath.Join(t.TempDir(), "stat")
+
+	return func(content string) {
+		assert.NoError(t, os.WriteFile(procStatPath, []byte(content), 0o644))
+	}
+}
+
+func TestReadProcStat(t *testing.T) {
+	writeProcStat := setupProcStat(t)
+
+	_, err := readProcStat()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	writeProcStat(`cpu  300 0 100 1500 100 0 0 0 0 0
+cpu0 100 0 50 800 50 0 0 0 0 0
+cpu2 200 10 50 600 50 5 5 10 20 0
+intr 12345 0 0
+ctxt 6789
+`)
+	times, err := readProcStat()
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint]cpuTimes{
+		0: {total: 1000, busy: 150},
+		2: {total: 930, busy: 280},
+	}, times)
+
+	writeProcStat("cpu0 100 0 50\n")
+	_, err = readProcStat()
+	assert.ErrorContains(t, err, "too few fields for cpu 0")
+
+	writeProcStat("cpu0 100 0 50 800 50 0 0 x\n")
+	_, err = readProcStat()
+	assert.ErrorContains(t, err, "invalid time of cpu 0")
+}
+
+func TestCPUUsagePod_sample(t *testing.T) {
+	writeProcStat := setupProcStat(t)
+	cl := &cpuUsageClientImpl{log: ctrl.Log.WithName("test-log")}
+	pod := &cpuUsagePod{deltaUsage: &cl.deltaUsage, log: cl.log}
+	usage := func(cpuID uint) telemetryResult {
+		percent, err := cl.GetUsagePercent(cpuID)
+		return telemetryResult{percent: percent, err: err}
+	}
+
+	// a baseline is needed before reporting
+	writeProcStat("cpu0 100 0 0 100 0 0 0 0\ncpu1 100 0 0 100 0 0 0 0\n")
+	pod.sample([]uint{0, 1, 3})
+	assert.Equal(t, telemetryResult{err: ErrCPUUsageNotProvided}, usage(0))
+	assert.Equal(t, telemetryResult{err: ErrCPUUsageMissing}, usage(3))
+
+	writeProcStat("cpu0 160 0 15 125 0 0 0 0\ncpu1 90 0 0 150 0 0 0 0\n")
+	pod.sample([]uint{0, 1, 3})
+	assert.Equal(t, telemetryResult{percent: 75}, usage(0))
+	// counters went backwards
+	assert.Equal(t, telemetryResult{err: ErrCPUUsageNotProvided}, usage(1))
+
+	writeProcStat("cpu0 160 0 15 125 0 0 0 0\ncpu1 90 0 0 170 0 0 0 0\n")
+	pod.sample([]uint{0, 1, 3})
+	assert.Equal(t, telemetryResult{percent: 0}, usage(0))
+	assert.Equal(t, telemetryResult{percent: 0}, usage(1))
+
+	// an unreadable /proc/stat resets the baseline
+	assert.NoError(t, os.Remove(procStatPath))
+	pod.sample([]uint{0})
+	_, err := cl.GetUsagePercent(0)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	writeProcStat("cpu0 200 0 15 125 0 0 0 0\n")
+	pod.sample([]uint{0})
+	assert.Equal(t, telemetryResult{err: ErrCPUUsageNotProvided}, usage(0))
+}
+
+func TestCPUUsageClient_EnsureConnection(t *testing.T) {
+	started := []*cpuUsagePod{}
+	t.Cleanup(func() {
+		testHookNewCPUUsagePod = nil
+	})
+	testHookNewCPUUsagePod = func(pod *cpuUsagePod) {
+		started = append(started, pod)
+	}
+
+	cl := &cpuUsageClientImpl{log: ctrl.Log.WithName("test-log")}
+	cl.EnsureConnection(&CPUUsageConnectionData{PodUID: "foo", ContainerName: "app", WatchedCPUs: []uint{1}})
+	cl.EnsureConnection(&CPUUsageConnectionData{PodUID: "foo", ContainerName: "app", WatchedCPUs: []uint{1}})
+	assert.Len(t, started, 1)
+
+	cl.EnsureConnection(&CPUUsageConnectionData{PodUID: "foo", ContainerName: "sidecar", WatchedCPUs: []uint{3, 2}})
+	assert.Len(t, started, 2)
+	assert.Equal(t, []uint{1, 2, 3}, mergeContainerCPUs(started[1].containers))
+	assert.ElementsMatch(t, []CPUUsageConnectionData{
+		{PodUID: "foo", ContainerName: "app", WatchedCPUs: []uint{1}},
+		{PodUID: "foo", ContainerName: "sidecar", WatchedCPUs: []uint{3, 2}},
+	}, cl.ListConnections())
+
+	// closing the pod clears the usage of its CPUs
+	cl.deltaUsage.Store(uint(2), telemetryResult{percent: 50})
+	cl.CloseConnection("foo")
+	assert.Empty(t, cl.ListConnections())
+	_, err := cl.GetUsagePercent(2)
+	assert.ErrorIs(t, err, ErrCPUUsageMissing)
+}
+
+func TestCPUUsageClient_sampleLoop(t *testing.T) {
+	writeProcStat := setupProcStat(t)
+	writeProcStat("cpu0 100 0 0 100 0 0 0 0\n")
+	origSamplePeriod := cpuUsageSamplePeriod
+	t.Cleanup(func() {
+		cpuUsageSamplePeriod = origSamplePeriod
+	})
+	cpuUsageSamplePeriod = time.Millisecond
+
+	cl := NewCPUUsageClient(ctrl.Log.WithName("test-log"))
+	cl.EnsureConnection(&CPUUsageConnectionData{PodUID: "foo", ContainerName: "app", WatchedCPUs: []uint{0}})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for {
+		if _, err := cl.GetUsagePercent(0); err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	percent, err := cl.GetUsagePercent(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, percent)
+
+	cl.Close()
+	_, err = cl.GetUsagePercent(0)
+	assert.ErrorIs(t, err, ErrCPUUsageMissing)
+}
@@ -6,23 +6,40 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 )
 
 const (
-	baseSocketPath = "/var/lib/power-node-agent/pods/%s/dpdk/rte/dpdk_telemetry.v2"
-	usageCommand   = "/eal/lcore/usage"
-	ioTimeout      = 3 * time.Second
+	// TelemetrySocketName is the name of the DPDK telemetry socket in the runtime directory of a DPDK process
+	TelemetrySocketName = "dpdk_telemetry.v2"
+	// directory of a pod's DPDK runtime directories, relative to the pod's directory
+	podDPDKDir = "dpdk"
+
+	usageCommand = "/eal/lcore/usage"
+	infoCommand  = "/eal/lcore/info"
+	ioTimeout    = 3 * time.Second
+
+	// DefaultTelemetryMaxAge is the age after which a DPDK usage sample is stale
+	DefaultTelemetryMaxAge = 100 * time.Millisecond
 )
 
 var (
 	ErrDPDKMetricMissing     = errors.New("no entry found for this cpu")
 	ErrDPDKMetricNotProvided = errors.New("dpdk telemetry did not provide a reading for this cpu")
+	ErrDPDKLcoreFloating     = errors.New("dpdk lcore is not pinned to a single cpu")
+	ErrDPDKMetricStale       = errors.New("dpdk telemetry sample of this cpu is stale")
+	ErrDPDKTelemetryDegraded = errors.New("dpdk telemetry of this cpu is degraded")
+
+	// directory of the per pod directories shared with the node agent
+	podsBasePath = "/var/lib/power-node-agent/pods"
 
 	retryDuration = 1 * time.Second
 	// Metric polling period; also the sampling window for windowed usage.
@@ -34,7 +51,7 @@ var (
 	testHookReadInitMsgReturn    func() error
 	testHookHandleMetricsLoop    func() error
 	testHookProcessCommandReturn func(cmd string) (any, error)
-	testHookNewSocketConnection  func(conn *dpdkTelemetryConnection)
+	testHookNewSocketConnection  func(pod *dpdkTelemetryPod)
 	testHookStopConnectLoop      func() bool
 	testHookCloseConnection      func()
 )
@@ -53,6 +70,14 @@ type usageData struct {
 	BusyCycles  []uint64 `json:"busy_cycles"`
 }
 
+type lcoreInfoResponse struct {
+	Info *lcoreInfo `json:"/eal/lcore/info"`
+}
+type lcoreInfo struct {
+	LcoreID uint   `json:"lcore_id"`
+	CPUSet  []uint `json:"cpuset"`
+}
+
 // cycleCounters holds cumulative cycle counters reported by DPDK usage endpoint for an lcore.
 type cycleCounters struct {
 	total uint64
@@ -63,52 +88,83 @@ func connectWithTimeout(addr string, to time.Duration) (net.Conn, error) {
 	return net.DialTimeout("unixpacket", addr, to)
 }
 
+// DPDKTelemetryConnectionData describes the DPDK telemetry of a container. Containers of the same pod share the
+// pod's telemetry sockets, each socket reports the lcores of one DPDK process
 type DPDKTelemetryConnectionData struct {
-	PodUID      string
-	WatchedCPUs []uint
+	PodUID        string
+	ContainerName string
+	WatchedCPUs   []uint
+	// SocketPath is the telemetry socket relative to the pod's DPDK directory, e.g. "<file-prefix>/dpdk_telemetry.v2".
+	// If empty, all telemetry sockets found in the runtime directories below the pod's DPDK directory are used
+	SocketPath string
+	// Connected is reported by ListConnections while a telemetry socket of the pod is connected
+	Connected bool
+	// Degraded is reported by ListConnections while a telemetry socket of the pod fails to provide fresh
+	// samples and the CPUs it reports run at the fallback frequency
+	Degraded bool
 }
 
 type DPDKTelemetryClient interface {
+	UsageSource
 	EnsureConnection(data *DPDKTelemetryConnectionData)
 	ListConnections() []DPDKTelemetryConnectionData
-	CloseConnection(podUID string)
-	GetUsagePercent(cpuID uint) (int, error)
-	Close()
 }
 
 type dpdkTelemetryClientImpl struct {
-	log         logr.Logger
-	connections sync.Map
-	deltaUsage  sync.Map
+	log          logr.Logger
+	connections  sync.Map // telemetry of each pod, keyed by pod UID
+	deltaUsage   sync.Map
+	maxSampleAge time.Duration // age after which a usage sample is reported stale
 }
 
-func NewDPDKTelemetryClient(logger logr.Logger) DPDKTelemetryClient {
+// NewDPDKTelemetryClient returns a client reporting the usage of DPDK lcores, samples older than maxSampleAge
+// are reported stale
+func NewDPDKTelemetryClient(logger logr.Logger, maxSampleAge time.Duration) DPDKTelemetryClient {
 	c := &dpdkTelemetryClientImpl{
-		log: logger,
+		log:          logger,
+		maxSampleAge: maxSampleAge,
 	}
 
 	return c
 }
 
 func (cl *dpdkTelemetryClientImpl) EnsureConnection(data *DPDKTelemetryConnectionData) {
-	cl.log.V(4).Info("ensuring connection", "podUID", data.PodUID, "watchedCPUs", data.WatchedCPUs)
-	ctx, cancel := context.WithCancel(context.Background())
+	cl.log.V(4).Info("ensuring connection", "podUID", data.PodUID, "container", data.ContainerName,
+		"watchedCPUs", data.WatchedCPUs, "socketPath", data.SocketPath)
 	podUID := data.PodUID
-	newConn := &dpdkTelemetryConnection{
-		podUID:      podUID,
-		watchedCPUs: data.WatchedCPUs,
-		deltaUsage:  &cl.deltaUsage,
-		log:         cl.log.WithValues("podUID", podUID),
-		cancelFunc:  cancel,
-	}
+	containers := map[string][]uint{data.ContainerName: data.WatchedCPUs}
 
-	if _, present := cl.connections.LoadOrStore(podUID, newConn); !present {
-		if testHookNewSocketConnection != nil {
-			testHookNewSocketConnection(newConn)
-		} else {
-			newConn.waitGroup.Add(1)
-			go newConn.connect(ctx)
+	if existing, present := cl.connections.Load(podUID); present {
+		pod := existing.(*dpdkTelemetryPod)
+		if cpus, found := pod.containers[data.ContainerName]; found && slices.Equal(cpus, data.WatchedCPUs) &&
+			pod.socketPath == data.SocketPath {
+			return
+		}
+		// the sockets are shared by the pod's containers, start over with the new configuration
+		for name, cpus := range pod.containers {
+			if name != data.ContainerName {
+				containers[name] = cpus
+			}
 		}
+		cl.log.V(4).Info("telemetry configuration changed, reconnecting", "podUID", podUID)
+		pod.close()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	newPod := &dpdkTelemetryPod{
+		podUID:     podUID,
+		containers: containers,
+		socketPath: data.SocketPath,
+		deltaUsage: &cl.deltaUsage,
+		log:        cl.log.WithValues("podUID", podUID),
+		cancelFunc: cancel,
+	}
+	cl.connections.Store(podUID, newPod)
+	if testHookNewSocketConnection != nil {
+		testHookNewSocketConnection(newPod)
+	} else {
+		newPod.waitGroup.Add(1)
+		go newPod.discover(ctx)
 	}
 }
 
@@ -116,11 +172,17 @@ func (cl *dpdkTelemetryClientImpl) ListConnections() []DPDKTelemetryConnectionDa
 	dataList := make([]DPDKTelemetryConnectionData, 0)
 
 	cl.connections.Range(func(key, value any) bool {
-		connection := value.(*dpdkTelemetryConnection)
-		dataList = append(dataList, DPDKTelemetryConnectionData{
-			PodUID:      connection.podUID,
-			WatchedCPUs: connection.watchedCPUs,
-		})
+		pod := value.(*dpdkTelemetryPod)
+		for name, cpus := range pod.containers {
+			dataList = append(dataList, DPDKTelemetryConnectionData{
+				PodUID:        pod.podUID,
+				ContainerName: name,
+				WatchedCPUs:   cpus,
+				SocketPath:    pod.socketPath,
+				Connected:     pod.connected(),
+				Degraded:      pod.degraded(),
+			})
+		}
 		return true
 	})
 
@@ -128,9 +190,8 @@ func (cl *dpdkTelemetryClientImpl) ListConnections() []DPDKTelemetryConnectionDa
 }
 
 func (cl *dpdkTelemetryClientImpl) CloseConnection(podUID string) {
-	if connection, found := cl.connections.LoadAndDelete(podUID); found {
-		connection := connection.(*dpdkTelemetryConnection)
-		connection.close()
+	if pod, found := cl.connections.LoadAndDelete(podUID); found {
+		pod.(*dpdkTelemetryPod).close()
 		cl.log.V(4).Info("stopped the connection.", "podUID", podUID)
 	} else {
 		cl.log.V(4).Info("connection does not exist.", "podUID", podUID)
@@ -140,6 +201,10 @@ func (cl *dpdkTelemetryClientImpl) CloseConnection(podUID string) {
 func (cl *dpdkTelemetryClientImpl) GetUsagePercent(cpuID uint) (int, error) {
 	if value, found := cl.deltaUsage.Load(cpuID); found {
 		r := value.(telemetryResult)
+		// the application may hang while its telemetry socket stays open
+		if r.err == nil && getCurrentTimestamp().Sub(r.timestamp) > cl.maxSampleAge {
+			return r.percent, ErrDPDKMetricStale
+		}
 		return r.percent, r.err
 	}
 
@@ -150,8 +215,7 @@ func (cl *dpdkTelemetryClientImpl) Close() {
 	cl.log.V(4).Info("stopping all connection loops.")
 
 	cl.connections.Range(func(key, value any) bool {
-		connection := value.(*dpdkTelemetryConnection)
-		connection.close()
+		value.(*dpdkTelemetryPod).close()
 		cl.connections.Delete(key.(string))
 		return true
 	})
@@ -159,34 +223,135 @@ func (cl *dpdkTelemetryClientImpl) Close() {
 	cl.log.V(4).Info("all connection loops stopped.")
 }
 
+// dpdkTelemetryPod tracks the telemetry sockets of a pod, one connection is kept per DPDK process
+type dpdkTelemetryPod struct {
+	podUID      string
+	containers  map[string][]uint // CPUs watched for each DPDK container of the pod
+	socketPath  string            // configured socket relative to the pod's DPDK directory, discovered if empty
+	deltaUsage  *sync.Map         // latest windowed usage percent per CPU
+	cpuSockets  sync.Map          // socket reporting the usage of each CPU
+	connections sync.Map          // connection of each socket, keyed by socket path
+	log         logr.Logger
+	waitGroup   sync.WaitGroup
+	cancelFunc  func()
+}
+
+func (p *dpdkTelemetryPod) close() {
+	if testHookCloseConnection != nil {
+		testHookCloseConnection()
+		return
+	}
+
+	p.cancelFunc()
+	p.waitGroup.Wait()
+	for _, cpuID := range p.watchedCPUs() {
+		p.deltaUsage.Delete(cpuID)
+		p.cpuSockets.Delete(cpuID)
+	}
+}
+
+// connected reports whether any telemetry socket of the pod is connected
+func (p *dpdkTelemetryPod) connected() bool {
+	connected := false
+	p.connections.Range(func(key, value any) bool {
+		connected = value.(*dpdkTelemetryConnection).connected.Load()
+		return !connected
+	})
+	return connected
+}
+
+// degraded reports whether the circuit breaker of any telemetry socket of the pod is open
+func (p *dpdkTelemetryPod) degraded() bool {
+	degraded := false
+	p.connections.Range(func(key, value any) bool {
+		degraded = value.(*dpdkTelemetryConnection).breaker.isOpen()
+		return !degraded
+	})
+	return degraded
+}
+
+// watchedCPUs returns the CPUs of all DPDK containers of the pod
+func (p *dpdkTelemetryPod) watchedCPUs() []uint {
+	return mergeContainerCPUs(p.containers)
+}
+
+// discover connects to new telemetry sockets of the pod until the context is cancelled
+func (p *dpdkTelemetryPod) discover(ctx context.Context) {
+	defer p.waitGroup.Done()
+
+	for {
+		for _, path := range p.socketPaths() {
+			if _, found := p.connections.Load(path); !found {
+				p.startConnection(ctx, path)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDuration):
+		}
+	}
+}
+
+// socketPaths returns the configured telemetry socket of the pod, or the sockets found in the
+// runtime directories below the pod's DPDK directory, one per DPDK file prefix
+func (p *dpdkTelemetryPod) socketPaths() []string {
+	dir := filepath.Join(podsBasePath, p.podUID, podDPDKDir)
+	if p.socketPath != "" {
+		return []string{filepath.Join(dir, p.socketPath)}
+	}
+	// secondary processes sharing a runtime directory append ":<instance>" to the socket name
+	paths, _ := filepath.Glob(filepath.Join(dir, "*", TelemetrySocketName+"*"))
+	// the runtime directory itself may be mounted as the pod's DPDK directory
+	direct, _ := filepath.Glob(filepath.Join(dir, TelemetrySocketName+"*"))
+	return append(paths, direct...)
+}
+
+func (p *dpdkTelemetryPod) startConnection(ctx context.Context, path string) {
+	p.log.V(4).Info("found telemetry socket", "socketPath", path)
+	conn := &dpdkTelemetryConnection{
+		socketPath:  path,
+		discovered:  p.socketPath == "",
+		watchedCPUs: p.watchedCPUs(),
+		deltaUsage:  p.deltaUsage,
+		cpuSockets:  &p.cpuSockets,
+		log:         p.log.WithValues("socketPath", path),
+	}
+	p.connections.Store(path, conn)
+	p.waitGroup.Add(1)
+	go func() {
+		defer p.waitGroup.Done()
+		conn.waitGroup.Add(1)
+		conn.connect(ctx)
+		// a removed socket is picked up again if the process is restarted
+		conn.releaseCPUs()
+		p.connections.Delete(path)
+	}()
+}
+
 type dpdkTelemetryConnection struct {
-	podUID          string
+	socketPath      string
+	discovered      bool // the connection is dropped once the socket is removed
 	watchedCPUs     []uint
-	prevUsageCycles sync.Map  // last cumulative usage counters per CPU
-	deltaUsage      *sync.Map // latest windowed usage percent per CPU
+	prevUsageCycles sync.Map        // last cumulative usage counters per CPU
+	deltaUsage      *sync.Map       // latest windowed usage percent per CPU
+	cpuSockets      *sync.Map       // socket reporting the usage of each CPU, shared by the pod's connections
+	lcoreCPUs       map[uint][]uint // CPUs each lcore may run on, queried once per socket connection
+	breaker         circuitBreaker  // degrades the CPUs of the socket while it fails to provide fresh samples
+	connected       atomic.Bool
 	buffer          []byte
 	log             logr.Logger
 	waitGroup       sync.WaitGroup
-	cancelFunc      func()
 }
 
 // telemetryResult represents the latest windowed CPU usage sample.
 // percent: 0–100 computed from delta(busy_cycles)/delta(total_cycles) over samplePeriod.
-// err: non-nil when the sample is unavailable
+// err: non-nil when the sample is unavailable.
+// timestamp: time of the last sample in which the counters of the CPU advanced
 type telemetryResult struct {
-	percent int
-	err     error
-}
-
-func (c *dpdkTelemetryConnection) close() {
-	if testHookCloseConnection != nil {
-		testHookCloseConnection()
-		return
-	}
-
-	c.cancelFunc()
-	c.waitGroup.Wait()
-	c.clearMetrics(c.watchedCPUs)
+	percent   int
+	err       error
+	timestamp time.Time
 }
 
 func (c *dpdkTelemetryConnection) connect(ctx context.Context) {
@@ -204,26 +369,29 @@ func (c *dpdkTelemetryConnection) connect(ctx context.Context) {
 			return
 		}
 		if err := c.ioLoop(ctx, conn); err != nil {
-			c.clearMetrics(c.watchedCPUs)
+			c.releaseCPUs()
 			c.log.Error(err, "connection closed")
+			c.recordFailure(err)
 		}
 	}
 }
 
 func (c *dpdkTelemetryConnection) connectLoop(ctx context.Context) net.Conn {
-	address := fmt.Sprintf(baseSocketPath, c.podUID)
-
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-time.After(retryDuration):
-			conn, err := connectWithTimeoutFunc(address, ioTimeout)
+			conn, err := connectWithTimeoutFunc(c.socketPath, ioTimeout)
 			if err == nil {
 				c.log.V(4).Info("connection opened")
 				return conn
 			}
 			if strings.Contains(err.Error(), "no such file or directory") {
+				if c.discovered {
+					c.log.V(4).Info("dpdk telemetry socket removed")
+					return nil
+				}
 				c.log.Error(err, "dpdk telemetry socket not found")
 			}
 		}
@@ -237,6 +405,10 @@ func (c *dpdkTelemetryConnection) ioLoop(ctx context.Context, conn net.Conn) err
 	if err := c.handleInitialMessage(conn); err != nil {
 		return err
 	}
+	c.connected.Store(true)
+	defer c.connected.Store(false)
+	// lcores of a restarted application may be mapped differently
+	c.lcoreCPUs = map[uint][]uint{}
 
 	ticker := time.NewTicker(samplePeriod)
 	defer ticker.Stop()
@@ -317,7 +489,10 @@ func (c *dpdkTelemetryConnection) handleInitialMessage(conn net.Conn) error {
 
 // handleUsage polls the DPDK usage endpoint, computes per-CPU windowed usage
 // (percent over samplePeriod) from cumulative counters, and updates deltaUsage.
-// If a sample is unavailable for a CPU, stores an error for that CPU.
+// Lcores are mapped to the CPUs they run on, as applications may remap them with --lcores.
+// If a sample is unavailable for a CPU, stores an error for that CPU. Polls in which the counters of
+// the CPUs do not advance count as failures of the socket, while its circuit breaker is open
+// the CPUs report ErrDPDKTelemetryDegraded.
 func (c *dpdkTelemetryConnection) handleUsage(conn net.Conn) error {
 	if testHookHandleMetricsLoop != nil {
 		return testHookHandleMetricsLoop()
@@ -327,17 +502,41 @@ func (c *dpdkTelemetryConnection) handleUsage(conn net.Conn) error {
 	if err := c.processCommand(conn, usageCommand, &res); err != nil {
 		return fmt.Errorf("usage error: %w", err)
 	}
+	if err := c.updateLcoreCPUs(conn, res.Usage.LcoreIDs); err != nil {
+		return err
+	}
 
-	lcoreIDs := map[uint]int{}
-
-	for index, cpuID := range res.Usage.LcoreIDs {
-		lcoreIDs[cpuID] = index
+	// index of the usage entry of each CPU, and errors of CPUs shared by a floating lcore
+	cpuIndexes := map[uint]int{}
+	cpuErrors := map[uint]error{}
+	for index, lcoreID := range res.Usage.LcoreIDs {
+		cpuSet := c.lcoreCPUs[lcoreID]
+		if len(cpuSet) == 1 {
+			cpuIndexes[cpuSet[0]] = index
+			continue
+		}
+		for _, cpuID := range cpuSet {
+			cpuErrors[cpuID] = fmt.Errorf("%w: lcore %d runs on cpus %v", ErrDPDKLcoreFloating, lcoreID, cpuSet)
+		}
 	}
 
+	now := getCurrentTimestamp()
+	results := map[uint]telemetryResult{}
+	// whether the counters of any CPU advanced, or stood still
+	fresh, stalled := false, false
 	for _, cpuID := range c.watchedCPUs {
-		result := telemetryResult{}
+		result := telemetryResult{timestamp: now}
+		_, floating := cpuErrors[cpuID]
+		_, reported := cpuIndexes[cpuID]
+		// with several DPDK processes in the pod, each CPU is reported by the first socket providing it
+		if !c.claimCPU(cpuID, floating || reported) {
+			continue
+		}
 
-		if index, found := lcoreIDs[cpuID]; found {
+		if err, found := cpuErrors[cpuID]; found {
+			result.err = err
+			c.prevUsageCycles.Delete(cpuID)
+		} else if index, found := cpuIndexes[cpuID]; found {
 			currTotal := res.Usage.TotalCycles[index]
 			currBusy := res.Usage.BusyCycles[index]
 
@@ -349,12 +548,17 @@ func (c *dpdkTelemetryConnection) handleUsage(conn net.Conn) error {
 					deltaBusy := currBusy - prevSnap.busy
 
 					if deltaTotal == 0 {
+						// the sample is no newer than the last one in which the counters advanced
 						result.percent = 0
+						result.timestamp = c.lastSampleTime(cpuID, now)
+						stalled = true
 					} else {
 						percent := (deltaBusy * 100) / deltaTotal
 						// Inconsistent sample. Skip this tick.
 						if percent > 100 {
 							result.err = ErrDPDKMetricNotProvided
+						} else {
+							fresh = true
 						}
 						result.percent = int(percent)
 					}
@@ -372,15 +576,98 @@ func (c *dpdkTelemetryConnection) handleUsage(conn net.Conn) error {
 		} else {
 			result.err = ErrDPDKMetricNotProvided
 		}
+		results[cpuID] = result
+	}
+
+	switch {
+	case fresh:
+		if c.breaker.recordFresh() {
+			c.log.Info("dpdk telemetry recovered, scaling its cpus again")
+		}
+	case stalled:
+		c.recordFailure(errors.New("usage counters did not advance"))
+	}
+	degraded := c.breaker.isOpen()
+	for cpuID, result := range results {
+		if degraded && result.err == nil {
+			result.err = ErrDPDKTelemetryDegraded
+		}
 		c.deltaUsage.Store(cpuID, result)
 	}
 
 	return nil
 }
 
-func (c *dpdkTelemetryConnection) clearMetrics(cpuList []uint) {
-	for _, cpuID := range cpuList {
+// lastSampleTime returns the timestamp of the stored usage of cpuID, or now if there is none
+func (c *dpdkTelemetryConnection) lastSampleTime(cpuID uint, now time.Time) time.Time {
+	if value, found := c.deltaUsage.Load(cpuID); found {
+		if prev, ok := value.(telemetryResult); ok && !prev.timestamp.IsZero() {
+			return prev.timestamp
+		}
+	}
+	return now
+}
+
+// recordFailure counts a failed poll of the socket, degrading its CPUs once the circuit breaker opens
+func (c *dpdkTelemetryConnection) recordFailure(err error) {
+	if c.breaker.recordFailure() {
+		c.log.Info("dpdk telemetry degraded, its cpus run at the fallback frequency", "reason", err.Error())
+	}
+}
+
+// updateLcoreCPUs queries the CPU set of lcores seen for the first time on this connection.
+// Lcores without info are remembered with an empty CPU set and never reported
+func (c *dpdkTelemetryConnection) updateLcoreCPUs(conn net.Conn, lcoreIDs []uint) error {
+	if c.lcoreCPUs == nil {
+		c.lcoreCPUs = map[uint][]uint{}
+	}
+	for _, lcoreID := range lcoreIDs {
+		if _, known := c.lcoreCPUs[lcoreID]; known {
+			continue
+		}
+		var res lcoreInfoResponse
+		if err := c.processCommand(conn, fmt.Sprintf("%s,%d", infoCommand, lcoreID), &res); err != nil {
+			return fmt.Errorf("lcore info error: %w", err)
+		}
+		if res.Info == nil {
+			c.log.Info("dpdk telemetry did not provide info for lcore", "lcore", lcoreID)
+			c.lcoreCPUs[lcoreID] = []uint{}
+			continue
+		}
+		if len(res.Info.CPUSet) > 1 {
+			c.log.Info("dpdk lcore floats across several cpus, its usage is not reported", "lcore", lcoreID, "cpus", res.Info.CPUSet)
+		} else {
+			c.log.V(4).Info("mapped dpdk lcore", "lcore", lcoreID, "cpus", res.Info.CPUSet)
+		}
+		c.lcoreCPUs[lcoreID] = res.Info.CPUSet
+	}
+	return nil
+}
+
+// claimCPU reports whether this socket stores the usage of cpuID. A CPU reported by the socket is claimed
+// unless another socket of the pod reports it already, unreported CPUs are only stored if no socket claimed them
+func (c *dpdkTelemetryConnection) claimCPU(cpuID uint, reported bool) bool {
+	if c.cpuSockets == nil {
+		return true
+	}
+	if !reported {
+		owner, found := c.cpuSockets.Load(cpuID)
+		return !found || owner == c.socketPath
+	}
+	owner, _ := c.cpuSockets.LoadOrStore(cpuID, c.socketPath)
+	return owner == c.socketPath
+}
+
+// releaseCPUs clears the usage of the CPUs stored by this socket so other sockets of the pod may claim them
+func (c *dpdkTelemetryConnection) releaseCPUs() {
+	for _, cpuID := range c.watchedCPUs {
+		if !c.claimCPU(cpuID, false) {
+			continue
+		}
 		c.deltaUsage.Delete(cpuID)
 		c.prevUsageCycles.Delete(cpuID)
+		if c.cpuSockets != nil {
+			c.cpuSockets.CompareAndDelete(cpuID, c.socketPath)
+		}
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -46,6 +48,19 @@ func (mc *MockConn) Close() error {
 
 type metricsMap map[uint]telemetryResult
 
+// sampleTime is the time of the usage samples taken while the clock is fixed by setSampleTime
+var sampleTime = time.Unix(1000, 0)
+
+func setSampleTime(t *testing.T, now *time.Time) {
+	origTimestampFunc := getCurrentTimestamp
+	t.Cleanup(func() {
+		getCurrentTimestamp = origTimestampFunc
+	})
+	getCurrentTimestamp = func() time.Time {
+		return *now
+	}
+}
+
 var (
 	usageOutputString = `{
 		"/eal/lcore/usage": {
@@ -65,7 +80,8 @@ func createNewDPDKTelemetryClient() dpdkTelemetryClientImpl {
 	))
 
 	return dpdkTelemetryClientImpl{
-		log: ctrl.Log.WithName("test-log"),
+		log:          ctrl.Log.WithName("test-log"),
+		maxSampleAge: DefaultTelemetryMaxAge,
 	}
 }
 
@@ -78,13 +94,14 @@ func TestDPDKTelemetryClient_EnsureConnection(t *testing.T) {
 	t.Cleanup(func() {
 		testHookNewSocketConnection = nil
 	})
-	testHookNewSocketConnection = func(conn *dpdkTelemetryConnection) {
-		usedUID = conn.podUID
-		usedCPUList = conn.watchedCPUs
+	testHookNewSocketConnection = func(pod *dpdkTelemetryPod) {
+		usedUID = pod.podUID
+		usedCPUList = pod.watchedCPUs()
 	}
 	newConnData := &DPDKTelemetryConnectionData{
-		PodUID:      expectedUID,
-		WatchedCPUs: expectedCPUList,
+		PodUID:        expectedUID,
+		ContainerName: "dpdk",
+		WatchedCPUs:   expectedCPUList,
 	}
 
 	cl := createNewDPDKTelemetryClient()
@@ -97,10 +114,11 @@ func TestDPDKTelemetryClient_EnsureConnection(t *testing.T) {
 func TestDPDKTelemetryClient_ListConnections(t *testing.T) {
 	expectedUID := "foo"
 	expectedCPUList := []uint{0, 1, 2, 3}
-	dummyConn := &dpdkTelemetryConnection{
-		podUID: expectedUID,
+	dummyConn := &dpdkTelemetryPod{
+		podUID:     expectedUID,
+		socketPath: "app/dpdk_telemetry.v2",
 	}
-	dummyConn.watchedCPUs = expectedCPUList
+	dummyConn.containers = map[string][]uint{"dpdk": expectedCPUList}
 
 	cl := createNewDPDKTelemetryClient()
 	cl.connections.Store(expectedUID, dummyConn)
@@ -109,7 +127,9 @@ func TestDPDKTelemetryClient_ListConnections(t *testing.T) {
 
 	assert.NotEmpty(t, connData)
 	assert.Equal(t, expectedUID, connData[0].PodUID)
+	assert.Equal(t, "dpdk", connData[0].ContainerName)
 	assert.Equal(t, expectedCPUList, connData[0].WatchedCPUs)
+	assert.Equal(t, "app/dpdk_telemetry.v2", connData[0].SocketPath)
 }
 
 func TestDPDKTelemetryClient_CloseConnection(t *testing.T) {
@@ -121,7 +141,7 @@ func TestDPDKTelemetryClient_CloseConnection(t *testing.T) {
 	testHookCloseConnection = func() {
 		closeCallled = true
 	}
-	dummyConn := &dpdkTelemetryConnection{}
+	dummyConn := &dpdkTelemetryPod{}
 
 	cl := createNewDPDKTelemetryClient()
 
@@ -136,6 +156,9 @@ func TestDPDKTelemetryClient_CloseConnection(t *testing.T) {
 }
 
 func TestDPDKTelemetryClient_GetUsagePercent(t *testing.T) {
+	now := sampleTime.Add(DefaultTelemetryMaxAge)
+	setSampleTime(t, &now)
+
 	tcases := []struct {
 		testCase    string
 		cpuID       uint
@@ -152,7 +175,7 @@ func TestDPDKTelemetryClient_GetUsagePercent(t *testing.T) {
 		{
 			testCase:    "Test Case 2 - Results with an error",
 			cpuID:       3,
-			usageResult: &telemetryResult{0, ErrDPDKMetricNotProvided},
+			usageResult: &telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
 			evalFn: func(v int, e error) bool {
 				return assert.ErrorIs(t, e, ErrDPDKMetricNotProvided)
 			},
@@ -160,11 +183,19 @@ func TestDPDKTelemetryClient_GetUsagePercent(t *testing.T) {
 		{
 			testCase:    "Test Case 3 - Usage",
 			cpuID:       2,
-			usageResult: &telemetryResult{42, nil},
+			usageResult: &telemetryResult{42, nil, sampleTime},
 			evalFn: func(v int, e error) bool {
 				return assert.NoError(t, e) && assert.Equal(t, 42, v)
 			},
 		},
+		{
+			testCase:    "Test Case 4 - Stale usage",
+			cpuID:       2,
+			usageResult: &telemetryResult{42, nil, sampleTime.Add(-time.Millisecond)},
+			evalFn: func(v int, e error) bool {
+				return assert.ErrorIs(t, e, ErrDPDKMetricStale)
+			},
+		},
 	}
 
 	for _, tc := range tcases {
@@ -195,7 +226,7 @@ func TestDPDKTelemetryClient_Close(t *testing.T) {
 	cl := createNewDPDKTelemetryClient()
 
 	for i := 0; i < connectionCount; i++ {
-		dummyConn := &dpdkTelemetryConnection{}
+		dummyConn := &dpdkTelemetryPod{}
 		cl.connections.Store(fmt.Sprint(i), dummyConn)
 	}
 
@@ -222,24 +253,29 @@ func createNewDPDKConnection() dpdkTelemetryConnection {
 	}
 }
 
-func TestDPDKConnection_close(t *testing.T) {
+func TestDPDKTelemetryPod_close(t *testing.T) {
 	cancelFuncCalled := false
 	cpuList := []uint{1, 2, 3}
 
-	dpdkConn := createNewDPDKConnection()
-	dpdkConn.watchedCPUs = cpuList
-	dpdkConn.cancelFunc = func() { cancelFuncCalled = true }
+	pod := &dpdkTelemetryPod{deltaUsage: &sync.Map{}}
+	pod.containers = map[string][]uint{"primary": cpuList[:2], "secondary": cpuList[2:]}
+	pod.cancelFunc = func() { cancelFuncCalled = true }
 	for _, cpuID := range cpuList {
-		dpdkConn.deltaUsage.Store(cpuID, nil)
+		pod.deltaUsage.Store(cpuID, nil)
+		pod.cpuSockets.Store(cpuID, "foo")
 	}
 
-	dpdkConn.close()
+	pod.close()
 
 	assert.True(t, cancelFuncCalled)
-	dpdkConn.deltaUsage.Range(func(key, value any) bool {
+	pod.deltaUsage.Range(func(key, value any) bool {
 		t.Error("Usage metrics map was not cleaned.")
 		return false
 	})
+	pod.cpuSockets.Range(func(key, value any) bool {
+		t.Error("CPU socket map was not cleaned.")
+		return false
+	})
 }
 
 func TestDPDKConnection_connect(t *testing.T) {
@@ -274,7 +310,7 @@ func TestDPDKConnection_connect(t *testing.T) {
 	}
 
 	dpdkConn := createNewDPDKConnection()
-	dpdkConn.podUID = "foo"
+	dpdkConn.socketPath = "foo"
 	dpdkConn.waitGroup.Add(1)
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -288,6 +324,7 @@ func TestDPDKConnection_connect(t *testing.T) {
 
 func TestDPDKConnection_ioLoop(t *testing.T) {
 	getMetricsCallCounter := 0
+	dpdkConn := createNewDPDKConnection()
 	t.Cleanup(func() {
 		testHookReadInitMsgReturn = nil
 		testHookHandleMetricsLoop = nil
@@ -296,6 +333,7 @@ func TestDPDKConnection_ioLoop(t *testing.T) {
 		return nil
 	}
 	testHookHandleMetricsLoop = func() error {
+		assert.True(t, dpdkConn.connected.Load())
 		getMetricsCallCounter++
 		if getMetricsCallCounter > 2 {
 			return fmt.Errorf("foo")
@@ -308,13 +346,13 @@ func TestDPDKConnection_ioLoop(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	t.Cleanup(cancel)
-	dpdkConn := createNewDPDKConnection()
 
 	err := dpdkConn.ioLoop(ctx, mkConn)
 
 	assert.NotNil(t, err)
 	assert.ErrorContains(t, err, "foo")
 	mkConn.AssertCalled(t, "Close")
+	assert.False(t, dpdkConn.connected.Load())
 }
 
 func TestDPDKConnection_connectLoop(t *testing.T) {
@@ -559,6 +597,8 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 	t.Cleanup(func() {
 		testHookProcessCommandReturn = nil
 	})
+	now := sampleTime
+	setSampleTime(t, &now)
 
 	tcases := []struct {
 		testCase         string
@@ -567,6 +607,7 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 		watchlist        []uint
 		prevUsageCycles  map[uint]cycleCounters
 		curUsageResponse usageResponse
+		lcoreCPUs        map[uint][]uint // cpuset reported per lcore, lcores run on the cpu of the same id if missing
 		err              error
 		evalFn           func(e error, expected metricsMap, b *sync.Map)
 	}{
@@ -581,7 +622,7 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 		{
 			testCase: "Test Case 2 - Usage not available",
 			expectedUsage: metricsMap{
-				1: telemetryResult{0, ErrDPDKMetricNotProvided},
+				1: telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
 			},
 			watchlist: []uint{1},
 			evalFn: func(e error, expected metricsMap, b *sync.Map) {
@@ -596,15 +637,15 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 		{
 			testCase: "Test Case 3 - Update usage readings",
 			initialUsage: metricsMap{
-				1: telemetryResult{88, nil},
-				2: telemetryResult{0, nil},
-				3: telemetryResult{56, nil},
+				1: telemetryResult{88, nil, sampleTime},
+				2: telemetryResult{0, nil, sampleTime},
+				3: telemetryResult{56, nil, sampleTime},
 			},
 			expectedUsage: metricsMap{
-				1: telemetryResult{88, nil},
-				2: telemetryResult{89, nil},
-				3: telemetryResult{0, ErrDPDKMetricNotProvided},
-				4: telemetryResult{0, ErrDPDKMetricNotProvided},
+				1: telemetryResult{88, nil, sampleTime},
+				2: telemetryResult{89, nil, sampleTime},
+				3: telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
+				4: telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
 			},
 			prevUsageCycles: map[uint]cycleCounters{
 				1: {total: 23846845490, busy: 21043446594},
@@ -627,14 +668,80 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 				}
 			},
 		},
+		{
+			testCase: "Test Case 4 - Lcores remapped to other cpus",
+			expectedUsage: metricsMap{
+				8:  telemetryResult{88, nil, sampleTime},
+				9:  telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
+				10: telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime},
+			},
+			prevUsageCycles: map[uint]cycleCounters{
+				8: {total: 23846845490, busy: 21043446594},
+			},
+			watchlist: []uint{8, 9, 10},
+			lcoreCPUs: map[uint][]uint{1: {8}, 2: {9}, 3: nil},
+			curUsageResponse: usageResponse{
+				Usage: usageData{
+					LcoreIDs:    []uint{1, 2, 3},
+					TotalCycles: []uint64{23846845590, 23900558914, 23900558914},
+					BusyCycles:  []uint64{21043446682, 21448837316, 21448837316},
+				},
+			},
+			evalFn: func(e error, expected metricsMap, b *sync.Map) {
+				assert.NoError(t, e)
+				for id, expVal := range expected {
+					val, found := b.Load(id)
+					assert.True(t, found)
+					assert.Equal(t, expVal, val.(telemetryResult))
+				}
+				// lcore ids are not cpu ids
+				_, found := b.Load(uint(1))
+				assert.False(t, found)
+			},
+		},
+		{
+			testCase: "Test Case 5 - Floating lcore",
+			prevUsageCycles: map[uint]cycleCounters{
+				4: {total: 23846845490, busy: 21043446594},
+			},
+			watchlist: []uint{4, 5},
+			lcoreCPUs: map[uint][]uint{1: {4, 5}},
+			curUsageResponse: usageResponse{
+				Usage: usageData{
+					LcoreIDs:    []uint{1},
+					TotalCycles: []uint64{23846845590},
+					BusyCycles:  []uint64{21043446682},
+				},
+			},
+			evalFn: func(e error, expected metricsMap, b *sync.Map) {
+				assert.NoError(t, e)
+				for _, id := range []uint{4, 5} {
+					val, found := b.Load(id)
+					assert.True(t, found)
+					assert.ErrorIs(t, val.(telemetryResult).err, ErrDPDKLcoreFloating)
+					assert.ErrorContains(t, val.(telemetryResult).err, "lcore 1 runs on cpus [4 5]")
+				}
+			},
+		},
 	}
 
 	for _, tc := range tcases {
 		t.Log(tc.testCase)
 
-		usedCommand := ""
+		usedCommands := []string{}
 		testHookProcessCommandReturn = func(cmd string) (any, error) {
-			usedCommand = cmd
+			usedCommands = append(usedCommands, cmd)
+			var lcoreID uint
+			if _, err := fmt.Sscanf(cmd, infoCommand+",%d", &lcoreID); err == nil {
+				cpuSet, found := tc.lcoreCPUs[lcoreID]
+				if !found {
+					cpuSet = []uint{lcoreID}
+				}
+				if cpuSet == nil {
+					return lcoreInfoResponse{}, nil
+				}
+				return lcoreInfoResponse{Info: &lcoreInfo{LcoreID: lcoreID, CPUSet: cpuSet}}, nil
+			}
 			return tc.curUsageResponse, tc.err
 		}
 
@@ -652,7 +759,266 @@ func TestDPDKConnection_handleUsage(t *testing.T) {
 
 		err := dpdkConn.handleUsage(mkConn)
 
-		assert.Equal(t, usageCommand, usedCommand)
+		assert.Equal(t, usageCommand, usedCommands[0])
+		// lcore info is queried once per lcore
+		for _, lcoreID := range tc.curUsageResponse.Usage.LcoreIDs {
+			assert.Contains(t, usedCommands, fmt.Sprintf("%s,%d", infoCommand, lcoreID))
+		}
 		tc.evalFn(err, tc.expectedUsage, dpdkConn.deltaUsage)
+		if err == nil {
+			commandCount := len(usedCommands)
+			assert.NoError(t, dpdkConn.handleUsage(mkConn))
+			assert.Len(t, usedCommands, commandCount+1)
+		}
+	}
+}
+
+func TestDPDKTelemetryPod_socketPaths(t *testing.T) {
+	origPodsBasePath := podsBasePath
+	t.Cleanup(func() {
+		podsBasePath = origPodsBasePath
+	})
+	podsBasePath = t.TempDir()
+	dir := filepath.Join(podsBasePath, "foo", podDPDKDir)
+	for _, prefix := range []string{"rte", "client", "no-telemetry"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, prefix), os.ModePerm))
+		if prefix != "no-telemetry" {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, prefix, TelemetrySocketName), nil, 0o644))
+		}
+	}
+
+	// secondary process sharing the runtime directory of the primary
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rte", TelemetrySocketName+":1"), nil, 0o644))
+
+	// every DPDK process of the pod is discovered
+	pod := &dpdkTelemetryPod{podUID: "foo"}
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "client", TelemetrySocketName),
+		filepath.Join(dir, "rte", TelemetrySocketName),
+		filepath.Join(dir, "rte", TelemetrySocketName+":1"),
+	}, pod.socketPaths())
+
+	// runtime directory mounted as the pod's DPDK directory
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, TelemetrySocketName), nil, 0o644))
+	assert.Contains(t, pod.socketPaths(), filepath.Join(dir, TelemetrySocketName))
+
+	// a configured socket is used even before it exists
+	pod.socketPath = "app/" + TelemetrySocketName
+	assert.Equal(t, []string{filepath.Join(dir, "app", TelemetrySocketName)}, pod.socketPaths())
+
+	pod = &dpdkTelemetryPod{podUID: "bar"}
+	assert.Empty(t, pod.socketPaths())
+}
+
+func TestDPDKTelemetryClient_EnsureConnection_reconfigure(t *testing.T) {
+	started := []*dpdkTelemetryPod{}
+	closed := 0
+	t.Cleanup(func() {
+		testHookNewSocketConnection = nil
+		testHookCloseConnection = nil
+	})
+	testHookNewSocketConnection = func(pod *dpdkTelemetryPod) {
+		started = append(started, pod)
+	}
+	testHookCloseConnection = func() {
+		closed++
+	}
+
+	cl := createNewDPDKTelemetryClient()
+	cl.EnsureConnection(&DPDKTelemetryConnectionData{PodUID: "foo", ContainerName: "primary", WatchedCPUs: []uint{1}})
+	cl.EnsureConnection(&DPDKTelemetryConnectionData{PodUID: "foo", ContainerName: "primary", WatchedCPUs: []uint{1}})
+	assert.Len(t, started, 1)
+	assert.Equal(t, 0, closed)
+
+	// containers of the same pod share its telemetry sockets
+	cl.EnsureConnection(&DPDKTelemetryConnectionData{PodUID: "foo", ContainerName: "secondary", WatchedCPUs: []uint{3, 2}})
+	assert.Len(t, started, 2)
+	assert.Equal(t, 1, closed)
+	assert.Equal(t, []uint{1, 2, 3}, started[1].watchedCPUs())
+	assert.ElementsMatch(t, []DPDKTelemetryConnectionData{
+		{PodUID: "foo", ContainerName: "primary", WatchedCPUs: []uint{1}},
+		{PodUID: "foo", ContainerName: "secondary", WatchedCPUs: []uint{3, 2}},
+	}, cl.ListConnections())
+
+	// a changed socket replaces the connection
+	cl.EnsureConnection(&DPDKTelemetryConnectionData{PodUID: "foo", ContainerName: "primary", WatchedCPUs: []uint{1}, SocketPath: "app/dpdk_telemetry.v2"})
+	assert.Len(t, started, 3)
+	assert.Equal(t, 2, closed)
+	assert.Equal(t, "app/dpdk_telemetry.v2", started[2].socketPath)
+	assert.Equal(t, []uint{1, 2, 3}, started[2].watchedCPUs())
+
+	cl.CloseConnection("foo")
+	assert.Equal(t, 3, closed)
+	assert.Empty(t, cl.ListConnections())
+}
+
+func TestDPDKConnection_claimCPU(t *testing.T) {
+	t.Cleanup(func() {
+		testHookProcessCommandReturn = nil
+	})
+	now := sampleTime
+	setSampleTime(t, &now)
+	response := usageResponse{}
+	testHookProcessCommandReturn = func(cmd string) (any, error) {
+		var lcoreID uint
+		if _, err := fmt.Sscanf(cmd, infoCommand+",%d", &lcoreID); err == nil {
+			return lcoreInfoResponse{Info: &lcoreInfo{LcoreID: lcoreID, CPUSet: []uint{lcoreID}}}, nil
+		}
+		return response, nil
+	}
+
+	// two DPDK processes of the same pod, each reporting one of the watched CPUs
+	pod := &dpdkTelemetryPod{deltaUsage: &sync.Map{}}
+	newConn := func(path string) *dpdkTelemetryConnection {
+		conn := createNewDPDKConnection()
+		conn.socketPath = path
+		conn.watchedCPUs = []uint{1, 2, 3}
+		conn.deltaUsage = pod.deltaUsage
+		conn.cpuSockets = &pod.cpuSockets
+		return &conn
+	}
+	server, client := newConn("server"), newConn("client")
+	server.prevUsageCycles.Store(uint(1), cycleCounters{total: 100, busy: 50})
+	client.prevUsageCycles.Store(uint(2), cycleCounters{total: 100, busy: 50})
+
+	response = usageResponse{Usage: usageData{LcoreIDs: []uint{1}, TotalCycles: []uint64{200}, BusyCycles: []uint64{120}}}
+	assert.NoError(t, server.handleUsage(&MockConn{}))
+	response = usageResponse{Usage: usageData{LcoreIDs: []uint{2}, TotalCycles: []uint64{200}, BusyCycles: []uint64{80}}}
+	assert.NoError(t, client.handleUsage(&MockConn{}))
+
+	loadUsage := func(cpuID uint) telemetryResult {
+		value, found := pod.deltaUsage.Load(cpuID)
+		assert.True(t, found)
+		return value.(telemetryResult)
+	}
+	assert.Equal(t, telemetryResult{70, nil, sampleTime}, loadUsage(1))
+	assert.Equal(t, telemetryResult{30, nil, sampleTime}, loadUsage(2))
+	assert.Equal(t, telemetryResult{0, ErrDPDKMetricNotProvided, sampleTime}, loadUsage(3))
+	owner, _ := pod.cpuSockets.Load(uint(1))
+	assert.Equal(t, "server", owner)
+	owner, _ = pod.cpuSockets.Load(uint(2))
+	assert.Equal(t, "client", owner)
+
+	// releasing the server's CPUs keeps the client's readings
+	server.releaseCPUs()
+	_, found := pod.deltaUsage.Load(uint(1))
+	assert.False(t, found)
+	_, found = pod.cpuSockets.Load(uint(1))
+	assert.False(t, found)
+	assert.Equal(t, telemetryResult{30, nil, sampleTime}, loadUsage(2))
+}
+
+func TestDPDKConnection_connectLoop_socketRemoved(t *testing.T) {
+	origConnectFunc := connectWithTimeoutFunc
+	origRetryDuration := retryDuration
+	t.Cleanup(func() {
+		connectWithTimeoutFunc = origConnectFunc
+		retryDuration = origRetryDuration
+	})
+	connectWithTimeoutFunc = func(addr string, to time.Duration) (net.Conn, error) {
+		return nil, fmt.Errorf("dial unixpacket %s: connect: no such file or directory", addr)
+	}
+	retryDuration = time.Millisecond
+
+	// discovered sockets are dropped once removed
+	dpdkConn := createNewDPDKConnection()
+	dpdkConn.socketPath = "foo"
+	dpdkConn.discovered = true
+	ctx, cancel := context.WithCancel(context.TODO())
+	t.Cleanup(cancel)
+	assert.Nil(t, dpdkConn.connectLoop(ctx))
+}
+
+func TestDPDKConnection_handleUsage_stalled(t *testing.T) {
+	origTripPolls, origRecoveryPolls := breakerTripPolls, breakerRecoveryPolls
+	t.Cleanup(func() {
+		testHookProcessCommandReturn = nil
+		breakerTripPolls, breakerRecoveryPolls = origTripPolls, origRecoveryPolls
+	})
+	breakerTripPolls, breakerRecoveryPolls = 3, 2
+	now := sampleTime
+	setSampleTime(t, &now)
+
+	var total, busy uint64 = 1000, 500
+	testHookProcessCommandReturn = func(cmd string) (any, error) {
+		var lcoreID uint
+		if _, err := fmt.Sscanf(cmd, infoCommand+",%d", &lcoreID); err == nil {
+			return lcoreInfoResponse{Info: &lcoreInfo{LcoreID: lcoreID, CPUSet: []uint{lcoreID}}}, nil
+		}
+		return usageResponse{Usage: usageData{LcoreIDs: []uint{1}, TotalCycles: []uint64{total}, BusyCycles: []uint64{busy}}}, nil
 	}
+
+	cl := createNewDPDKTelemetryClient()
+	pod := &dpdkTelemetryPod{podUID: "foo", containers: map[string][]uint{"dpdk": {1}}, deltaUsage: &cl.deltaUsage}
+	conn := createNewDPDKConnection()
+	conn.watchedCPUs = []uint{1}
+	conn.deltaUsage = pod.deltaUsage
+	pod.connections.Store("foo", &conn)
+	cl.connections.Store("foo", pod)
+	poll := func(advance bool) {
+		now = now.Add(samplePeriod)
+		if advance {
+			total += 100
+			busy += 60
+		}
+		assert.NoError(t, conn.handleUsage(&MockConn{}))
+	}
+
+	poll(false)
+	poll(true)
+	usage, err := cl.GetUsagePercent(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, usage)
+	lastFresh := now
+
+	// counters standing still keep the time of the last fresh sample, until it is stale
+	poll(false)
+	_, err = cl.GetUsagePercent(1)
+	assert.NoError(t, err)
+	value, _ := cl.deltaUsage.Load(uint(1))
+	assert.Equal(t, lastFresh, value.(telemetryResult).timestamp)
+	now = lastFresh.Add(DefaultTelemetryMaxAge + time.Millisecond)
+	_, err = cl.GetUsagePercent(1)
+	assert.ErrorIs(t, err, ErrDPDKMetricStale)
+	assert.False(t, cl.ListConnections()[0].Degraded)
+
+	// the breaker opens after consecutive stalled polls and degrades fresh samples
+	poll(false)
+	poll(false)
+	assert.True(t, conn.breaker.isOpen())
+	assert.True(t, cl.ListConnections()[0].Degraded)
+	poll(true)
+	_, err = cl.GetUsagePercent(1)
+	assert.ErrorIs(t, err, ErrDPDKTelemetryDegraded)
+
+	// and closes once samples are fresh again for the recovery polls
+	poll(true)
+	usage, err = cl.GetUsagePercent(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, usage)
+	assert.False(t, cl.ListConnections()[0].Degraded)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	origTripPolls, origRecoveryPolls := breakerTripPolls, breakerRecoveryPolls
+	t.Cleanup(func() {
+		breakerTripPolls, breakerRecoveryPolls = origTripPolls, origRecoveryPolls
+	})
+	breakerTripPolls, breakerRecoveryPolls = 2, 2
+
+	breaker := circuitBreaker{}
+	assert.False(t, breaker.recordFailure())
+	// a fresh poll resets the failures
+	assert.False(t, breaker.recordFresh())
+	assert.False(t, breaker.recordFailure())
+	assert.True(t, breaker.recordFailure())
+	assert.True(t, breaker.isOpen())
+	assert.False(t, breaker.recordFailure())
+
+	// a failure while recovering starts the recovery over
+	assert.False(t, breaker.recordFresh())
+	assert.False(t, breaker.recordFailure())
+	assert.False(t, breaker.recordFresh())
+	assert.True(t, breaker.recordFresh())
+	assert.False(t, breaker.isOpen())
 }
@@ -0,0 +1,101 @@
+package scaling
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// factor applied to the gain when the frequency oscillates
+	gainDecrease = 0.5
+	// factor applied to the gain when the frequency follows the load slowly
+	gainIncrease = 1.25
+	// consecutive frequency changes in the same direction after which the response counts as sluggish
+	sluggishChanges = 3
+)
+
+// AdaptiveGainOpts adapts the gain of the controller of a CPU to its workload. The gain is lowered when the
+// direction of the frequency changes reverses MaxReversals times within Window, and raised when the frequency
+// moves in the same direction for several consecutive changes. The gain is a factor on the configured gains,
+// bounded by MinGain and MaxGain. The state is kept with the options, updated options restart from the
+// configured gains unless their settings are the same
+type AdaptiveGainOpts struct {
+	Window       time.Duration
+	MaxReversals int
+	MinGain      float64
+	MaxGain      float64
+
+	gain      float64     // factor on the configured gains, the configured gains apply until it is adapted
+	reversals []time.Time // times of the reversals within the window
+	direction int         // direction of the last frequency change, 1 up and -1 down
+	streak    int         // consecutive frequency changes in the same direction
+}
+
+// carryState keeps the adapted gain of prev if the settings did not change
+func (a *AdaptiveGainOpts) carryState(prev *AdaptiveGainOpts) {
+	if a == nil || prev == nil || a.Window != prev.Window || a.MaxReversals != prev.MaxReversals ||
+		a.MinGain != prev.MinGain || a.MaxGain != prev.MaxGain {
+		return
+	}
+	a.gain, a.direction, a.streak = prev.gain, prev.direction, prev.streak
+	a.reversals = slices.Clone(prev.reversals)
+}
+
+// factor returns the factor on the configured gains, 1 if the gain is not adapted
+func (a *AdaptiveGainOpts) factor() float64 {
+	if a == nil || a.gain == 0 {
+		return 1
+	}
+	return a.gain
+}
+
+// percent returns the adapted gain in percent of the configured gains, 0 if the gain is not adapted
+func (a *AdaptiveGainOpts) percent() int {
+	if a == nil {
+		return 0
+	}
+	return int(a.factor()*100 + 0.5)
+}
+
+// recordChange tracks the direction of a change of the target frequency and adapts the gain,
+// returning whether it changed
+func (a *AdaptiveGainOpts) recordChange(prevFrequency, nextFrequency int, now time.Time) bool {
+	if a == nil || prevFrequency < 0 || nextFrequency == prevFrequency {
+		return false
+	}
+	direction := 1
+	if nextFrequency < prevFrequency {
+		direction = -1
+	}
+
+	if direction == a.direction {
+		a.streak++
+	} else {
+		if a.direction != 0 {
+			a.reversals = append(a.reversals, now)
+		}
+		a.direction = direction
+		a.streak = 1
+	}
+	// forget the reversals that left the window
+	expired := 0
+	for expired < len(a.reversals) && now.Sub(a.reversals[expired]) > a.Window {
+		expired++
+	}
+	a.reversals = a.reversals[expired:]
+
+	gain := a.factor()
+	switch {
+	case len(a.reversals) >= a.MaxReversals:
+		gain = max(gain*gainDecrease, a.MinGain)
+		a.reversals = nil
+	case a.streak >= sluggishChanges:
+		gain = min(gain*gainIncrease, a.MaxGain)
+		a.streak = 0
+	default:
+		return false
+	}
+	changed := gain != a.factor()
+	a.gain = gain
+	return changed
+}
@@ -0,0 +1,130 @@
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveGainOpts_recordChange(t *testing.T) {
+	start := time.Unix(1000, 0)
+	newOpts := func() *AdaptiveGainOpts {
+		return &AdaptiveGainOpts{Window: time.Second, MaxReversals: 3, MinGain: 0.3, MaxGain: 1.5}
+	}
+
+	// the configured gains apply until the gain is adapted
+	var disabled *AdaptiveGainOpts
+	assert.False(t, disabled.recordChange(1000000, 2000000, start))
+	assert.Equal(t, 1.0, disabled.factor())
+	assert.Zero(t, disabled.percent())
+	assert.Equal(t, 100, newOpts().percent())
+
+	// reversals within the window lower the gain down to the minimum
+	gain := newOpts()
+	frequencies := []int{2000000, 2100000, 2000000, 2100000, 2000000}
+	for i := 1; i < len(frequencies); i++ {
+		changed := gain.recordChange(frequencies[i-1], frequencies[i], start.Add(time.Duration(i)*100*time.Millisecond))
+		assert.Equal(t, i == len(frequencies)-1, changed, "change %d", i)
+	}
+	assert.Equal(t, 50, gain.percent())
+	for i := 0; i < 3; i++ {
+		gain.recordChange(2000000, 2100000, start.Add(time.Second))
+		gain.recordChange(2100000, 2000000, start.Add(time.Second))
+	}
+	assert.Equal(t, 30, gain.percent())
+
+	// reversals leaving the window are forgotten
+	gain = newOpts()
+	gain.recordChange(2000000, 2100000, start)
+	gain.recordChange(2100000, 2000000, start)
+	gain.recordChange(2000000, 2100000, start)
+	assert.False(t, gain.recordChange(2100000, 2000000, start.Add(2*time.Second)))
+	assert.Len(t, gain.reversals, 1)
+	assert.Equal(t, 100, gain.percent())
+
+	// consecutive changes in the same direction raise the gain up to the maximum
+	gain = newOpts()
+	for frequency := 1000000; frequency < 2000000; frequency += 100000 {
+		gain.recordChange(frequency, frequency+100000, start)
+	}
+	assert.Equal(t, 150, gain.percent())
+
+	// the first target and unchanged frequencies are not changes
+	gain = newOpts()
+	assert.False(t, gain.recordChange(FrequencyNotYetSet, 2000000, start))
+	assert.False(t, gain.recordChange(2000000, 2000000, start))
+	assert.Zero(t, gain.direction)
+}
+
+func TestCPUScalingUpdater_Update_adaptiveGain(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cpu := &simCPU{frequency: 2000000, demand: 2000000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		CurrentTargetFrequency:     2000000,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		ScaleFactor:                1.0,
+		AdaptiveGain:               &AdaptiveGainOpts{Window: time.Second, MaxReversals: 2, MinGain: 0.25, MaxGain: 2},
+	}
+	updater := &cpuScalingUpdaterImpl{clock: func() time.Time { return now }}
+
+	// usage 100 at 2 GHz scales up by 25% with the full gain
+	updater.Update(opts)
+	assert.Equal(t, uint(2500000), cpu.frequency)
+	assert.Equal(t, now, opts.lastChange)
+
+	// the load flips between light and heavy, two reversals halve the gain
+	cpu.demand = 1000000 // usage 40
+	updater.Update(opts)
+	assert.Equal(t, uint(1250000), cpu.frequency)
+	cpu.demand = 1250000 // usage 100
+	updater.Update(opts)
+	assert.Equal(t, uint(1562500), cpu.frequency)
+	assert.Equal(t, 50, opts.AdaptiveGain.percent())
+
+	// the next change scales by half the error
+	cpu.demand = 625000 // usage 40
+	updater.Update(opts)
+	assert.Equal(t, uint(1171875), cpu.frequency)
+}
+
+func TestCPUScalingUpdater_updatePID_adaptiveGain(t *testing.T) {
+	cpu := &simCPU{frequency: 2000000, demand: 1000000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		CurrentTargetFrequency:     2000000,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		PID:                        &PIDOpts{ProportionalGain: 0.5},
+		AdaptiveGain:               &AdaptiveGainOpts{Window: time.Second, MaxReversals: 3, MinGain: 0.25, MaxGain: 1, gain: 0.5},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+
+	// usage 50 is 0.3 below the target, the proportional term moves by 0.5 * 0.5 * 0.3 of the 2 GHz range
+	updater.Update(opts)
+	assert.Equal(t, uint(1850000), cpu.frequency)
+
+	// a sluggish response raises the gain and restarts the controller from the current target
+	opts.AdaptiveGain.streak = sluggishChanges - 1
+	cpu.demand = 500000
+	updater.Update(opts)
+	assert.Equal(t, 63, opts.AdaptiveGain.percent())
+	assert.False(t, opts.PID.started)
+	target := opts.CurrentTargetFrequency
+	updater.Update(opts)
+	assert.True(t, opts.PID.started)
+	assert.Equal(t, target, opts.PID.baseFrequency)
+}
@@ -0,0 +1,82 @@
+package scaling
+
+import (
+	"maps"
+	"time"
+)
+
+// IdleCStatesOpts overrides the C-states of a CPU whose usage stays below UsageThreshold for IdlePeriod, e.g. to
+// enable the deeper C-states a polling workload keeps its CPUs out of at low load, and restores the C-states of
+// its profile as soon as the usage rises. The state is kept with the options, updated options with other settings
+// restore the C-states of the profile and wait for a new idle period
+type IdleCStatesOpts struct {
+	UsageThreshold int
+	IdlePeriod     time.Duration
+	// States are the C-states enabled (true) or disabled (false) while the CPU is idle
+	States map[string]bool
+
+	idleSince time.Time // start of the usage below the threshold, zero while the CPU is loaded
+	active    bool      // the C-states of the CPU are overridden
+}
+
+// isActive returns whether the C-states of the CPU are overridden
+func (i *IdleCStatesOpts) isActive() bool {
+	return i != nil && i.active
+}
+
+// sameSettings returns whether other sets the same idle C-states, both may be nil
+func (i *IdleCStatesOpts) sameSettings(other *IdleCStatesOpts) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return i.UsageThreshold == other.UsageThreshold && i.IdlePeriod == other.IdlePeriod && maps.Equal(i.States, other.States)
+}
+
+// carryState moves the state of prev if the settings did not change, so the override it set is kept
+// rather than restored with the replaced options
+func (i *IdleCStatesOpts) carryState(prev *IdleCStatesOpts) {
+	if i == nil || !i.sameSettings(prev) {
+		return
+	}
+	i.idleSince, i.active = prev.idleSince, prev.active
+	prev.idleSince, prev.active = time.Time{}, false
+}
+
+// updateIdleCStates overrides the C-states of the CPU once its usage stayed below the idle threshold for the
+// idle period, and restores the C-states of its profile when the usage reaches the threshold or is unavailable
+func (u *cpuScalingUpdaterImpl) updateIdleCStates(opts *CPUScalingOpts, usage int, usageErr error) {
+	idle := opts.IdleCStates
+	if idle == nil {
+		return
+	}
+	if usageErr != nil || usage >= idle.UsageThreshold {
+		idle.idleSince = time.Time{}
+		if !idle.active {
+			return
+		}
+		// stays active to retry at the next update
+		if err := opts.CPU.ClearCStatesOverride(); err != nil {
+			u.logger.Error(err, "failed to restore c-states", "cpu", opts.CPU.GetID())
+			return
+		}
+		idle.active = false
+		u.logger.V(5).Info("cpu loaded, restored c-states", "cpu", opts.CPU.GetID(), "usage", usage)
+		return
+	}
+
+	now := u.now()
+	if idle.idleSince.IsZero() {
+		idle.idleSince = now
+	}
+	if idle.active || now.Sub(idle.idleSince) < idle.IdlePeriod {
+		return
+	}
+	if err := opts.CPU.SetCStatesOverride(idle.States); err != nil {
+		u.logger.Error(err, "failed to set idle c-states", "cpu", opts.CPU.GetID())
+		// retried after another idle period rather than at every update
+		idle.idleSince = now
+		return
+	}
+	idle.active = true
+	u.logger.V(5).Info("cpu idle, set c-states", "cpu", opts.CPU.GetID(), "usage", usage, "c_states", idle.States)
+}
@@ -0,0 +1,121 @@
+package scaling
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+)
+
+// idleCPU is a simulated CPU keeping the C-states override set by the scaler
+type idleCPU struct {
+	simCPU
+	cStates  map[string]bool
+	setErr   error
+	setCalls int
+}
+
+func (c *idleCPU) SetCStatesOverride(states map[string]bool) error {
+	c.setCalls++
+	if c.setErr != nil {
+		return c.setErr
+	}
+	c.cStates = states
+	return nil
+}
+
+func (c *idleCPU) ClearCStatesOverride() error {
+	c.cStates = nil
+	return nil
+}
+
+func (c *idleCPU) ClearFrequencyConstraint(power.FrequencyRequester) error { return nil }
+
+// errUsageSource fails to read the usage of any CPU
+type errUsageSource struct {
+	err error
+}
+
+func (s *errUsageSource) GetUsagePercent(uint) (int, error) { return 0, s.err }
+
+func (s *errUsageSource) CloseConnection(string) {}
+
+func (s *errUsageSource) Close() {}
+
+func TestCPUScalingUpdater_updateIdleCStates(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cpu := &idleCPU{simCPU: simCPU{frequency: 1000000, demand: 50000}}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		CurrentTargetFrequency:     1000000,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		FallbackFreq:               1000000,
+		ScaleFactor:                1.0,
+		IdleCStates:                &IdleCStatesOpts{UsageThreshold: 10, IdlePeriod: time.Second, States: map[string]bool{"C6": true}},
+	}
+	updater := &cpuScalingUpdaterImpl{clock: func() time.Time { return now }}
+
+	// usage 5 is idle, the C-states are overridden once it stayed idle for the idle period
+	updater.Update(opts)
+	assert.Nil(t, cpu.cStates)
+	now = now.Add(500 * time.Millisecond)
+	updater.Update(opts)
+	assert.Nil(t, cpu.cStates)
+	now = now.Add(500 * time.Millisecond)
+	updater.Update(opts)
+	assert.Equal(t, map[string]bool{"C6": true}, cpu.cStates)
+	assert.True(t, opts.IdleCStates.isActive())
+	updater.Update(opts)
+	assert.Equal(t, 1, cpu.setCalls)
+
+	// the C-states of the profile are restored as soon as the load returns
+	cpu.demand = 500000
+	updater.Update(opts)
+	assert.Nil(t, cpu.cStates)
+	assert.False(t, opts.IdleCStates.isActive())
+
+	// a load spike restarts the idle period
+	cpu.frequency, cpu.demand = 1000000, 50000
+	updater.Update(opts)
+	now = now.Add(900 * time.Millisecond)
+	cpu.demand = 500000
+	updater.Update(opts)
+	cpu.frequency, cpu.demand = 1000000, 50000
+	updater.Update(opts)
+	now = now.Add(900 * time.Millisecond)
+	updater.Update(opts)
+	assert.Nil(t, cpu.cStates)
+	now = now.Add(100 * time.Millisecond)
+	updater.Update(opts)
+	assert.Equal(t, map[string]bool{"C6": true}, cpu.cStates)
+
+	// unavailable usage restores the C-states of the profile
+	opts.UsageSource = &errUsageSource{err: errors.New("no usage")}
+	updater.Update(opts)
+	assert.Nil(t, cpu.cStates)
+
+	// a failed override is retried after another idle period
+	opts.UsageSource = cpu
+	cpu.setErr = errors.New("not supported")
+	cpu.setCalls = 0
+	updater.Update(opts)
+	now = now.Add(time.Second)
+	updater.Update(opts)
+	assert.Equal(t, 1, cpu.setCalls)
+	updater.Update(opts)
+	assert.Equal(t, 1, cpu.setCalls)
+	assert.False(t, opts.IdleCStates.isActive())
+	now = now.Add(time.Second)
+	updater.Update(opts)
+	assert.Equal(t, 2, cpu.setCalls)
+}
@@ -1,9 +1,11 @@
 package scaling
 
 import (
+	"container/heap"
 	"context"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,111 +14,309 @@ import (
 	"github.com/intel/power-optimization-library/pkg/power"
 )
 
-// Func definitions for unit testing
 var (
-	newCPUScalingWorkerFunc = NewCPUScalingWorker
+	// Updates due within the same tick are run in one batch
+	schedulerTick = time.Millisecond
+	// Wait of the scheduler while no CPU is scaled, AddCPUScaling wakes it up earlier
+	schedulerIdleWait = time.Second
+
+	getSchedulerTime = time.Now
 )
 
 type CPUScalingManager interface {
 	manager.Runnable
 	AddCPUScaling(optList []CPUScalingOpts)
 	RemoveCPUScaling(cpuIDs []uint)
+	GetCPUScalingStatus(cpuIDs []uint) []CPUScalingStatus
 }
 
+// cpuScalingManagerImpl runs the frequency updates of all scaled CPUs from a single scheduler.
+// The CPUs are kept in a queue ordered by the time of their next update, at each tick the updates
+// due are run in one batch and rescheduled after the duration returned by the updater.
 type cpuScalingManagerImpl struct {
 	powerLibrary *power.Host
-	dpdkClient   DPDKTelemetryClient
-	workers      sync.Map
-	logger       logr.Logger
+	updater      CPUScalingUpdater
+	mutex        sync.Mutex
+	entries      map[uint]*scalingEntry
+	queue        scalingQueue
+	// options of CPUs no longer scaled whose actuator is released by the scheduler,
+	// so the release does not race with an update in progress
+	released []*CPUScalingOpts
+	// options replaced by AddCPUScaling whose state is carried over by the scheduler, for the same reason
+	replaced []replacedOpts
+	// records the usage samples read by the updates if set
+	recorder *UsageRecorder
+	wakeUp   chan struct{}
+	logger   logr.Logger
 }
 
-func NewCPUScalingManager(powerLib *power.Host, dpdkClient DPDKTelemetryClient) CPUScalingManager {
+// NewCPUScalingManager creates the scheduler of the scaled CPUs, the usage samples it reads are
+// written to recorder unless it is nil
+func NewCPUScalingManager(powerLib *power.Host, recorder *UsageRecorder) CPUScalingManager {
 	nodeName := os.Getenv("NODE_NAME")
 
 	mgr := &cpuScalingManagerImpl{
 		powerLibrary: powerLib,
-		dpdkClient:   dpdkClient,
+		updater:      NewCPUScalingUpdater(),
+		recorder:     recorder,
+		entries:      map[uint]*scalingEntry{},
+		wakeUp:       make(chan struct{}, 1),
 		logger:       ctrl.Log.WithName("CPUScalingManager").WithName(nodeName),
 	}
 
 	return mgr
 }
 
+// Start runs the scheduler until the context is cancelled
 func (s *cpuScalingManagerImpl) Start(ctx context.Context) error {
-	<-ctx.Done()
-	s.stop()
-	return nil
+	s.logger.V(5).Info("starting scheduler")
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.stop()
+			return nil
+		case <-s.wakeUp:
+		case <-timer.C:
+		}
+		timer.Reset(s.runDue(getSchedulerTime()))
+	}
 }
 
-func (s *cpuScalingManagerImpl) stop() {
-	s.logger.V(5).Info("stopping all workers")
+// replacedOpts are the options of a CPU replaced by updated ones
+type replacedOpts struct {
+	prev, next *CPUScalingOpts
+}
 
-	managedCPUs := s.getManagedCPUIDs()
+func (s *cpuScalingManagerImpl) stop() {
+	s.logger.V(5).Info("stopping scaling of all CPUs")
 
-	for _, cpuID := range managedCPUs {
-		worker, found := s.workers.LoadAndDelete(cpuID)
-		if found {
-			worker := worker.(CPUScalingWorker)
-			worker.Stop()
-			s.logger.V(5).Info("worker stopped successfully", "cpuID", cpuID)
-		}
+	s.mutex.Lock()
+	for _, entry := range s.entries {
+		s.released = append(s.released, entry.opts)
 	}
+	clear(s.entries)
+	s.queue = nil
+	s.replaced = nil
+	s.mutex.Unlock()
+	s.releaseActuators()
 
 	s.logger.V(5).Info("successfully stopped all")
 }
 
-// AddCPUScaling creates or updates per-CPU scaling workers for the given CPUs.
-// Existing workers for other CPUs are not affected. Each worker runs continuously
-// and tunes the CPU's frequency based on the provided options and real-time usage
-// from the DPDK telemetry client.
+// AddCPUScaling schedules the scaling of the given CPUs, or updates the options of CPUs
+// already scaled, keeping the state which still applies to the updated options. Other CPUs are not affected. Each CPU's frequency is tuned based on the
+// provided options and real-time usage from the usage source set in the options.
 func (s *cpuScalingManagerImpl) AddCPUScaling(optsList []CPUScalingOpts) {
+	s.mutex.Lock()
+	now := getSchedulerTime()
 	for _, opts := range optsList {
-		worker, found := s.getCPUScalingWorker(opts.CPU.GetID())
-		if !found {
-			s.logger.V(5).Info("creating worker", "cpuID", opts.CPU.GetID())
-			s.workers.Store(
-				opts.CPU.GetID(),
-				newCPUScalingWorkerFunc(
-					opts.CPU.GetID(),
-					s.powerLibrary,
-					s.dpdkClient,
-					&opts,
-				),
-			)
-		} else {
-			worker.UpdateOpts(&opts)
+		cpuID := opts.CPU.GetID()
+		if entry, found := s.entries[cpuID]; found {
+			if entry.opts.Actuator != opts.Actuator || !entry.opts.IdleCStates.sameSettings(opts.IdleCStates) {
+				s.released = append(s.released, entry.opts)
+			}
+			s.replaced = append(s.replaced, replacedOpts{prev: entry.opts, next: &opts})
+			entry.opts = &opts
+			continue
+		}
+		s.logger.V(5).Info("scheduling cpu", "cpuID", cpuID)
+		entry := &scalingEntry{
+			cpuID:  cpuID,
+			opts:   &opts,
+			due:    ceilToTick(now.Add(opts.SamplePeriod)),
+			status: CPUScalingStatus{CPUID: cpuID, TargetFrequency: FrequencyNotYetSet, GainPercent: opts.AdaptiveGain.percent()},
 		}
+		s.entries[cpuID] = entry
+		heap.Push(&s.queue, entry)
 	}
+	s.mutex.Unlock()
+
+	s.wake()
 }
 
-// RemoveCPUScaling stops scaling workers for the given CPU IDs.
+// wake makes the scheduler run without waiting for the next update, which may be later, or idle
+func (s *cpuScalingManagerImpl) wake() {
+	select {
+	case s.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+// RemoveCPUScaling stops scaling the given CPUs. Frequency limits or EPP set by their actuator,
+// and C-states set while they were idle, are released by the scheduler.
 func (s *cpuScalingManagerImpl) RemoveCPUScaling(cpuIDs []uint) {
+	s.mutex.Lock()
+	defer s.wake()
+	defer s.mutex.Unlock()
 	for _, cpuID := range cpuIDs {
-		worker, found := s.workers.LoadAndDelete(cpuID)
+		entry, found := s.entries[cpuID]
 		if !found {
-			s.logger.V(5).Info("worker does not exist", "cpuID", cpuID)
-		} else {
-			worker := worker.(CPUScalingWorker)
-			worker.Stop()
-			s.logger.V(5).Info("worker stopped successfully", "cpuID", cpuID)
+			s.logger.V(5).Info("cpu is not scaled", "cpuID", cpuID)
+			continue
+		}
+		delete(s.entries, cpuID)
+		s.released = append(s.released, entry.opts)
+		// entries of a running batch are out of the queue and not rescheduled
+		if entry.index >= 0 {
+			heap.Remove(&s.queue, entry.index)
 		}
+		s.logger.V(5).Info("cpu scaling stopped successfully", "cpuID", cpuID)
 	}
 }
 
-func (s *cpuScalingManagerImpl) getManagedCPUIDs() []uint {
-	managedCPUs := make([]uint, 0)
-	s.workers.Range(func(key, value any) bool {
-		managedCPUs = append(managedCPUs, key.(uint))
-		return true
-	})
+// runDue runs the updates due at now in one batch and reschedules them, returning
+// the duration until the next update is due
+func (s *cpuScalingManagerImpl) runDue(now time.Time) time.Duration {
+	type update struct {
+		entry *scalingEntry
+		opts  *CPUScalingOpts
+	}
+	batch := []update{}
+	s.carryReplacedState()
+	s.releaseActuators()
 
-	return managedCPUs
+	s.mutex.Lock()
+	for len(s.queue) > 0 && !s.queue[0].due.After(now) {
+		entry := heap.Pop(&s.queue).(*scalingEntry)
+		batch = append(batch, update{entry: entry, opts: entry.opts})
+	}
+	s.mutex.Unlock()
+
+	// updates run without the lock, so the controllers are not blocked by sysfs access
+	waits := make([]time.Duration, len(batch))
+	for i, u := range batch {
+		if s.recorder == nil {
+			waits[i] = s.updater.Update(u.opts)
+			continue
+		}
+		// the usage was measured at the frequency the CPU ran at before the update
+		frequency, _ := u.opts.CPU.GetCurrentCPUFrequency()
+		waits[i] = s.updater.Update(u.opts)
+		s.recordUsage(now, u.entry.cpuID, frequency, u.opts)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, u := range batch {
+		// removed during the batch
+		if s.entries[u.entry.cpuID] != u.entry {
+			continue
+		}
+		u.entry.due = ceilToTick(now.Add(waits[i]))
+		u.entry.status = CPUScalingStatus{
+			CPUID:           u.entry.cpuID,
+			TargetFrequency: u.opts.CurrentTargetFrequency,
+			Usage:           u.opts.usage,
+			UsageErr:        u.opts.usageErr,
+			LastChange:      u.opts.lastChange,
+			LastUpdate:      now,
+			GainPercent:     u.opts.AdaptiveGain.percent(),
+			Idle:            u.opts.IdleCStates.isActive(),
+		}
+		heap.Push(&s.queue, u.entry)
+	}
+	if len(s.queue) == 0 {
+		return schedulerIdleWait
+	}
+	return max(s.queue[0].due.Sub(getSchedulerTime()), 0)
+}
+
+// recordUsage writes the usage sample read by the last update of a CPU to the recorder
+func (s *cpuScalingManagerImpl) recordUsage(now time.Time, cpuID, frequency uint, opts *CPUScalingOpts) {
+	sample := UsageSample{Time: now, CPU: cpuID, Usage: opts.usage, Frequency: frequency}
+	if opts.usageErr != nil {
+		sample = UsageSample{Time: now, CPU: cpuID, Frequency: frequency, Error: opts.usageErr.Error()}
+	}
+	if err := s.recorder.Record(sample); err != nil {
+		s.logger.Error(err, "failed to record usage sample", "cpuID", cpuID)
+	}
+}
+
+// carryReplacedState carries the state of replaced options over to the options replacing them. The replaced
+// options are no longer updated once the batch which may have been using them has finished
+func (s *cpuScalingManagerImpl) carryReplacedState() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// in the order of replacement, so options replaced again before the scheduler ran pass the state on
+	for _, r := range s.replaced {
+		r.next.carryState(r.prev)
+	}
+	s.replaced = nil
+}
+
+// releaseActuators removes the frequency limits or EPP set by the actuators of CPUs no longer scaled, and the
+// C-states set while CPUs were idle by options which are no longer used
+func (s *cpuScalingManagerImpl) releaseActuators() {
+	s.mutex.Lock()
+	released, idle := []*CPUScalingOpts{}, []*CPUScalingOpts{}
+	for _, opts := range s.released {
+		// the C-states are restored even if the CPU is still scaled, options with other idle settings wait for a
+		// new idle period. Options with the same settings took over the override before
+		if opts.IdleCStates.isActive() {
+			idle = append(idle, opts)
+		}
+		// the CPU was scaled again with the same actuator in the meantime
+		if entry, found := s.entries[opts.CPU.GetID()]; found && entry.opts.Actuator == opts.Actuator {
+			continue
+		}
+		released = append(released, opts)
+	}
+	s.released = nil
+	s.mutex.Unlock()
+
+	for _, opts := range released {
+		if err := releaseActuator(opts); err != nil {
+			s.logger.Error(err, "failed to release the actuator of the cpu", "cpuID", opts.CPU.GetID())
+		}
+	}
+	for _, opts := range idle {
+		if err := opts.CPU.ClearCStatesOverride(); err != nil {
+			s.logger.Error(err, "failed to restore the c-states of the cpu", "cpuID", opts.CPU.GetID())
+		}
+	}
+}
+
+// ceilToTick rounds t up to the scheduler tick, aligning updates of CPUs with equal periods into one batch
+func ceilToTick(t time.Time) time.Time {
+	rounded := t.Truncate(schedulerTick)
+	if rounded.Before(t) {
+		rounded = rounded.Add(schedulerTick)
+	}
+	return rounded
+}
+
+// GetCPUScalingStatus returns the status of the given CPUs which are scaled, as of their last update,
+// with the current frequency of the CPU
+func (s *cpuScalingManagerImpl) GetCPUScalingStatus(cpuIDs []uint) []CPUScalingStatus {
+	statuses := []CPUScalingStatus{}
+	cpus := []power.Cpu{}
+	s.mutex.Lock()
+	for _, cpuID := range cpuIDs {
+		if entry, found := s.entries[cpuID]; found {
+			statuses = append(statuses, entry.status)
+			cpus = append(cpus, entry.opts.CPU)
+		}
+	}
+	s.mutex.Unlock()
+
+	// sysfs is read without the lock
+	for i, cpu := range cpus {
+		if frequency, err := cpu.GetCurrentCPUFrequency(); err == nil {
+			statuses[i].CurrentFrequency = frequency
+		}
+	}
+	return statuses
 }
 
-func (s *cpuScalingManagerImpl) getCPUScalingWorker(cpuID uint) (CPUScalingWorker, bool) {
-	if value, found := s.workers.Load(cpuID); found {
-		return value.(CPUScalingWorker), true
+func (s *cpuScalingManagerImpl) getManagedCPUIDs() []uint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	managedCPUs := make([]uint, 0, len(s.entries))
+	for cpuID := range s.entries {
+		managedCPUs = append(managedCPUs, cpuID)
 	}
 
-	return nil, false
+	return managedCPUs
 }
@@ -1,20 +1,23 @@
 package scaling
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"slices"
 	"testing"
 	"time"
 
 	"github.com/intel/power-optimization-library/pkg/power"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap/zapcore"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
-func createNewCPUScalingManager() cpuScalingManagerImpl {
+func createNewCPUScalingManager() *cpuScalingManagerImpl {
 	log.SetLogger(zap.New(
 		zap.UseDevMode(true),
 		func(opts *zap.Options) {
@@ -22,38 +25,59 @@ func createNewCPUScalingManager() cpuScalingManagerImpl {
 		},
 	))
 
-	return cpuScalingManagerImpl{
-		logger: ctrl.Log.WithName("test-log"),
+	return &cpuScalingManagerImpl{
+		entries: map[uint]*scalingEntry{},
+		wakeUp:  make(chan struct{}, 1),
+		logger:  ctrl.Log.WithName("test-log"),
 	}
 }
 
+// setSchedulerTime fixes the time seen by the scheduler
+func setSchedulerTime(t testing.TB, now *time.Time) {
+	origGetSchedulerTime := getSchedulerTime
+	t.Cleanup(func() {
+		getSchedulerTime = origGetSchedulerTime
+	})
+	getSchedulerTime = func() time.Time { return *now }
+}
+
 func TestCPUScalingManager_Start(t *testing.T) {
+	host, teardown, err := setupScalingTestFiles(1, map[string]string{})
+	assert.NoError(t, err)
+	defer teardown()
+
 	mgr := createNewCPUScalingManager()
-	w := &workerMock{}
-	w.On("Stop").Return()
-	mgr.workers.Store(uint(0), w)
+	upd := &updaterMock{}
+	mgr.updater = upd
+	updated := make(chan struct{}, 1)
+	upd.On("Update", mock.Anything).Return(time.Hour).Run(func(mock.Arguments) {
+		updated <- struct{}{}
+	})
 
 	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, mgr.Start(ctx))
+		close(done)
+	}()
 
-	cancel()
-	mgr.Start(ctx)
+	// the scheduler is woken up for CPUs added while it idles
+	mgr.AddCPUScaling([]CPUScalingOpts{{CPU: host.GetAllCpus().ByID(0), SamplePeriod: time.Millisecond}})
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cpu was not updated")
+	}
 
-	w.AssertCalled(t, "Stop")
+	cancel()
+	<-done
+	assert.Empty(t, mgr.getManagedCPUIDs())
+	assert.Empty(t, mgr.queue)
 }
 
 func TestCPUScalingManager_AddCPUScaling(t *testing.T) {
-	origNewCPUScalingWorkerFunc := newCPUScalingWorkerFunc
-	t.Cleanup(func() {
-		newCPUScalingWorkerFunc = origNewCPUScalingWorkerFunc
-	})
-	newCPUScalingWorkerFunc = func(
-		cpuID uint,
-		_ *power.Host,
-		dpdkClient DPDKTelemetryClient,
-		opts *CPUScalingOpts,
-	) CPUScalingWorker {
-		return CreateMockWorker(cpuID, opts)
-	}
+	now := time.Unix(1000, 0)
+	setSchedulerTime(t, &now)
 
 	// Initialize power host and cpus for tests
 	host, teardown, err := setupScalingTestFiles(4, map[string]string{
@@ -72,7 +96,7 @@ func TestCPUScalingManager_AddCPUScaling(t *testing.T) {
 		addConfig     []CPUScalingOpts
 	}{
 		{
-			testCase:      "New workers in an empty worker pool",
+			testCase:      "New CPUs in an empty scheduler",
 			initialConfig: []CPUScalingOpts{},
 			addConfig: []CPUScalingOpts{
 				{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond},
@@ -80,7 +104,7 @@ func TestCPUScalingManager_AddCPUScaling(t *testing.T) {
 			},
 		},
 		{
-			testCase: "New workers extending already populated worker pool",
+			testCase: "New CPUs extending already scheduled CPUs",
 			initialConfig: []CPUScalingOpts{
 				{CPU: allCpus.ByID(0), SamplePeriod: 50 * time.Millisecond},
 				{CPU: allCpus.ByID(1), SamplePeriod: 500 * time.Millisecond},
@@ -91,7 +115,7 @@ func TestCPUScalingManager_AddCPUScaling(t *testing.T) {
 			},
 		},
 		{
-			testCase: "Updating existing workers",
+			testCase: "Updating scheduled CPUs",
 			initialConfig: []CPUScalingOpts{
 				{CPU: allCpus.ByID(0), SamplePeriod: 50 * time.Millisecond},
 				{CPU: allCpus.ByID(1), SamplePeriod: 500 * time.Millisecond},
@@ -107,42 +131,35 @@ func TestCPUScalingManager_AddCPUScaling(t *testing.T) {
 		t.Run(tc.testCase, func(t *testing.T) {
 			mgr := createNewCPUScalingManager()
 
-			// create workers from initial configuration
-			initialWorkers := make(map[uint]*workerMock)
-			for _, opt := range tc.initialConfig {
-				w := CreateMockWorker(opt.CPU.GetID(), &opt)
-				initialWorkers[opt.CPU.GetID()] = w
-				mgr.workers.Store(opt.CPU.GetID(), w)
-			}
-			// set up UpdateOpts for workers that will be updated
-			for _, opt := range tc.addConfig {
-				if w, exists := initialWorkers[opt.CPU.GetID()]; exists {
-					w.On("UpdateOpts", &opt).Return()
-				}
+			mgr.AddCPUScaling(tc.initialConfig)
+			initialDue := map[uint]time.Time{}
+			for cpuID, entry := range mgr.entries {
+				initialDue[cpuID] = entry.due
 			}
 
 			mgr.AddCPUScaling(tc.addConfig)
 
-			// assert all added workers exist and have correct values
-			for _, opt := range tc.addConfig {
-				w, found := mgr.getCPUScalingWorker(opt.CPU.GetID())
-				assert.True(t, found)
-				typedW := w.(*workerMock)
-				assert.Equal(t, &opt, typedW.opts)
-			}
-			// assert existing workers that were updated got UpdateOpts called
-			for _, opt := range tc.addConfig {
-				if w, exists := initialWorkers[opt.CPU.GetID()]; exists {
-					w.AssertCalled(t, "UpdateOpts", &opt)
+			// assert all added CPUs are scheduled with the new options
+			for _, opts := range tc.addConfig {
+				entry, found := mgr.entries[opts.CPU.GetID()]
+				if assert.True(t, found) {
+					assert.Equal(t, opts, *entry.opts)
+				}
+				// updated CPUs keep their next update, new ones are due after the sample period
+				if due, scheduled := initialDue[opts.CPU.GetID()]; scheduled {
+					assert.Equal(t, due, entry.due)
+				} else {
+					assert.Equal(t, now.Add(opts.SamplePeriod), entry.due)
 				}
 			}
-			// assert existing workers that were NOT in addConfig are still present and untouched
-			for _, opt := range tc.initialConfig {
-				cpuID := opt.CPU.GetID()
-				_, found := mgr.getCPUScalingWorker(cpuID)
-				assert.True(t, found, "initial worker for CPU %d should still exist", cpuID)
-				initialWorkers[cpuID].AssertNotCalled(t, "Stop")
+			// assert CPUs that were NOT in addConfig are still scheduled
+			for _, opts := range tc.initialConfig {
+				_, found := mgr.entries[opts.CPU.GetID()]
+				assert.True(t, found, "CPU %d should still be scheduled", opts.CPU.GetID())
 			}
+			assert.Len(t, mgr.queue, len(mgr.entries))
+			// the scheduler is woken up
+			assert.Len(t, mgr.wakeUp, 1)
 		})
 	}
 }
@@ -165,7 +182,7 @@ func TestCPUScalingManager_RemoveCPUScaling(t *testing.T) {
 		remainingIDs  []uint
 	}{
 		{
-			testCase: "Removing some workers from existing pool",
+			testCase: "Removing some CPUs from the scheduler",
 			initialConfig: []CPUScalingOpts{
 				{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond},
 				{CPU: allCpus.ByID(1), SamplePeriod: 100 * time.Millisecond},
@@ -176,7 +193,7 @@ func TestCPUScalingManager_RemoveCPUScaling(t *testing.T) {
 			remainingIDs: []uint{0, 1},
 		},
 		{
-			testCase: "Removing all workers from existing pool",
+			testCase: "Removing all CPUs from the scheduler",
 			initialConfig: []CPUScalingOpts{
 				{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond},
 				{CPU: allCpus.ByID(1), SamplePeriod: 100 * time.Millisecond},
@@ -197,34 +214,379 @@ func TestCPUScalingManager_RemoveCPUScaling(t *testing.T) {
 	for _, tc := range tcases {
 		t.Run(tc.testCase, func(t *testing.T) {
 			mgr := createNewCPUScalingManager()
-
-			// create workers from initial configuration
-			initialWorkers := make(map[uint]*workerMock)
-			for _, opt := range tc.initialConfig {
-				w := CreateMockWorker(opt.CPU.GetID(), &opt)
-				if slices.Contains(tc.removeCPUIDs, opt.CPU.GetID()) {
-					w.On("Stop").Return()
-				}
-				initialWorkers[opt.CPU.GetID()] = w
-				mgr.workers.Store(opt.CPU.GetID(), w)
-			}
+			mgr.AddCPUScaling(tc.initialConfig)
 
 			mgr.RemoveCPUScaling(tc.removeCPUIDs)
 
-			// assert removed workers were stopped and deleted
-			for _, cpuID := range tc.removeCPUIDs {
-				_, found := mgr.getCPUScalingWorker(cpuID)
-				assert.False(t, found, "worker for CPU %d should be removed", cpuID)
-				if w, exists := initialWorkers[cpuID]; exists {
-					w.AssertCalled(t, "Stop")
-				}
-			}
-			// assert remaining workers are still present and untouched
-			for _, cpuID := range tc.remainingIDs {
-				_, found := mgr.getCPUScalingWorker(cpuID)
-				assert.True(t, found, "worker for CPU %d should still exist", cpuID)
-				initialWorkers[cpuID].AssertNotCalled(t, "Stop")
+			managedCPUs := mgr.getManagedCPUIDs()
+			slices.Sort(managedCPUs)
+			assert.Equal(t, tc.remainingIDs, managedCPUs)
+			queued := []uint{}
+			for _, entry := range mgr.queue {
+				queued = append(queued, entry.cpuID)
 			}
+			assert.ElementsMatch(t, tc.remainingIDs, queued)
+		})
+	}
+}
+
+func TestCPUScalingManager_runDue(t *testing.T) {
+	now := time.Unix(1000, 0)
+	setSchedulerTime(t, &now)
+	host, teardown, err := setupScalingTestFiles(4, map[string]string{})
+	assert.NoError(t, err)
+	defer teardown()
+	allCpus := host.GetAllCpus()
+
+	mgr := createNewCPUScalingManager()
+	upd := &updaterMock{}
+	mgr.updater = upd
+	updated := []uint{}
+	upd.On("Update", mock.Anything).Return(20 * time.Millisecond).Run(func(args mock.Arguments) {
+		opts := args.Get(0).(*CPUScalingOpts)
+		updated = append(updated, opts.CPU.GetID())
+		// CPUs removed while their update runs are not rescheduled
+		if opts.CPU.GetID() == 1 {
+			mgr.RemoveCPUScaling([]uint{1})
+		}
+	})
+	mgr.AddCPUScaling([]CPUScalingOpts{
+		{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond},
+		{CPU: allCpus.ByID(1), SamplePeriod: 10 * time.Millisecond},
+		// due within the same tick
+		{CPU: allCpus.ByID(2), SamplePeriod: 9*time.Millisecond + 500*time.Microsecond},
+		{CPU: allCpus.ByID(3), SamplePeriod: 15 * time.Millisecond},
+	})
+
+	// nothing is due yet
+	assert.Equal(t, 10*time.Millisecond, mgr.runDue(now))
+	assert.Empty(t, updated)
+
+	now = now.Add(10 * time.Millisecond)
+	assert.Equal(t, 5*time.Millisecond, mgr.runDue(now))
+	assert.Equal(t, []uint{0, 1, 2}, updated)
+
+	// updated CPUs are rescheduled after the duration returned by the updater
+	now = now.Add(5 * time.Millisecond)
+	assert.Equal(t, 15*time.Millisecond, mgr.runDue(now))
+	assert.Equal(t, []uint{0, 1, 2, 3}, updated)
+	assert.Equal(t, now.Add(15*time.Millisecond), mgr.entries[0].due)
+	assert.Equal(t, now.Add(15*time.Millisecond), mgr.entries[2].due)
+	assert.NotContains(t, mgr.entries, uint(1))
+
+	mgr.RemoveCPUScaling([]uint{0, 2, 3})
+	assert.Equal(t, schedulerIdleWait, mgr.runDue(now))
+}
+
+func TestCPUScalingManager_releaseActuators(t *testing.T) {
+	host, teardown, err := setupScalingTestFiles(2, map[string]string{
+		"governor": "powersave",
+		"max":      "3000000",
+		"min":      "1000000",
+	})
+	assert.NoError(t, err)
+	defer teardown()
+	allCpus := host.GetAllCpus()
+
+	mgr := createNewCPUScalingManager()
+	mgr.updater = fixedUpdater(10 * time.Millisecond)
+	optsList := []CPUScalingOpts{
+		{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond, Actuator: ActuatorMaxFreq},
+		{CPU: allCpus.ByID(1), SamplePeriod: 10 * time.Millisecond, Actuator: ActuatorMaxFreq},
+	}
+	mgr.AddCPUScaling(optsList)
+	for _, opts := range optsList {
+		assert.NoError(t, applyFrequency(&opts, 2000000))
+	}
+
+	// the cap is released once the scheduler runs, not while the controller removes the CPU
+	mgr.RemoveCPUScaling([]uint{0})
+	assert.Contains(t, allCpus.ByID(0).GetFrequencyConstraints(), power.ScalerRequester)
+	mgr.runDue(getSchedulerTime())
+	assert.NotContains(t, allCpus.ByID(0).GetFrequencyConstraints(), power.ScalerRequester)
+
+	// a CPU scaled again with the same actuator before the scheduler runs keeps its cap
+	mgr.RemoveCPUScaling([]uint{1})
+	mgr.AddCPUScaling(optsList[1:])
+	mgr.runDue(getSchedulerTime())
+	assert.Contains(t, allCpus.ByID(1).GetFrequencyConstraints(), power.ScalerRequester)
+
+	// switching to another actuator releases the cap as well
+	optsList[1].Actuator = ActuatorSetSpeed
+	mgr.AddCPUScaling(optsList[1:])
+	assert.Contains(t, allCpus.ByID(1).GetFrequencyConstraints(), power.ScalerRequester)
+	mgr.runDue(getSchedulerTime())
+	assert.NotContains(t, allCpus.ByID(1).GetFrequencyConstraints(), power.ScalerRequester)
+	assert.Empty(t, mgr.released)
+}
+
+func TestCPUScalingManager_carryReplacedState(t *testing.T) {
+	now := time.Unix(1000, 0)
+	setSchedulerTime(t, &now)
+	mgr := createNewCPUScalingManager()
+	mgr.updater = fixedUpdater(10 * time.Millisecond)
+	cpu := &simCPU{frequency: 2000000}
+	newOpts := func() CPUScalingOpts {
+		return CPUScalingOpts{
+			CPU:                    cpu,
+			SamplePeriod:           10 * time.Millisecond,
+			CurrentTargetFrequency: FrequencyNotYetSet,
+			PID:                    &PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.1},
+			AdaptiveGain:           &AdaptiveGainOpts{Window: time.Minute, MaxReversals: 3, MinGain: 0.25, MaxGain: 2},
+		}
+	}
+	scale := func() {
+		opts := mgr.entries[0].opts
+		opts.CurrentTargetFrequency = 2500000
+		opts.lastChange = now
+		opts.PID.started, opts.PID.baseFrequency, opts.PID.integral = true, 2200000, 0.4
+		opts.AdaptiveGain.gain, opts.AdaptiveGain.direction, opts.AdaptiveGain.streak = 0.5, 1, 2
+		opts.AdaptiveGain.reversals = []time.Time{now}
+	}
+
+	mgr.AddCPUScaling([]CPUScalingOpts{newOpts()})
+	scale()
+	mgr.runDue(now.Add(10 * time.Millisecond))
+
+	// re-adding the CPU with the same options keeps the gain, the target and the controller state
+	mgr.AddCPUScaling([]CPUScalingOpts{newOpts()})
+	mgr.runDue(now.Add(20 * time.Millisecond))
+	opts := mgr.entries[0].opts
+	assert.Equal(t, 2500000, opts.CurrentTargetFrequency)
+	assert.Equal(t, now, opts.lastChange)
+	assert.Equal(t, PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.1, started: true, baseFrequency: 2200000, integral: 0.4}, *opts.PID)
+	assert.Equal(t, 50, opts.AdaptiveGain.percent())
+	assert.Equal(t, []time.Time{now}, opts.AdaptiveGain.reversals)
+	assert.Equal(t, 50, mgr.entries[0].status.GainPercent)
+	assert.Equal(t, 2500000, mgr.entries[0].status.TargetFrequency)
+	assert.Empty(t, mgr.replaced)
+
+	// changed settings restart the controllers they configure
+	updated := newOpts()
+	updated.PID.IntegralGain = 0.2
+	updated.AdaptiveGain.MaxGain = 4
+	mgr.AddCPUScaling([]CPUScalingOpts{updated})
+	mgr.runDue(now.Add(30 * time.Millisecond))
+	opts = mgr.entries[0].opts
+	assert.Equal(t, 2500000, opts.CurrentTargetFrequency)
+	assert.False(t, opts.PID.started)
+	assert.Equal(t, 100, opts.AdaptiveGain.percent())
+
+	// another actuator starts from an unset target
+	scale()
+	updated = newOpts()
+	updated.PID.IntegralGain = 0.2
+	updated.AdaptiveGain.MaxGain = 4
+	updated.Actuator = ActuatorEPP
+	mgr.AddCPUScaling([]CPUScalingOpts{updated})
+	mgr.runDue(now.Add(40 * time.Millisecond))
+	opts = mgr.entries[0].opts
+	assert.Equal(t, FrequencyNotYetSet, opts.CurrentTargetFrequency)
+	assert.False(t, opts.PID.started)
+	assert.Equal(t, 50, opts.AdaptiveGain.percent())
+}
+
+func TestCPUScalingManager_releaseIdleCStates(t *testing.T) {
+	now := time.Unix(1000, 0)
+	setSchedulerTime(t, &now)
+	mgr := createNewCPUScalingManager()
+	mgr.updater = fixedUpdater(10 * time.Millisecond)
+	cpu := &idleCPU{}
+	newOpts := func() CPUScalingOpts {
+		return CPUScalingOpts{
+			CPU:          cpu,
+			SamplePeriod: 10 * time.Millisecond,
+			IdleCStates:  &IdleCStatesOpts{UsageThreshold: 10, IdlePeriod: time.Second, States: map[string]bool{"C6": true}},
+		}
+	}
+	idle := func() {
+		mgr.entries[0].opts.IdleCStates.active = true
+		cpu.cStates = map[string]bool{"C6": true}
+	}
+
+	mgr.AddCPUScaling([]CPUScalingOpts{newOpts()})
+	idle()
+	mgr.runDue(now.Add(10 * time.Millisecond))
+	assert.True(t, mgr.entries[0].status.Idle)
+
+	// options with the same idle settings keep the override
+	mgr.AddCPUScaling([]CPUScalingOpts{newOpts()})
+	assert.Empty(t, mgr.released)
+	mgr.runDue(now.Add(20 * time.Millisecond))
+	assert.Equal(t, map[string]bool{"C6": true}, cpu.cStates)
+	assert.True(t, mgr.entries[0].status.Idle)
+
+	// other idle settings restore the C-states and wait for a new idle period
+	updated := newOpts()
+	updated.IdleCStates.States = map[string]bool{"C6": true, "C1E": true}
+	mgr.AddCPUScaling([]CPUScalingOpts{updated})
+	assert.NotNil(t, cpu.cStates)
+	mgr.runDue(now.Add(30 * time.Millisecond))
+	assert.Nil(t, cpu.cStates)
+	assert.False(t, mgr.entries[0].status.Idle)
+
+	// another actuator with the same idle settings releases the previous options, the override moves to the new ones
+	idle()
+	updated = newOpts()
+	updated.IdleCStates.States = map[string]bool{"C6": true, "C1E": true}
+	updated.Actuator = ActuatorMaxFreq
+	mgr.AddCPUScaling([]CPUScalingOpts{updated})
+	mgr.runDue(now.Add(40 * time.Millisecond))
+	assert.NotNil(t, cpu.cStates)
+	assert.True(t, mgr.entries[0].status.Idle)
+
+	// the C-states of CPUs no longer scaled are restored once the scheduler runs
+	idle()
+	mgr.RemoveCPUScaling([]uint{0})
+	assert.NotNil(t, cpu.cStates)
+	mgr.runDue(now.Add(50 * time.Millisecond))
+	assert.Nil(t, cpu.cStates)
+	assert.Empty(t, mgr.released)
+}
+
+func TestCPUScalingManager_GetCPUScalingStatus(t *testing.T) {
+	now := time.Unix(1000, 0)
+	setSchedulerTime(t, &now)
+	host, teardown, err := setupScalingTestFiles(2, map[string]string{"current": "2100000"})
+	assert.NoError(t, err)
+	defer teardown()
+	allCpus := host.GetAllCpus()
+
+	mgr := createNewCPUScalingManager()
+	upd := &updaterMock{}
+	mgr.updater = upd
+	usageErr := fmt.Errorf("foo")
+	upd.On("Update", mock.Anything).Return(10 * time.Millisecond).Run(func(args mock.Arguments) {
+		opts := args.Get(0).(*CPUScalingOpts)
+		if opts.CPU.GetID() == 0 {
+			opts.usage = 75
+			opts.CurrentTargetFrequency = 2500000
+			opts.lastChange = now
+		} else {
+			opts.usageErr = usageErr
+		}
+	})
+	mgr.AddCPUScaling([]CPUScalingOpts{
+		{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond, CurrentTargetFrequency: FrequencyNotYetSet},
+		{CPU: allCpus.ByID(1), SamplePeriod: 10 * time.Millisecond, CurrentTargetFrequency: FrequencyNotYetSet},
+	})
+
+	// CPUs are reported once scaled, before their first update
+	assert.Equal(t, []CPUScalingStatus{
+		{CPUID: 1, TargetFrequency: FrequencyNotYetSet, CurrentFrequency: 2100000},
+	}, mgr.GetCPUScalingStatus([]uint{1, 2}))
+
+	now = now.Add(10 * time.Millisecond)
+	mgr.runDue(now)
+	assert.Equal(t, []CPUScalingStatus{
+		{CPUID: 0, TargetFrequency: 2500000, CurrentFrequency: 2100000, Usage: 75, LastChange: now, LastUpdate: now},
+		{CPUID: 1, TargetFrequency: FrequencyNotYetSet, CurrentFrequency: 2100000, UsageErr: usageErr, LastUpdate: now},
+	}, mgr.GetCPUScalingStatus([]uint{0, 1}))
+
+	mgr.RemoveCPUScaling([]uint{0})
+	assert.Len(t, mgr.GetCPUScalingStatus([]uint{0, 1}), 1)
+}
+
+func TestCPUScalingManager_recordUsage(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+	setSchedulerTime(t, &now)
+	host, teardown, err := setupScalingTestFiles(2, map[string]string{"current": "2100000"})
+	assert.NoError(t, err)
+	defer teardown()
+	allCpus := host.GetAllCpus()
+
+	mgr := createNewCPUScalingManager()
+	buf := &bytes.Buffer{}
+	mgr.recorder = NewUsageRecorder(buf)
+	upd := &updaterMock{}
+	mgr.updater = upd
+	upd.On("Update", mock.Anything).Return(10 * time.Millisecond).Run(func(args mock.Arguments) {
+		opts := args.Get(0).(*CPUScalingOpts)
+		if opts.CPU.GetID() == 0 {
+			opts.usage = 75
+		} else {
+			opts.usageErr = ErrDPDKMetricStale
+		}
+	})
+	mgr.AddCPUScaling([]CPUScalingOpts{
+		{CPU: allCpus.ByID(0), SamplePeriod: 10 * time.Millisecond},
+		{CPU: allCpus.ByID(1), SamplePeriod: 10 * time.Millisecond},
+	})
+	mgr.runDue(now.Add(time.Second))
+
+	trace, err := ReadUsageTrace(buf)
+	assert.NoError(t, err)
+	slices.SortFunc(trace, func(a, b UsageSample) int { return int(a.CPU) - int(b.CPU) })
+	assert.Equal(t, []UsageSample{
+		{Time: now.Add(time.Second), CPU: 0, Usage: 75, Frequency: 2100000},
+		{Time: now.Add(time.Second), CPU: 1, Frequency: 2100000, Error: ErrDPDKMetricStale.Error()},
+	}, trace)
+}
+
+func TestCeilToTick(t *testing.T) {
+	base := time.Unix(1000, 0)
+	assert.Equal(t, base, ceilToTick(base))
+	assert.Equal(t, base.Add(time.Millisecond), ceilToTick(base.Add(time.Microsecond)))
+	assert.Equal(t, base.Add(2*time.Millisecond), ceilToTick(base.Add(1999*time.Microsecond)))
+}
+
+// fixedUpdater stands in for the updater to measure the scheduling overhead alone
+type fixedUpdater time.Duration
+
+func (u fixedUpdater) Update(*CPUScalingOpts) time.Duration { return time.Duration(u) }
+
+// benchmarkScheduler runs a tick of the scheduler with all CPUs due, reporting the cost per CPU update
+func benchmarkScheduler(b *testing.B, cpus int, updater CPUScalingUpdater, newCPU func(id uint) power.Cpu) {
+	now := time.Unix(1000, 0)
+	setSchedulerTime(b, &now)
+	mgr := createNewCPUScalingManager()
+	mgr.updater = updater
+	optsList := make([]CPUScalingOpts, 0, cpus)
+	for id := 0; id < cpus; id++ {
+		cpu := newCPU(uint(id))
+		optsList = append(optsList, CPUScalingOpts{
+			CPU:                        cpu,
+			UsageSource:                cpu.(UsageSource),
+			SamplePeriod:               10 * time.Millisecond,
+			CooldownPeriod:             30 * time.Millisecond,
+			TargetUsage:                80,
+			AllowedUsageDifference:     5,
+			AllowedFrequencyDifference: 25000,
+			MinFrequency:               1000000,
+			MaxFrequency:               3000000,
+			CurrentTargetFrequency:     FrequencyNotYetSet,
+			ScaleFactor:                0.5,
+		})
+	}
+	mgr.AddCPUScaling(optsList)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		now = now.Add(time.Hour)
+		mgr.runDue(now)
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*cpus), "ns/update")
+}
+
+type benchmarkCPU struct {
+	simCPU
+	id uint
+}
+
+func (c *benchmarkCPU) GetID() uint { return c.id }
+
+func BenchmarkCPUScalingManager_runDue(b *testing.B) {
+	for _, cpus := range []int{256, 1024} {
+		b.Run(fmt.Sprintf("scheduler/%d", cpus), func(b *testing.B) {
+			benchmarkScheduler(b, cpus, fixedUpdater(10*time.Millisecond), func(id uint) power.Cpu {
+				return &benchmarkCPU{simCPU: simCPU{frequency: 2000000, demand: 1600000}, id: id}
+			})
+		})
+		// simulated CPUs with distinct workloads, the updater reads their usage and sets their frequency
+		b.Run(fmt.Sprintf("updater/%d", cpus), func(b *testing.B) {
+			benchmarkScheduler(b, cpus, &cpuScalingUpdaterImpl{logger: ctrl.Log.WithName("bench")}, func(id uint) power.Cpu {
+				return &benchmarkCPU{simCPU: simCPU{frequency: 2000000, demand: 1000000 + (id%16)*150000}, id: id}
+			})
 		})
 	}
 }
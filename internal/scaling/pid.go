@@ -0,0 +1,85 @@
+package scaling
+
+import "time"
+
+// PIDOpts configures the PID controller of a CPU. The gains apply at each evaluation to the usage error in
+// fractions of 100 percent points, and scale the output to the frequency range of the CPU. The
+// controller state is kept with the options, updated options restart the controller from the current frequency
+// unless the gains are the same
+type PIDOpts struct {
+	ProportionalGain float64
+	IntegralGain     float64
+	DerivativeGain   float64
+
+	started       bool
+	baseFrequency int     // frequency the controller output is added to, set when the controller starts
+	integral      float64 // sum of the usage errors of all evaluations
+	prevError     float64
+}
+
+// carryState keeps the controller state of prev if the gains did not change
+func (p *PIDOpts) carryState(prev *PIDOpts) {
+	if p == nil || prev == nil || p.ProportionalGain != prev.ProportionalGain ||
+		p.IntegralGain != prev.IntegralGain || p.DerivativeGain != prev.DerivativeGain {
+		return
+	}
+	p.started, p.baseFrequency, p.integral, p.prevError = prev.started, prev.baseFrequency, prev.integral, prev.prevError
+}
+
+// updatePID computes the next frequency with a PID controller on the usage error. Usage within the allowed
+// usage difference counts as no error. The integral accumulates no further than needed to saturate the output
+// at the limits of the frequency range, so the controller reacts as soon as the error changes sign instead of winding down
+func (u *cpuScalingUpdaterImpl) updatePID(opts *CPUScalingOpts, currentUsage int) time.Duration {
+	pid := opts.PID
+	if !pid.started {
+		baseFrequency := opts.CurrentTargetFrequency
+		if baseFrequency == FrequencyNotYetSet {
+			currentFrequency, err := opts.CPU.GetCurrentCPUFrequency()
+			if err != nil {
+				u.setFallbackFrequency(opts)
+				return opts.SamplePeriod
+			}
+			baseFrequency = int(currentFrequency)
+		}
+		*pid = PIDOpts{
+			ProportionalGain: pid.ProportionalGain,
+			IntegralGain:     pid.IntegralGain,
+			DerivativeGain:   pid.DerivativeGain,
+			started:          true,
+			baseFrequency:    baseFrequency,
+		}
+	}
+
+	usageError := 0.0
+	if currentUsage < opts.TargetUsage-opts.AllowedUsageDifference ||
+		currentUsage > opts.TargetUsage+opts.AllowedUsageDifference {
+		usageError = float64(currentUsage-opts.TargetUsage) / 100.0
+	}
+
+	// the adapted gain scales all terms
+	gain := opts.AdaptiveGain.factor()
+	proportionalGain, integralGain, derivativeGain := pid.ProportionalGain*gain, pid.IntegralGain*gain, pid.DerivativeGain*gain
+
+	freqRange := float64(opts.MaxFrequency - opts.MinFrequency)
+	// output of the proportional and derivative terms, in fractions of the frequency range
+	pdOutput := proportionalGain*usageError + derivativeGain*(usageError-pid.prevError)
+	integral := pid.integral + usageError
+	if integralGain > 0 && freqRange > 0 {
+		// the integral grows at most to the value saturating the output
+		maxIntegral := (float64(opts.MaxFrequency-pid.baseFrequency)/freqRange - pdOutput) / integralGain
+		minIntegral := (float64(opts.MinFrequency-pid.baseFrequency)/freqRange - pdOutput) / integralGain
+		if usageError > 0 && integral > maxIntegral {
+			integral = max(pid.integral, maxIntegral)
+		}
+		if usageError < 0 && integral < minIntegral {
+			integral = min(pid.integral, minIntegral)
+		}
+	}
+	nextFrequency := pid.baseFrequency + int((pdOutput+integralGain*integral)*freqRange)
+	pid.integral = integral
+	pid.prevError = usageError
+
+	nextFrequency = limitFrequencyStep(opts, nextFrequency, pid.baseFrequency)
+
+	return u.setNextFrequency(opts, nextFrequency, currentUsage, pid.baseFrequency)
+}
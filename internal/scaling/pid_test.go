@@ -0,0 +1,299 @@
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	"github.com/stretchr/testify/assert"
+)
+
+// simCPU simulates a CPU running a workload that needs a fixed amount of work per second,
+// its usage is the share of the CPU's capacity at the current frequency the workload uses
+type simCPU struct {
+	power.Cpu
+	frequency uint
+	demand    uint // frequency at which the workload would keep the CPU fully busy
+}
+
+func (c *simCPU) GetID() uint { return 0 }
+
+func (c *simCPU) GetCurrentCPUFrequency() (uint, error) { return c.frequency, nil }
+
+func (c *simCPU) SetCPUFrequency(frequency uint) error {
+	c.frequency = frequency
+	return nil
+}
+
+func (c *simCPU) SnapFrequency(frequency uint) uint { return frequency }
+
+// simulated CPUs scale between 1 GHz and 3 GHz
+func (c *simCPU) GetFrequencyLimitsExcluding(power.FrequencyRequester) (uint, uint) {
+	return 1000000, 3000000
+}
+
+func (c *simCPU) GetUsagePercent(uint) (int, error) {
+	return int(min(100, c.demand*100/c.frequency)), nil
+}
+
+func (c *simCPU) CloseConnection(string) {}
+
+func (c *simCPU) Close() {}
+
+func TestCPUScalingUpdater_updatePID(t *testing.T) {
+	newOpts := func(cpu *simCPU, pid *PIDOpts) *CPUScalingOpts {
+		return &CPUScalingOpts{
+			CPU:                        cpu,
+			UsageSource:                cpu,
+			TargetUsage:                80,
+			AllowedUsageDifference:     2,
+			AllowedFrequencyDifference: 1000,
+			SamplePeriod:               10 * time.Millisecond,
+			CooldownPeriod:             30 * time.Millisecond,
+			MinFrequency:               1000000,
+			MaxFrequency:               3000000,
+			CurrentTargetFrequency:     FrequencyNotYetSet,
+			FallbackFreq:               2000000,
+			PID:                        pid,
+		}
+	}
+
+	tcases := []struct {
+		testCase string
+		pid      PIDOpts
+		// demand of the workload at each evaluation
+		trace func(step int) uint
+		steps int
+		// usage range reached at the end of the trace
+		minUsage int
+		maxUsage int
+	}{
+		{
+			testCase: "Test Case 1 - PI controller settles on the target usage",
+			pid:      PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+			trace:    func(int) uint { return 1600000 },
+			steps:    60,
+			minUsage: 78,
+			maxUsage: 82,
+		},
+		{
+			testCase: "Test Case 2 - PI controller follows a load step",
+			pid:      PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+			trace: func(step int) uint {
+				if step < 30 {
+					return 1200000
+				}
+				return 2000000
+			},
+			steps:    90,
+			minUsage: 78,
+			maxUsage: 82,
+		},
+		{
+			testCase: "Test Case 3 - PI controller recovers from a saturated output",
+			pid:      PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+			trace: func(step int) uint {
+				// the workload needs more than the maximum frequency, then drops
+				if step < 50 {
+					return 4000000
+				}
+				return 1600000
+			},
+			steps:    80,
+			minUsage: 78,
+			maxUsage: 82,
+		},
+		{
+			testCase: "Test Case 4 - PID controller settles on the target usage",
+			pid:      PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2, DerivativeGain: 0.2},
+			trace:    func(int) uint { return 1600000 },
+			steps:    60,
+			minUsage: 78,
+			maxUsage: 82,
+		},
+		{
+			testCase: "Test Case 5 - proportional only controller keeps a steady-state error",
+			pid:      PIDOpts{ProportionalGain: 0.5},
+			trace:    func(int) uint { return 1600000 },
+			steps:    60,
+			minUsage: 90,
+			maxUsage: 100,
+		},
+		{
+			testCase: "Test Case 6 - target usage is unreachable at the minimum frequency",
+			pid:      PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+			trace:    func(int) uint { return 400000 },
+			steps:    60,
+			minUsage: 40,
+			maxUsage: 40,
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.testCase, func(t *testing.T) {
+			cpu := &simCPU{frequency: 1500000}
+			pid := tc.pid
+			opts := newOpts(cpu, &pid)
+			updater := &cpuScalingUpdaterImpl{}
+
+			for step := 0; step < tc.steps; step++ {
+				cpu.demand = tc.trace(step)
+				updater.Update(opts)
+				assert.GreaterOrEqual(t, cpu.frequency, uint(opts.MinFrequency))
+				assert.LessOrEqual(t, cpu.frequency, uint(opts.MaxFrequency))
+			}
+
+			usage, err := cpu.GetUsagePercent(0)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, usage, tc.minUsage)
+			assert.LessOrEqual(t, usage, tc.maxUsage)
+		})
+	}
+}
+
+func TestCPUScalingUpdater_updatePID_antiWindup(t *testing.T) {
+	cpu := &simCPU{frequency: 3000000, demand: 4000000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     2,
+		AllowedFrequencyDifference: 1000,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		PID:                        &PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+
+	// the workload saturates the CPU at the maximum frequency
+	for i := 0; i < 5; i++ {
+		updater.Update(opts)
+	}
+	assert.Equal(t, uint(3000000), cpu.frequency)
+	integral := opts.PID.integral
+	for i := 0; i < 20; i++ {
+		updater.Update(opts)
+	}
+	assert.Equal(t, uint(3000000), cpu.frequency)
+	assert.Equal(t, integral, opts.PID.integral)
+
+	// once the load drops the frequency is reduced right away
+	cpu.demand = 1600000
+	updater.Update(opts)
+	assert.Less(t, cpu.frequency, uint(3000000))
+}
+
+func TestCPUScalingUpdater_updatePID_restart(t *testing.T) {
+	cpu := &simCPU{frequency: 2000000, demand: 1600000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     2,
+		AllowedFrequencyDifference: 1000,
+		CooldownPeriod:             30 * time.Millisecond,
+		SamplePeriod:               10 * time.Millisecond,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		PID:                        &PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.2},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+
+	// the controller starts from the current frequency, which meets the target
+	assert.Equal(t, 30*time.Millisecond, updater.Update(opts))
+	assert.Equal(t, uint(2000000), cpu.frequency)
+	assert.Equal(t, 2000000, opts.PID.baseFrequency)
+
+	// the base frequency is kept while the controller runs
+	cpu.demand = 2000000
+	assert.Equal(t, 30*time.Millisecond, updater.Update(opts))
+	assert.Equal(t, 2000000, opts.PID.baseFrequency)
+	assert.Equal(t, uint(2000000+int(0.7*0.2*2000000)), cpu.frequency)
+}
+
+func TestCPUScalingUpdater_updatePID_burst(t *testing.T) {
+	cpu := &simCPU{frequency: 2000000, demand: 1600000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     2,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		MaxFrequencyStep:           100000,
+		BurstUsage:                 95,
+		PID:                        &PIDOpts{ProportionalGain: 0.5, IntegralGain: 0.1},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+	updater.Update(opts)
+	assert.True(t, opts.PID.started)
+
+	// a burst sets the maximum frequency at once and restarts the controller from it
+	cpu.demand = 2000000
+	updater.Update(opts)
+	assert.Equal(t, uint(3000000), cpu.frequency)
+	assert.False(t, opts.PID.started)
+
+	// the controller lowers the frequency from the maximum within the step limit
+	cpu.demand = 1200000
+	updater.Update(opts)
+	assert.True(t, opts.PID.started)
+	assert.Equal(t, 3000000, opts.PID.baseFrequency)
+	assert.Equal(t, uint(2900000), cpu.frequency)
+}
+
+func TestCPUScalingUpdater_Update_burstFirstUpdate(t *testing.T) {
+	cpu := &simCPU{frequency: 2000000, demand: 2000000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		ScaleFactor:                1.0,
+		BurstUsage:                 95,
+		AdaptiveGain:               &AdaptiveGainOpts{Window: time.Second, MaxReversals: 3, MinGain: 0.25, MaxGain: 2},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+
+	// a burst before the first target is set is a change up from the frequency the CPU runs at
+	updater.Update(opts)
+	assert.Equal(t, uint(3000000), cpu.frequency)
+	assert.Equal(t, 1, opts.AdaptiveGain.direction)
+}
+
+func TestCPUScalingUpdater_updatePID_firstScaleDown(t *testing.T) {
+	cpu := &simCPU{frequency: 2000000, demand: 1000000}
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		UsageSource:                cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		AllowedFrequencyDifference: 1000,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             10 * time.Millisecond,
+		ScaleDownCooldownPeriod:    100 * time.Millisecond,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		PID:                        &PIDOpts{ProportionalGain: 0.5},
+		AdaptiveGain:               &AdaptiveGainOpts{Window: time.Second, MaxReversals: 3, MinGain: 0.25, MaxGain: 2},
+	}
+	updater := &cpuScalingUpdaterImpl{}
+
+	// the first step compares with the frequency the controller started from, so it is a change down
+	assert.Equal(t, 100*time.Millisecond, updater.Update(opts))
+	assert.Less(t, cpu.frequency, uint(2000000))
+	assert.Equal(t, -1, opts.AdaptiveGain.direction)
+}
@@ -0,0 +1,61 @@
+package scaling
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// UsageSample is a usage sample of a scaled CPU as read by the scaler, one line of a usage trace
+type UsageSample struct {
+	Time time.Time `json:"time"`
+	CPU  uint      `json:"cpu"`
+	// Usage in percent, not set if reading it failed with Error
+	Usage int `json:"usage"`
+	// Frequency of the CPU the usage was measured at, in kHz
+	Frequency uint   `json:"frequency,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UsageRecorder writes the usage samples read by the scaler as a JSONL trace, which can be replayed
+// against other scaling policies with Replay
+type UsageRecorder struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func NewUsageRecorder(writer io.Writer) *UsageRecorder {
+	return &UsageRecorder{writer: writer}
+}
+
+// Record appends a sample to the trace
+func (r *UsageRecorder) Record(sample UsageSample) error {
+	line, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, err = r.writer.Write(append(line, '\n'))
+	return err
+}
+
+// ReadUsageTrace reads a JSONL trace written by a UsageRecorder
+func ReadUsageTrace(reader io.Reader) ([]UsageSample, error) {
+	samples := []UsageSample{}
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var sample UsageSample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("invalid sample on line %d: %w", line, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
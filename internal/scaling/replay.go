@@ -0,0 +1,216 @@
+package scaling
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// CPUModel describes the simulated CPU a usage trace is replayed on, frequencies are in kHz
+type CPUModel struct {
+	MinFrequency uint
+	MaxFrequency uint
+	// FrequencyStep is the granularity of the frequencies the CPU accepts, any frequency is accepted if 0
+	FrequencyStep uint
+	// InitialFrequency is the frequency the CPU runs at before the first update, MaxFrequency if 0
+	InitialFrequency uint
+}
+
+// ReplayDecision is a frequency target set by the scaler during a replay
+type ReplayDecision struct {
+	Time time.Time `json:"time"`
+	CPU  uint      `json:"cpu"`
+	// Usage read by the update which set the frequency, in percent
+	Usage     int  `json:"usage"`
+	Frequency uint `json:"frequency"`
+}
+
+// ReplayResult summarises the replay of the usage trace of one CPU
+type ReplayResult struct {
+	CPU       uint
+	Decisions []ReplayDecision
+	Duration  time.Duration
+	// TimeAboveTarget is the time the simulated usage exceeded the target usage by more than the allowed difference
+	TimeAboveTarget time.Duration
+	Writes          int
+	// Oscillations counts the reversals of direction between consecutive frequency changes
+	Oscillations int
+	// AverageFrequency is the time weighted average of the simulated frequency, in kHz
+	AverageFrequency uint
+	// GainPercent is the gain adapted by the end of the trace, 0 if it is not adapted
+	GainPercent int
+}
+
+// Replay runs the updater against a simulated CPU for each CPU of a usage trace, with the options newOpts
+// returns for it, and reports the frequency decisions of the updater. The workload of each sample is modelled
+// as the work the usage represents at the frequency it was measured at, so the simulated usage follows the
+// frequencies set by the updater. Usage measured at 100 percent is saturated and may underestimate the work.
+func Replay(trace []UsageSample, model CPUModel, newOpts func(cpu power.Cpu, usageSource UsageSource) CPUScalingOpts) []ReplayResult {
+	samplesByCPU := map[uint][]UsageSample{}
+	for _, sample := range trace {
+		samplesByCPU[sample.CPU] = append(samplesByCPU[sample.CPU], sample)
+	}
+	cpuIDs := make([]uint, 0, len(samplesByCPU))
+	for cpuID := range samplesByCPU {
+		cpuIDs = append(cpuIDs, cpuID)
+	}
+	slices.Sort(cpuIDs)
+
+	results := make([]ReplayResult, 0, len(cpuIDs))
+	for _, cpuID := range cpuIDs {
+		samples := samplesByCPU[cpuID]
+		slices.SortStableFunc(samples, func(a, b UsageSample) int { return a.Time.Compare(b.Time) })
+		results = append(results, replayCPUTrace(cpuID, samples, model, newOpts))
+	}
+	return results
+}
+
+func replayCPUTrace(
+	cpuID uint, samples []UsageSample, model CPUModel,
+	newOpts func(cpu power.Cpu, usageSource UsageSource) CPUScalingOpts,
+) ReplayResult {
+	cpu := &replayCPU{id: cpuID, model: model, frequency: model.InitialFrequency}
+	if cpu.frequency == 0 {
+		cpu.frequency = model.MaxFrequency
+	}
+	source := &replayUsageSource{cpu: cpu}
+	opts := newOpts(cpu, source)
+
+	result := ReplayResult{CPU: cpuID}
+	start, end := samples[0].Time, samples[len(samples)-1].Time
+	now := start
+	// the updates run on the time of the trace
+	updater := &cpuScalingUpdaterImpl{logger: ctrl.Log.WithName("replay"), clock: func() time.Time { return now }}
+	var frequencyTime float64
+	lastDirection := 0
+	for i := 0; now.Before(end); {
+		for i+1 < len(samples) && !samples[i+1].Time.After(now) {
+			i++
+		}
+		source.sample = samples[i]
+
+		prevFrequency, writes := cpu.frequency, cpu.writes
+		wait := updater.Update(&opts)
+		if wait <= 0 {
+			wait = schedulerTick
+		}
+		interval := min(wait, end.Sub(now))
+
+		if cpu.writes > writes {
+			result.Decisions = append(result.Decisions, ReplayDecision{
+				Time:      now,
+				CPU:       cpuID,
+				Usage:     opts.usage,
+				Frequency: cpu.frequency,
+			})
+			direction := 0
+			switch {
+			case cpu.frequency > prevFrequency:
+				direction = 1
+			case cpu.frequency < prevFrequency:
+				direction = -1
+			}
+			if direction != 0 && lastDirection != 0 && direction != lastDirection {
+				result.Oscillations++
+			}
+			if direction != 0 {
+				lastDirection = direction
+			}
+		}
+
+		// the workload of the sample runs at the frequency set until the next update
+		if usage, err := source.GetUsagePercent(cpuID); err == nil && usage > opts.TargetUsage+opts.AllowedUsageDifference {
+			result.TimeAboveTarget += interval
+		}
+		frequencyTime += float64(cpu.frequency) * interval.Seconds()
+		now = now.Add(wait)
+	}
+
+	result.Writes = cpu.writes
+	result.GainPercent = opts.AdaptiveGain.percent()
+	result.Duration = end.Sub(start)
+	if result.Duration > 0 {
+		result.AverageFrequency = uint(frequencyTime / result.Duration.Seconds())
+	}
+	return result
+}
+
+// replayCPU simulates the frequency of a CPU set by the actuators
+type replayCPU struct {
+	power.Cpu
+	id        uint
+	model     CPUModel
+	frequency uint
+	writes    int
+}
+
+func (c *replayCPU) GetID() uint { return c.id }
+
+func (c *replayCPU) GetCurrentCPUFrequency() (uint, error) { return c.frequency, nil }
+
+func (c *replayCPU) SetCPUFrequency(frequency uint) error {
+	c.frequency = frequency
+	c.writes++
+	return nil
+}
+
+// SetFrequencyConstraint models a loaded CPU under the max-freq actuator, which runs at its cap
+func (c *replayCPU) SetFrequencyConstraint(_ power.FrequencyRequester, constraint power.FrequencyConstraint) error {
+	return c.SetCPUFrequency(constraint.Max)
+}
+
+func (c *replayCPU) ClearFrequencyConstraint(power.FrequencyRequester) error { return nil }
+
+// SetEPPOverride models the hardware running at the top of the frequency band of the EPP level
+func (c *replayCPU) SetEPPOverride(epp string) error {
+	level := slices.Index(eppLevels, epp)
+	freqRange := c.model.MaxFrequency - c.model.MinFrequency
+	return c.SetCPUFrequency(c.model.MinFrequency + freqRange*uint(level+1)/uint(len(eppLevels)))
+}
+
+func (c *replayCPU) ClearEPPOverride() error { return nil }
+
+// C-states do not change the simulated frequency
+func (c *replayCPU) SetCStatesOverride(map[string]bool) error { return nil }
+
+func (c *replayCPU) ClearCStatesOverride() error { return nil }
+
+func (c *replayCPU) SnapFrequency(frequency uint) uint {
+	if c.model.FrequencyStep == 0 {
+		return frequency
+	}
+	snapped := (frequency + c.model.FrequencyStep/2) / c.model.FrequencyStep * c.model.FrequencyStep
+	return min(max(snapped, c.model.MinFrequency), c.model.MaxFrequency)
+}
+
+func (c *replayCPU) GetFrequencyLimitsExcluding(power.FrequencyRequester) (uint, uint) {
+	return c.model.MinFrequency, c.model.MaxFrequency
+}
+
+// replayUsageSource reports the usage of the workload of the current sample at the simulated frequency
+type replayUsageSource struct {
+	cpu    *replayCPU
+	sample UsageSample
+}
+
+func (s *replayUsageSource) GetUsagePercent(uint) (int, error) {
+	if s.sample.Error != "" {
+		return 0, errors.New(s.sample.Error)
+	}
+	if s.cpu.frequency == 0 {
+		return 100, nil
+	}
+	sampleFrequency := s.sample.Frequency
+	if sampleFrequency == 0 {
+		sampleFrequency = s.cpu.model.MaxFrequency
+	}
+	work := uint(s.sample.Usage) * sampleFrequency
+	return int(min(100, work/s.cpu.frequency)), nil
+}
+
+func (s *replayUsageSource) CloseConnection(string) {}
+
+func (s *replayUsageSource) Close() {}
@@ -0,0 +1,156 @@
+package scaling
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageRecorder(t *testing.T) {
+	start := time.Unix(1000, 0).UTC()
+	samples := []UsageSample{
+		{Time: start, CPU: 2, Usage: 70, Frequency: 2000000},
+		{Time: start.Add(10 * time.Millisecond), CPU: 2, Frequency: 2000000, Error: ErrDPDKMetricStale.Error()},
+	}
+	buf := &bytes.Buffer{}
+	recorder := NewUsageRecorder(buf)
+	for _, sample := range samples {
+		require.NoError(t, recorder.Record(sample))
+	}
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+
+	trace, err := ReadUsageTrace(buf)
+	require.NoError(t, err)
+	assert.Equal(t, samples, trace)
+
+	_, err = ReadUsageTrace(strings.NewReader("{\"cpu\": 1}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Unix(1000, 0)
+	model := CPUModel{MinFrequency: 1000000, MaxFrequency: 3000000, FrequencyStep: 100000}
+	newOpts := func(cpu power.Cpu, usageSource UsageSource) CPUScalingOpts {
+		return CPUScalingOpts{
+			CPU:                        cpu,
+			UsageSource:                usageSource,
+			SamplePeriod:               10 * time.Millisecond,
+			CooldownPeriod:             30 * time.Millisecond,
+			TargetUsage:                80,
+			AllowedUsageDifference:     5,
+			AllowedFrequencyDifference: 25000,
+			MinFrequency:               int(model.MinFrequency),
+			MaxFrequency:               int(model.MaxFrequency),
+			CurrentTargetFrequency:     FrequencyNotYetSet,
+			ScaleFactor:                0.5,
+			FallbackFreq:               2000000,
+			FallbackFreqPercent:        50,
+		}
+	}
+	// a workload needing 1.6 GHz for a second, then 2.4 GHz for a second, sampled every 10ms
+	trace := []UsageSample{}
+	for step := 0; step < 200; step++ {
+		usage := 40
+		if step >= 100 {
+			usage = 60
+		}
+		trace = append(trace, UsageSample{
+			Time:      start.Add(time.Duration(step) * 10 * time.Millisecond),
+			CPU:       3,
+			Usage:     usage,
+			Frequency: 4000000,
+		})
+	}
+
+	results := Replay(trace, model, newOpts)
+	require.Len(t, results, 1)
+	result := results[0]
+	assert.Equal(t, uint(3), result.CPU)
+	assert.Equal(t, 1990*time.Millisecond, result.Duration)
+	assert.Equal(t, len(result.Decisions), result.Writes)
+	require.NotEmpty(t, result.Decisions)
+	// settles at the frequencies keeping the usage at the target
+	assert.InDelta(t, 2000000, result.Decisions[0].Frequency, 1000000)
+	assert.InDelta(t, 3000000, result.Decisions[len(result.Decisions)-1].Frequency, 200000)
+	assert.Less(t, result.TimeAboveTarget, 500*time.Millisecond)
+	assert.Greater(t, result.AverageFrequency, model.MinFrequency)
+	assert.Less(t, result.AverageFrequency, model.MaxFrequency)
+	for _, decision := range result.Decisions {
+		assert.Zero(t, decision.Frequency%model.FrequencyStep)
+	}
+
+	// samples failing to read the usage set the fallback frequency
+	failing := []UsageSample{
+		{Time: start, CPU: 1, Error: ErrDPDKMetricStale.Error()},
+		{Time: start.Add(time.Second), CPU: 1, Error: ErrDPDKMetricStale.Error()},
+	}
+	results = Replay(failing, model, newOpts)
+	require.Len(t, results, 1)
+	assert.Equal(t, []ReplayDecision{{Time: start, CPU: 1, Frequency: 2000000}}, results[0].Decisions)
+	assert.Zero(t, results[0].TimeAboveTarget)
+}
+
+func TestReplay_oscillations(t *testing.T) {
+	start := time.Unix(1000, 0)
+	model := CPUModel{MinFrequency: 1000000, MaxFrequency: 3000000, InitialFrequency: 2000000}
+	newOpts := func(cpu power.Cpu, usageSource UsageSource) CPUScalingOpts {
+		return CPUScalingOpts{
+			CPU:                    cpu,
+			UsageSource:            usageSource,
+			SamplePeriod:           10 * time.Millisecond,
+			CooldownPeriod:         10 * time.Millisecond,
+			TargetUsage:            80,
+			MinFrequency:           int(model.MinFrequency),
+			MaxFrequency:           int(model.MaxFrequency),
+			CurrentTargetFrequency: FrequencyNotYetSet,
+			ScaleFactor:            1.0,
+		}
+	}
+	// the workload alternates between light and heavy at every sample
+	trace := []UsageSample{}
+	for step := 0; step < 10; step++ {
+		usage := 20
+		if step%2 == 1 {
+			usage = 100
+		}
+		trace = append(trace, UsageSample{Time: start.Add(time.Duration(step) * 10 * time.Millisecond), Usage: usage, Frequency: 2000000})
+	}
+
+	results := Replay(trace, model, newOpts)
+	require.Len(t, results, 1)
+	assert.Equal(t, 9, results[0].Writes)
+	assert.Equal(t, 8, results[0].Oscillations)
+	assert.Zero(t, results[0].GainPercent)
+
+	// the adaptive gain dampens the swings of the frequency
+	adaptiveResults := Replay(trace, model, func(cpu power.Cpu, usageSource UsageSource) CPUScalingOpts {
+		opts := newOpts(cpu, usageSource)
+		opts.AdaptiveGain = &AdaptiveGainOpts{Window: time.Second, MaxReversals: 2, MinGain: 0.25, MaxGain: 2}
+		return opts
+	})
+	require.Len(t, adaptiveResults, 1)
+	assert.Equal(t, 25, adaptiveResults[0].GainPercent)
+	swing := func(result ReplayResult) uint {
+		decisions := result.Decisions
+		last, prev := decisions[len(decisions)-1].Frequency, decisions[len(decisions)-2].Frequency
+		return max(last, prev) - min(last, prev)
+	}
+	assert.Less(t, swing(adaptiveResults[0]), swing(results[0]))
+}
+
+func TestReplayCPU_actuators(t *testing.T) {
+	cpu := &replayCPU{model: CPUModel{MinFrequency: 1000000, MaxFrequency: 3000000}}
+
+	assert.NoError(t, cpu.SetFrequencyConstraint(power.ScalerRequester, power.FrequencyConstraint{Max: 2200000}))
+	assert.Equal(t, uint(2200000), cpu.frequency)
+	assert.NoError(t, cpu.SetEPPOverride("balance_power"))
+	assert.Equal(t, uint(2000000), cpu.frequency)
+	assert.NoError(t, cpu.SetEPPOverride("performance"))
+	assert.Equal(t, uint(3000000), cpu.frequency)
+	assert.Equal(t, 3, cpu.writes)
+}
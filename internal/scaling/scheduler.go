@@ -0,0 +1,46 @@
+package scaling
+
+import "time"
+
+// scalingEntry holds the options, next update and status of a scaled CPU
+type scalingEntry struct {
+	cpuID  uint
+	opts   *CPUScalingOpts
+	due    time.Time
+	index  int // position in the queue, -1 while the update runs
+	status CPUScalingStatus
+}
+
+// scalingQueue is a heap of scaled CPUs ordered by their next update, implementing heap.Interface
+type scalingQueue []*scalingEntry
+
+func (q scalingQueue) Len() int { return len(q) }
+
+func (q scalingQueue) Less(i, j int) bool {
+	if q[i].due.Equal(q[j].due) {
+		return q[i].cpuID < q[j].cpuID
+	}
+	return q[i].due.Before(q[j].due)
+}
+
+func (q scalingQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *scalingQueue) Push(x any) {
+	entry := x.(*scalingEntry)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *scalingQueue) Pop() any {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*q = old[:n-1]
+	return entry
+}
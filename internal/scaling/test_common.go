@@ -23,6 +23,8 @@ func setupScalingTestFiles(cores int, cpufiles map[string]string) (power.Host, f
 	scalingMaxFile := "cpufreq/scaling_max_freq"
 	scalingMinFile := "cpufreq/scaling_min_freq"
 	availGovFile := "cpufreq/scaling_available_governors"
+	availFreqsFile := "cpufreq/scaling_available_frequencies"
+	eppFile := "cpufreq/energy_performance_preference"
 
 	// Create directories and files for test CPUs
 	for i := 0; i < cores; i++ {
@@ -63,6 +65,13 @@ func setupScalingTestFiles(cores int, cpufiles map[string]string) (power.Host, f
 			}
 		}
 
+		if value, exists := cpufiles["available_frequencies"]; exists {
+			os.WriteFile(filepath.Join(cpudir, availFreqsFile), []byte(value+"\n"), 0o644)
+		}
+		if value, exists := cpufiles["epp"]; exists {
+			os.WriteFile(filepath.Join(cpudir, eppFile), []byte(value+"\n"), 0o644)
+		}
+
 		// Minimal topology info
 		os.WriteFile(filepath.Join(cpudir, "topology", "physical_package_id"), []byte("0\n"), 0o664)
 		os.WriteFile(filepath.Join(cpudir, "topology", "die_id"), []byte("0\n"), 0o664)
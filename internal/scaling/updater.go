@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/intel/power-optimization-library/pkg/power"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -12,38 +13,50 @@ type CPUScalingUpdater interface {
 }
 
 type cpuScalingUpdaterImpl struct {
-	dpdkClient DPDKTelemetryClient
-	logger     logr.Logger
+	logger logr.Logger
+	// clock of the updates, the scheduler's if not set
+	clock func() time.Time
 }
 
-func NewCPUScalingUpdater(dpdkClient DPDKTelemetryClient) CPUScalingUpdater {
+func NewCPUScalingUpdater() CPUScalingUpdater {
 	updater := &cpuScalingUpdaterImpl{
-		dpdkClient: dpdkClient,
-		logger:     ctrl.Log.WithName("CPUScalingUpdater"),
+		logger: ctrl.Log.WithName("CPUScalingUpdater"),
 	}
 
 	return updater
 }
 
+// now returns the time of the update
+func (u *cpuScalingUpdaterImpl) now() time.Time {
+	if u.clock != nil {
+		return u.clock()
+	}
+	return getSchedulerTime()
+}
+
 // Update inspects the current state (usage percentage, frequency) for the managed
 // CPU and sets a new target frequency when needed, and returns the duration
-// until the next update (cooldown or sample period).
+// until the next update (cooldown or sample period). The usage is read from the
+// usage source of the CPU's workload type.
 func (u *cpuScalingUpdaterImpl) Update(opts *CPUScalingOpts) time.Duration {
-	currentUsage, err := u.dpdkClient.GetUsagePercent(opts.CPU.GetID())
-	if err != nil {
-		if frequencyInAllowedDifference(opts.FallbackFreq, opts) {
-			return opts.SamplePeriod
-		}
+	u.updateFrequencyRange(opts)
 
-		err = opts.CPU.SetCPUFrequency(uint(opts.FallbackFreq))
-		if err != nil {
-			u.logger.Error(err, "failed to set fallback frequency", "cpu", opts.CPU.GetID(), "fallback_freq", opts.FallbackFreq)
-			return opts.SamplePeriod
-		}
-		opts.CurrentTargetFrequency = opts.FallbackFreq
+	currentUsage, err := opts.UsageSource.GetUsagePercent(opts.CPU.GetID())
+	opts.usage, opts.usageErr = currentUsage, err
+	u.updateIdleCStates(opts, currentUsage, err)
+	if err != nil {
+		u.setFallbackFrequency(opts)
 		return opts.SamplePeriod
 	}
 
+	if opts.BurstUsage > 0 && currentUsage >= opts.BurstUsage {
+		return u.burstToMaxFrequency(opts, currentUsage)
+	}
+
+	if opts.PID != nil {
+		return u.updatePID(opts, currentUsage)
+	}
+
 	if currentUsage >= opts.TargetUsage-opts.AllowedUsageDifference &&
 		currentUsage <= opts.TargetUsage+opts.AllowedUsageDifference {
 		return opts.SamplePeriod
@@ -51,41 +64,85 @@ func (u *cpuScalingUpdaterImpl) Update(opts *CPUScalingOpts) time.Duration {
 
 	currentFrequencyUint, err := opts.CPU.GetCurrentCPUFrequency()
 	if err != nil {
-		if frequencyInAllowedDifference(opts.FallbackFreq, opts) {
-			return opts.SamplePeriod
-		}
-
-		err = opts.CPU.SetCPUFrequency(uint(opts.FallbackFreq))
-		if err != nil {
-			u.logger.Error(err, "failed to set fallback frequency", "cpu", opts.CPU.GetID(), "fallback_freq", opts.FallbackFreq)
-			return opts.SamplePeriod
-		}
-		opts.CurrentTargetFrequency = opts.FallbackFreq
+		u.setFallbackFrequency(opts)
 		return opts.SamplePeriod
 	}
 	currentFrequency := int(currentFrequencyUint)
 
+	scaleFactor := opts.ScaleFactor
+	if currentUsage < opts.TargetUsage && opts.ScaleDownFactor > 0 {
+		scaleFactor = opts.ScaleDownFactor
+	}
+	scaleFactor *= opts.AdaptiveGain.factor()
 	nextFrequencyFloat :=
-		float64(currentFrequency) * (1.0 + (float64(currentUsage)/float64(opts.TargetUsage)-1.0)*opts.ScaleFactor)
-	nextFrequency := int(nextFrequencyFloat)
+		float64(currentFrequency) * (1.0 + (float64(currentUsage)/float64(opts.TargetUsage)-1.0)*scaleFactor)
+	nextFrequency := limitFrequencyStep(opts, int(nextFrequencyFloat), currentFrequency)
+
+	return u.setNextFrequency(opts, nextFrequency, currentUsage, currentFrequency)
+}
 
-	if nextFrequency < opts.HWMinFrequency {
-		nextFrequency = opts.HWMinFrequency
+// burstToMaxFrequency sets the maximum frequency at once when the usage reaches the burst threshold,
+// the PID controller restarts from it at the next update
+func (u *cpuScalingUpdaterImpl) burstToMaxFrequency(opts *CPUScalingOpts, currentUsage int) time.Duration {
+	currentFrequency, err := opts.CPU.GetCurrentCPUFrequency()
+	if err != nil {
+		u.setFallbackFrequency(opts)
+		return opts.SamplePeriod
 	}
-	if nextFrequency > opts.HWMaxFrequency {
-		nextFrequency = opts.HWMaxFrequency
+	if opts.PID != nil {
+		opts.PID.started = false
 	}
+	return u.setNextFrequency(opts, opts.MaxFrequency, currentUsage, int(currentFrequency))
+}
+
+// limitFrequencyStep limits the change of the next frequency to MaxFrequencyStep from the current target,
+// or from the current frequency before the first target is set
+func limitFrequencyStep(opts *CPUScalingOpts, nextFrequency, currentFrequency int) int {
+	baseFrequency := opts.CurrentTargetFrequency
+	if baseFrequency == FrequencyNotYetSet {
+		baseFrequency = currentFrequency
+	}
+	if opts.MaxFrequencyStep <= 0 || baseFrequency < 0 {
+		return nextFrequency
+	}
+	return min(max(nextFrequency, baseFrequency-opts.MaxFrequencyStep), baseFrequency+opts.MaxFrequencyStep)
+}
+
+// setNextFrequency clamps the next frequency to the frequency range and sets it unless it is
+// within the allowed difference of the current target, returning the duration until the next update,
+// the scale-down cooldown if the target was lowered
+func (u *cpuScalingUpdaterImpl) setNextFrequency(opts *CPUScalingOpts, nextFrequency, currentUsage, currentFrequency int) time.Duration {
+	if nextFrequency < opts.MinFrequency {
+		nextFrequency = opts.MinFrequency
+	}
+	if nextFrequency > opts.MaxFrequency {
+		nextFrequency = opts.MaxFrequency
+	}
+	// drivers with discrete frequencies round the target, compare what will actually be set
+	nextFrequency = int(opts.CPU.SnapFrequency(uint(nextFrequency)))
 
 	if frequencyInAllowedDifference(nextFrequency, opts) {
 		return opts.SamplePeriod
 	}
 
-	err = opts.CPU.SetCPUFrequency(uint(nextFrequency))
+	err := applyFrequency(opts, nextFrequency)
 	if err != nil {
 		u.logger.Error(err, "failed to set next frequency", "cpu", opts.CPU.GetID(), "next_freq", nextFrequency)
 		return opts.SamplePeriod
 	}
+	prevFrequency := opts.CurrentTargetFrequency
+	if prevFrequency == FrequencyNotYetSet {
+		prevFrequency = currentFrequency
+	}
 	opts.CurrentTargetFrequency = nextFrequency
+	opts.lastChange = u.now()
+	if opts.AdaptiveGain.recordChange(prevFrequency, nextFrequency, opts.lastChange) {
+		u.logger.V(5).Info("adapted gain", "cpu", opts.CPU.GetID(), "gain_percent", opts.AdaptiveGain.percent())
+		// the PID controller restarts from the current target, so its output does not jump with the gain
+		if opts.PID != nil {
+			opts.PID.started = false
+		}
+	}
 	u.logger.V(6).Info("set next frequency",
 		"cpu", opts.CPU.GetID(),
 		"usage", currentUsage,
@@ -93,9 +150,46 @@ func (u *cpuScalingUpdaterImpl) Update(opts *CPUScalingOpts) time.Duration {
 		"next_freq", nextFrequency,
 	)
 
+	if opts.ScaleDownCooldownPeriod > 0 && nextFrequency < prevFrequency {
+		return opts.ScaleDownCooldownPeriod
+	}
 	return opts.CooldownPeriod
 }
 
+// updateFrequencyRange follows changes of the effective frequency limits of the CPU, e.g. when the P-states of
+// its profile are updated or another control loop constrains its frequency, and moves the fallback frequency
+// within the new range
+func (u *cpuScalingUpdaterImpl) updateFrequencyRange(opts *CPUScalingOpts) {
+	// the cap of the max-freq actuator is not part of the range it scales in
+	minFreq, maxFreq := opts.CPU.GetFrequencyLimitsExcluding(power.ScalerRequester)
+	if int(minFreq) == opts.MinFrequency && int(maxFreq) == opts.MaxFrequency {
+		return
+	}
+	u.logger.V(5).Info("frequency range changed", "cpu", opts.CPU.GetID(), "min", minFreq, "max", maxFreq)
+	opts.MinFrequency, opts.MaxFrequency = int(minFreq), int(maxFreq)
+	opts.FallbackFreq = FallbackFrequency(minFreq, maxFreq, opts.FallbackFreqPercent)
+}
+
+// FallbackFrequency returns the frequency at fallbackFreqPercent of the range between minFreq and maxFreq
+func FallbackFrequency(minFreq, maxFreq uint, fallbackFreqPercent int) int {
+	return int(minFreq + (maxFreq-minFreq)*uint(fallbackFreqPercent)/100)
+}
+
+// setFallbackFrequency sets the fallback frequency, snapped to the frequencies the CPU accepts
+func (u *cpuScalingUpdaterImpl) setFallbackFrequency(opts *CPUScalingOpts) {
+	fallbackFreq := int(opts.CPU.SnapFrequency(uint(opts.FallbackFreq)))
+	if frequencyInAllowedDifference(fallbackFreq, opts) {
+		return
+	}
+
+	if err := applyFrequency(opts, fallbackFreq); err != nil {
+		u.logger.Error(err, "failed to set fallback frequency", "cpu", opts.CPU.GetID(), "fallback_freq", fallbackFreq)
+		return
+	}
+	opts.CurrentTargetFrequency = fallbackFreq
+	opts.lastChange = u.now()
+}
+
 func frequencyInAllowedDifference(frequency int, opts *CPUScalingOpts) bool {
 	if opts.CurrentTargetFrequency != FrequencyNotYetSet &&
 		frequency >= opts.CurrentTargetFrequency-opts.AllowedFrequencyDifference &&
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/intel/power-optimization-library/pkg/power"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -49,8 +50,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				CurrentTargetFrequency:     1340980,
 				FallbackFreq:               2000000,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.0,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -70,8 +71,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     2134003,
-				HWMaxFrequency:             3200000,
-				HWMinFrequency:             1000000,
+				MaxFrequency:               3200000,
+				MinFrequency:               1000000,
 				ScaleFactor:                0.5,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             35 * time.Millisecond,
@@ -91,8 +92,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     3024616,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.9,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             35 * time.Millisecond,
@@ -112,8 +113,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     2844827,
-				HWMaxFrequency:             3200000,
-				HWMinFrequency:             1000000,
+				MaxFrequency:               3200000,
+				MinFrequency:               1000000,
 				ScaleFactor:                1.6,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -133,8 +134,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     1260000,
-				HWMaxFrequency:             3200000,
-				HWMinFrequency:             1000000,
+				MaxFrequency:               3200000,
+				MinFrequency:               1000000,
 				ScaleFactor:                0.75,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -154,8 +155,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     2500000,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.0,
 				AllowedFrequencyDifference: 10000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -175,8 +176,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               15 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     3418600,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.0,
 				AllowedFrequencyDifference: 100000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -196,8 +197,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               15 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     FrequencyNotYetSet,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.0,
 				AllowedFrequencyDifference: 100000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -217,8 +218,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     3512500,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				ScaleFactor:                1.0,
 				AllowedFrequencyDifference: 100000,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -238,8 +239,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     2000000,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				AllowedFrequencyDifference: 100000,
 				ScaleFactor:                1.0,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -259,8 +260,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     3512500,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				AllowedFrequencyDifference: 100000,
 				ScaleFactor:                1.0,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -280,8 +281,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 				SamplePeriod:               10 * time.Millisecond,
 				FallbackFreq:               2000000,
 				CurrentTargetFrequency:     2000000,
-				HWMaxFrequency:             3700000,
-				HWMinFrequency:             400000,
+				MaxFrequency:               3700000,
+				MinFrequency:               400000,
 				AllowedFrequencyDifference: 100000,
 				ScaleFactor:                1.0,
 				CooldownPeriod:             20 * time.Millisecond,
@@ -299,8 +300,8 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 			// Setup mock filesystem with userspace governor
 			host, teardown, err := setupScalingTestFiles(3, map[string]string{
 				"governor": "userspace",
-				"max":      fmt.Sprintf("%d", tc.scalingOpts.HWMaxFrequency),
-				"min":      fmt.Sprintf("%d", tc.scalingOpts.HWMinFrequency),
+				"max":      fmt.Sprintf("%d", tc.scalingOpts.MaxFrequency),
+				"min":      fmt.Sprintf("%d", tc.scalingOpts.MinFrequency),
 			})
 			assert.NoError(t, err)
 			defer teardown()
@@ -321,8 +322,10 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 			dpdkmock := &MockDPDKTelemetryClient{}
 			dpdkmock.On("GetUsagePercent").Return(tc.currentUsage, tc.usageErr)
 
+			tc.scalingOpts.UsageSource = dpdkmock
+
 			// Run updater to set the frequency
-			updater := &cpuScalingUpdaterImpl{dpdkClient: dpdkmock}
+			updater := &cpuScalingUpdaterImpl{}
 			nextSetIn := updater.Update(tc.scalingOpts)
 
 			// Verify setspeed matches expected frequency
@@ -336,3 +339,246 @@ func TestCPUScalingUpdater_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestCPUScalingUpdater_Update_availableFrequencies(t *testing.T) {
+	host, teardown, err := setupScalingTestFiles(1, map[string]string{
+		"driver":                "acpi-cpufreq",
+		"max":                   "3000000",
+		"min":                   "1000000",
+		"available_frequencies": "3000000 2500000 2000000 1500000 1000000",
+	})
+	assert.NoError(t, err)
+	defer teardown()
+
+	curFreqPath := filepath.Join("testing", "cpus", "cpu0", "cpufreq", "scaling_cur_freq")
+	setFreqPath := filepath.Join("testing", "cpus", "cpu0", "cpufreq", "scaling_setspeed")
+	opts := &CPUScalingOpts{
+		CPU:                        host.GetAllCpus().ByID(0),
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             20 * time.Millisecond,
+		CurrentTargetFrequency:     2000000,
+		FallbackFreq:               2100000,
+		MaxFrequency:               3000000,
+		MinFrequency:               1000000,
+		ScaleFactor:                1.0,
+		AllowedFrequencyDifference: 10000,
+	}
+	update := func(usage int, usageErr error) (string, time.Duration) {
+		assert.NoError(t, os.WriteFile(setFreqPath, []byte(""), 0o644))
+		dpdkmock := &MockDPDKTelemetryClient{}
+		dpdkmock.On("GetUsagePercent").Return(usage, usageErr)
+		opts.UsageSource = dpdkmock
+		nextSetIn := (&cpuScalingUpdaterImpl{}).Update(opts)
+		frequency, err := os.ReadFile(setFreqPath)
+		assert.NoError(t, err)
+		return strings.TrimSpace(string(frequency)), nextSetIn
+	}
+	assert.NoError(t, os.WriteFile(curFreqPath, []byte("2000000\n"), 0o644))
+
+	// 2000000 * 88 / 80 = 2200000 is rounded back to the current target, nothing is written
+	frequency, nextSetIn := update(88, nil)
+	assert.Equal(t, "", frequency)
+	assert.Equal(t, 10*time.Millisecond, nextSetIn)
+	assert.Equal(t, 2000000, opts.CurrentTargetFrequency)
+
+	// 2000000 * 96 / 80 = 2400000 is snapped to the next available frequency
+	frequency, nextSetIn = update(96, nil)
+	assert.Equal(t, "2500000", frequency)
+	assert.Equal(t, 20*time.Millisecond, nextSetIn)
+	assert.Equal(t, 2500000, opts.CurrentTargetFrequency)
+
+	// the fallback frequency is snapped as well
+	frequency, nextSetIn = update(0, fmt.Errorf("telemetry unavailable"))
+	assert.Equal(t, "2000000", frequency)
+	assert.Equal(t, 10*time.Millisecond, nextSetIn)
+	assert.Equal(t, 2000000, opts.CurrentTargetFrequency)
+}
+
+func TestCPUScalingUpdater_Update_frequencyRange(t *testing.T) {
+	host, teardown, err := setupScalingTestFiles(1, map[string]string{
+		"max": "3000000",
+		"min": "1000000",
+	})
+	assert.NoError(t, err)
+	defer teardown()
+
+	cpu := host.GetAllCpus().ByID(0)
+	setFreqPath := filepath.Join("testing", "cpus", "cpu0", "cpufreq", "scaling_setspeed")
+	opts := &CPUScalingOpts{
+		CPU:                        cpu,
+		TargetUsage:                80,
+		AllowedUsageDifference:     5,
+		SamplePeriod:               10 * time.Millisecond,
+		CooldownPeriod:             20 * time.Millisecond,
+		CurrentTargetFrequency:     FrequencyNotYetSet,
+		MinFrequency:               1000000,
+		MaxFrequency:               3000000,
+		FallbackFreq:               1500000,
+		FallbackFreqPercent:        25,
+		ScaleFactor:                1.0,
+		AllowedFrequencyDifference: 10000,
+	}
+	update := func(usage int, usageErr error) string {
+		assert.NoError(t, os.WriteFile(setFreqPath, []byte(""), 0o644))
+		dpdkmock := &MockDPDKTelemetryClient{}
+		dpdkmock.On("GetUsagePercent").Return(usage, usageErr)
+		opts.UsageSource = dpdkmock
+		(&cpuScalingUpdaterImpl{}).Update(opts)
+		frequency, err := os.ReadFile(setFreqPath)
+		assert.NoError(t, err)
+		return strings.TrimSpace(string(frequency))
+	}
+
+	// the profile narrows the P-states of the CPU
+	assert.NoError(t, cpu.SetFrequencyConstraint(power.ProfileRequester, power.FrequencyConstraint{Min: 1500000, Max: 2500000}))
+
+	// 2000000 * 100 / 80 = 2500000 is within the profile's range
+	assert.Equal(t, "2500000", update(100, nil))
+	assert.Equal(t, 1500000, opts.MinFrequency)
+	assert.Equal(t, 2500000, opts.MaxFrequency)
+	// the fallback frequency is relative to the profile's range
+	assert.Equal(t, 1750000, opts.FallbackFreq)
+	assert.Equal(t, "1750000", update(0, fmt.Errorf("telemetry unavailable")))
+
+	// other constraints narrow the range further
+	assert.NoError(t, cpu.SetFrequencyConstraint(power.ThermalRequester, power.FrequencyConstraint{Max: 2000000}))
+	assert.Equal(t, "2000000", update(100, nil))
+	assert.Equal(t, 2000000, opts.MaxFrequency)
+
+	// the range is restored with the constraints
+	assert.NoError(t, cpu.ClearFrequencyConstraint(power.ThermalRequester))
+	assert.NoError(t, cpu.ClearFrequencyConstraint(power.ProfileRequester))
+	assert.Equal(t, "1000000", update(20, nil))
+	assert.Equal(t, 1000000, opts.MinFrequency)
+	assert.Equal(t, 3000000, opts.MaxFrequency)
+	assert.Equal(t, 1500000, opts.FallbackFreq)
+}
+
+func TestCPUScalingUpdater_Update_asymmetric(t *testing.T) {
+	tCases := []struct {
+		testCase       string
+		demand         uint
+		targetFreq     int
+		modify         func(opts *CPUScalingOpts)
+		expectedFreq   uint
+		expectedNextIn time.Duration
+	}{
+		{
+			testCase:       "scales up with the scale-up factor",
+			demand:         2000000, // usage 100
+			targetFreq:     2000000,
+			expectedFreq:   2500000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+		{
+			testCase:       "scales down with the scale-down factor and cooldown",
+			demand:         800000, // usage 40
+			targetFreq:     2000000,
+			expectedFreq:   1750000,
+			expectedNextIn: 100 * time.Millisecond,
+		},
+		{
+			testCase:   "scale-down factor falls back to the scale factor",
+			demand:     800000,
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.ScaleDownFactor = 0
+			},
+			expectedFreq:   1000000,
+			expectedNextIn: 100 * time.Millisecond,
+		},
+		{
+			testCase:   "scale-down cooldown falls back to the cooldown",
+			demand:     800000,
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.ScaleDownCooldownPeriod = 0
+			},
+			expectedFreq:   1750000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+		{
+			testCase:   "step up is limited",
+			demand:     2000000,
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.MaxFrequencyStep = 200000
+			},
+			expectedFreq:   2200000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+		{
+			testCase:   "step down is limited",
+			demand:     800000,
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.ScaleDownFactor = 1.0
+				opts.MaxFrequencyStep = 200000
+			},
+			expectedFreq:   1800000,
+			expectedNextIn: 100 * time.Millisecond,
+		},
+		{
+			testCase:   "step is limited from the current frequency before the first target",
+			demand:     2000000,
+			targetFreq: FrequencyNotYetSet,
+			modify: func(opts *CPUScalingOpts) {
+				opts.MaxFrequencyStep = 200000
+			},
+			expectedFreq:   2200000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+		{
+			testCase:   "bursts to the maximum frequency regardless of the step limit",
+			demand:     2000000,
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.MaxFrequencyStep = 200000
+				opts.BurstUsage = 95
+			},
+			expectedFreq:   3000000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+		{
+			testCase:   "does not burst below the threshold",
+			demand:     1800000, // usage 90
+			targetFreq: 2000000,
+			modify: func(opts *CPUScalingOpts) {
+				opts.BurstUsage = 95
+			},
+			expectedFreq:   2250000,
+			expectedNextIn: 30 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range tCases {
+		t.Run(tc.testCase, func(t *testing.T) {
+			cpu := &simCPU{frequency: 2000000, demand: tc.demand}
+			opts := &CPUScalingOpts{
+				CPU:                        cpu,
+				UsageSource:                cpu,
+				TargetUsage:                80,
+				AllowedUsageDifference:     5,
+				AllowedFrequencyDifference: 1000,
+				SamplePeriod:               10 * time.Millisecond,
+				CooldownPeriod:             30 * time.Millisecond,
+				ScaleDownCooldownPeriod:    100 * time.Millisecond,
+				CurrentTargetFrequency:     tc.targetFreq,
+				MinFrequency:               1000000,
+				MaxFrequency:               3000000,
+				ScaleFactor:                1.0,
+				ScaleDownFactor:            0.25,
+			}
+			if tc.modify != nil {
+				tc.modify(opts)
+			}
+
+			nextIn := (&cpuScalingUpdaterImpl{}).Update(opts)
+			assert.Equal(t, tc.expectedFreq, cpu.frequency)
+			assert.Equal(t, int(tc.expectedFreq), opts.CurrentTargetFrequency)
+			assert.Equal(t, tc.expectedNextIn, nextIn)
+		})
+	}
+}
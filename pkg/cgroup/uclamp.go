@@ -0,0 +1,148 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+const (
+	defaultCgroupRoot     = "/sys/fs/cgroup"
+	cgroupControllersFile = "cgroup.controllers"
+	uclampMinFile         = "cpu.uclamp.min"
+	uclampMaxFile         = "cpu.uclamp.max"
+	// kubelet creates pod cgroups under kubepods (cgroupfs driver) or kubepods.slice (systemd driver)
+	kubepodsPrefix = "kubepods"
+)
+
+// ErrUnsupported is returned when uclamp values cannot be written on the node: without cgroup v2, with a kernel
+// not exposing cpu.uclamp.*, or when the pod cgroups are not mounted writable into the node agent
+var ErrUnsupported = errors.New("uclamp is not supported")
+
+// UClamp holds utilisation clamping values, in percent
+type UClamp struct {
+	Min int
+	Max int
+}
+
+// UClampWriter applies utilisation clamping to container cgroups
+type UClampWriter interface {
+	SetContainerUClamp(podUID string, containerID string, uclamp UClamp) error
+}
+
+type fsUClampWriter struct {
+	root string
+}
+
+// NewUClampWriter returns a UClampWriter operating on the cgroup v2 hierarchy mounted at /sys/fs/cgroup
+func NewUClampWriter() UClampWriter {
+	return &fsUClampWriter{root: defaultCgroupRoot}
+}
+
+// SetContainerUClamp writes cpu.uclamp.min and cpu.uclamp.max of the cgroup belonging to the container
+func (w *fsUClampWriter) SetContainerUClamp(podUID string, containerID string, uclamp UClamp) error {
+	if uclamp.Min < 0 || uclamp.Max > 100 || uclamp.Min > uclamp.Max {
+		return fmt.Errorf("invalid uclamp range %d-%d", uclamp.Min, uclamp.Max)
+	}
+	if _, err := os.Stat(filepath.Join(w.root, cgroupControllersFile)); err != nil {
+		return fmt.Errorf("%w, cgroup v2 is required: %v", ErrUnsupported, err)
+	}
+	cgroupPath, err := w.findContainerCgroup(podUID, StripContainerRuntime(containerID))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(cgroupPath, uclampMaxFile)); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w, %s is not exposed by the kernel", ErrUnsupported, uclampMaxFile)
+	}
+	// max is written first so a raised min never exceeds the current max
+	if err := writeUClampFile(filepath.Join(cgroupPath, uclampMaxFile), uclamp.Max); err != nil {
+		return err
+	}
+	return writeUClampFile(filepath.Join(cgroupPath, uclampMinFile), uclamp.Min)
+}
+
+// writeUClampFile writes a uclamp percentage, pod cgroups mounted read-only are reported as unsupported
+func writeUClampFile(path string, percent int) error {
+	err := os.WriteFile(path, []byte(formatUClamp(percent)), 0644)
+	if errors.Is(err, syscall.EROFS) {
+		return fmt.Errorf("%w, the pod cgroups are mounted read-only: %v", ErrUnsupported, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// findContainerCgroup locates the container's cgroup below the pod's cgroup. Both the cgroupfs
+// (pod<uid>/<id>) and systemd (kubepods-...-pod<uid_with_underscores>.slice/<runtime>-<id>.scope)
+// layouts are supported.
+func (w *fsUClampWriter) findContainerCgroup(podUID string, containerID string) (string, error) {
+	if podUID == "" || containerID == "" {
+		return "", fmt.Errorf("pod UID and container ID are required")
+	}
+	podNames := []string{"pod" + podUID, "pod" + strings.ReplaceAll(podUID, "-", "_")}
+
+	topLevel, err := os.ReadDir(w.root)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cgroup root: %w", err)
+	}
+	podPath := ""
+	for _, entry := range topLevel {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), kubepodsPrefix) {
+			continue
+		}
+		err := filepath.WalkDir(filepath.Join(w.root, entry.Name()), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			name := strings.TrimSuffix(d.Name(), ".slice")
+			for _, podName := range podNames {
+				if strings.HasSuffix(name, podName) {
+					podPath = path
+					return fs.SkipAll
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to search pod cgroup: %w", err)
+		}
+		if podPath != "" {
+			break
+		}
+	}
+	if podPath == "" {
+		return "", fmt.Errorf("cgroup of pod %s not found", podUID)
+	}
+
+	containers, err := os.ReadDir(podPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read pod cgroup: %w", err)
+	}
+	for _, entry := range containers {
+		if entry.IsDir() && strings.Contains(entry.Name(), containerID) {
+			return filepath.Join(podPath, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("cgroup of container %s not found", containerID)
+}
+
+// StripContainerRuntime removes the "<runtime>://" prefix from a container ID as reported in the pod status
+func StripContainerRuntime(containerID string) string {
+	if _, id, found := strings.Cut(containerID, "://"); found {
+		return id
+	}
+	return containerID
+}
+
+// formatUClamp formats a percentage the way cpu.uclamp.* files expect it, 100% is written as "max"
+func formatUClamp(percent int) string {
+	if percent >= 100 {
+		return "max"
+	}
+	return fmt.Sprintf("%d.00", percent)
+}
@@ -0,0 +1,95 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCgroupRoot(t *testing.T, containerDirs ...string) string {
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, cgroupControllersFile), []byte("cpu memory\n"), 0644))
+	for _, dir := range containerDirs {
+		path := filepath.Join(root, dir)
+		assert.NoError(t, os.MkdirAll(path, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, uclampMinFile), []byte("0.00\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, uclampMaxFile), []byte("max\n"), 0644))
+	}
+	return root
+}
+
+func readUClamp(t *testing.T, dir string) (string, string) {
+	minValue, err := os.ReadFile(filepath.Join(dir, uclampMinFile))
+	assert.NoError(t, err)
+	maxValue, err := os.ReadFile(filepath.Join(dir, uclampMaxFile))
+	assert.NoError(t, err)
+	return string(minValue), string(maxValue)
+}
+
+func TestSetContainerUClamp(t *testing.T) {
+	tcases := []struct {
+		name         string
+		containerDir string
+		containerID  string
+		uclamp       UClamp
+		expectedMin  string
+		expectedMax  string
+	}{
+		{
+			name:         "systemd driver",
+			containerDir: "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-poda1b2_c3d4.slice/cri-containerd-abc123.scope",
+			containerID:  "containerd://abc123",
+			uclamp:       UClamp{Min: 20, Max: 80},
+			expectedMin:  "20.00",
+			expectedMax:  "80.00",
+		},
+		{
+			name:         "cgroupfs driver",
+			containerDir: "kubepods/besteffort/poda1b2-c3d4/abc123",
+			containerID:  "docker://abc123",
+			uclamp:       UClamp{Min: 0, Max: 100},
+			expectedMin:  "0.00",
+			expectedMax:  "max",
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := setupCgroupRoot(t, tc.containerDir, "kubepods.slice/kubepods-podother.slice/cri-containerd-abc123.scope")
+			w := &fsUClampWriter{root: root}
+			assert.NoError(t, w.SetContainerUClamp("a1b2-c3d4", tc.containerID, tc.uclamp))
+			minValue, maxValue := readUClamp(t, filepath.Join(root, tc.containerDir))
+			assert.Equal(t, tc.expectedMin, minValue)
+			assert.Equal(t, tc.expectedMax, maxValue)
+		})
+	}
+}
+
+func TestSetContainerUClamp_errors(t *testing.T) {
+	root := setupCgroupRoot(t, "kubepods/poda1b2/abc123")
+	w := &fsUClampWriter{root: root}
+
+	assert.ErrorContains(t, w.SetContainerUClamp("a1b2", "abc123", UClamp{Min: 60, Max: 40}), "invalid uclamp range")
+	assert.ErrorContains(t, w.SetContainerUClamp("missing", "abc123", UClamp{Max: 100}), "cgroup of pod missing not found")
+	assert.ErrorContains(t, w.SetContainerUClamp("a1b2", "def456", UClamp{Max: 100}), "cgroup of container def456 not found")
+	assert.ErrorContains(t, w.SetContainerUClamp("a1b2", "", UClamp{Max: 100}), "container ID are required")
+
+	// kernel without uclamp support
+	assert.NoError(t, os.Remove(filepath.Join(root, "kubepods/poda1b2/abc123", uclampMaxFile)))
+	err := w.SetContainerUClamp("a1b2", "abc123", UClamp{Max: 100})
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorContains(t, err, "cpu.uclamp.max is not exposed by the kernel")
+
+	assert.NoError(t, os.Remove(filepath.Join(root, cgroupControllersFile)))
+	err = w.SetContainerUClamp("a1b2", "abc123", UClamp{Max: 100})
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorContains(t, err, "cgroup v2 is required")
+}
+
+func TestStripContainerRuntime(t *testing.T) {
+	assert.Equal(t, "abc123", StripContainerRuntime("containerd://abc123"))
+	assert.Equal(t, "abc123", StripContainerRuntime("cri-o://abc123"))
+	assert.Equal(t, "abc123", StripContainerRuntime("abc123"))
+}
@@ -0,0 +1,53 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	"sigs.k8s.io/yaml"
+)
+
+// HostStatePath is the path the host state is served on by the node agent's metrics server
+const HostStatePath = "/debug/host"
+
+type hostStateHandler struct {
+	host power.Host
+}
+
+// NewHostStateHandler returns a handler serving the power library's view of the host. The response is JSON
+// unless YAML is requested with ?format=yaml or an Accept header
+func NewHostStateHandler(host power.Host) http.Handler {
+	return &hostStateHandler{host: host}
+}
+
+func (h *hostStateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := json.MarshalIndent(h.host.GetState(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType := "application/json"
+	if wantsYAML(r) {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType = "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(data)
+}
+
+func wantsYAML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "yaml"
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "yaml") && !strings.Contains(accept, "json")
+}
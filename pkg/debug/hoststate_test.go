@@ -0,0 +1,91 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/intel/power-optimization-library/pkg/power"
+	"github.com/stretchr/testify/assert"
+)
+
+type hostMock struct {
+	power.Host
+}
+
+func (m *hostMock) GetState() power.HostState {
+	return power.HostState{
+		Name:  "node1",
+		Pools: []power.PoolState{{Name: "sharedPool", Cpus: []uint{2, 3}}},
+	}
+}
+
+func TestHostStateHandler(t *testing.T) {
+	handler := NewHostStateHandler(&hostMock{})
+	tcases := []struct {
+		name        string
+		method      string
+		target      string
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json by default",
+			method:      http.MethodGet,
+			target:      HostStatePath,
+			code:        http.StatusOK,
+			contentType: "application/json",
+			body:        `"name": "node1"`,
+		},
+		{
+			name:        "yaml format query",
+			method:      http.MethodGet,
+			target:      HostStatePath + "?format=yaml",
+			code:        http.StatusOK,
+			contentType: "application/yaml",
+			body:        "- cpus:\n  - 2\n  - 3\n  exclusive: false\n  name: sharedPool\n",
+		},
+		{
+			name:        "yaml accept header",
+			method:      http.MethodGet,
+			target:      HostStatePath,
+			accept:      "application/yaml",
+			code:        http.StatusOK,
+			contentType: "application/yaml",
+			body:        "name: node1\n",
+		},
+		{
+			name:        "query takes precedence over accept header",
+			method:      http.MethodGet,
+			target:      HostStatePath + "?format=json",
+			accept:      "application/yaml",
+			code:        http.StatusOK,
+			contentType: "application/json",
+			body:        `"name": "node1"`,
+		},
+		{
+			name:   "method not allowed",
+			method: http.MethodPost,
+			target: HostStatePath,
+			code:   http.StatusMethodNotAllowed,
+			body:   "method not allowed",
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.contentType != "" {
+				assert.Equal(t, tc.contentType, rec.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, rec.Body.String(), tc.body)
+		})
+	}
+}
@@ -201,19 +201,23 @@ func (cpu *cpuImpl) updateCStates() error {
 	if !IsFeatureSupported(CStatesFeature) {
 		return nil
 	}
+	return cpu.applyCStates(cpu.overrideCStates(cpu.profileCStates()))
+}
 
-	// Get cstates config from profile
-	profile := cpu.pool.GetPowerProfile()
-	if profile != nil {
-		if maxLatencyUs := profile.GetCStates().GetMaxLatencyUs(); maxLatencyUs != nil {
-			return cpu.applyCStates(cpu.configCStatesByLatency(*maxLatencyUs))
-		}
-		if providedCStates := profile.GetCStates().States(); len(providedCStates) > 0 {
-			return cpu.applyCStates(cpu.configCStatesByNames(providedCStates))
+// profileCStates returns the C-states configured by the CPU's power profile, the defaults if it has none
+func (cpu *cpuImpl) profileCStates() CStates {
+	if cpu.pool != nil {
+		if profile := cpu.pool.GetPowerProfile(); profile != nil {
+			if maxLatencyUs := profile.GetCStates().GetMaxLatencyUs(); maxLatencyUs != nil {
+				return cpu.configCStatesByLatency(*maxLatencyUs)
+			}
+			if providedCStates := profile.GetCStates().States(); len(providedCStates) > 0 {
+				return cpu.configCStatesByNames(providedCStates)
+			}
 		}
 	}
 
-	return cpu.applyCStates(cstatesImpl{states: cpu.getDefaultCStatesStatus()})
+	return cstatesImpl{states: cpu.getDefaultCStatesStatus()}
 }
 
 func (cpu *cpuImpl) applyCStates(desiredCStates CStates) error {
@@ -224,18 +228,12 @@ func (cpu *cpuImpl) applyCStates(desiredCStates CStates) error {
 			continue
 		}
 
-		stateFilePath := filepath.Join(
-			basePath,
-			fmt.Sprint("cpu", cpu.id),
-			fmt.Sprintf(cStateDisableFileFmt, cstatesInfo[stateName].StateNumber),
-		)
-		content := make([]byte, 1)
-		if enabled {
-			content[0] = '0' // write '0' to enable the c state
-		} else {
-			content[0] = '1' // write '1' to disable the c state
+		stateFile := fmt.Sprintf(cStateDisableFileFmt, cstatesInfo[stateName].StateNumber)
+		content := "0" // write '0' to enable the c state
+		if !enabled {
+			content = "1" // write '1' to disable the c state
 		}
-		if err := os.WriteFile(stateFilePath, content, 0644); err != nil {
+		if err := cpu.writeCpuProperty(stateFile, content); err != nil {
 			return fmt.Errorf("could not apply cstate %s on cpu %d: %w", stateName, cpu.id, err)
 		}
 	}
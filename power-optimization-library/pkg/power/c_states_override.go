@@ -0,0 +1,78 @@
+package power
+
+import (
+	"maps"
+	"sync"
+)
+
+// cStatesOverride are C-states set by a control loop in place of the C-states of the CPU's power profile
+type cStatesOverride struct {
+	mutex  sync.Mutex
+	states map[string]bool
+}
+
+// SetCStatesOverride enables (true) or disables (false) the named C-states of the CPU in place of the C-states
+// of its power profile, including when the CPU moves between pools, until ClearCStatesOverride is called.
+// C-states which are not named keep the configuration of the profile
+func (cpu *cpuImpl) SetCStatesOverride(states map[string]bool) error {
+	if !IsFeatureSupported(CStatesFeature) {
+		return featureList.getFeatureIdError(CStatesFeature)
+	}
+	if len(states) == 0 {
+		return newError(ErrInvalidValue, "C-states override of cpu %d cannot be empty", cpu.id)
+	}
+	for stateName := range states {
+		if _, exists := allCPUCStatesInfo[cpu.id][stateName]; !exists {
+			return newError(ErrInvalidValue, "c-state %s does not exist for cpu %d", stateName, cpu.id)
+		}
+	}
+	cpu.mutex.Lock()
+	defer cpu.mutex.Unlock()
+	cpu.cStatesOverride.mutex.Lock()
+	defer cpu.cStatesOverride.mutex.Unlock()
+
+	// C-states of a previous override which are not named again return to the profile
+	desiredCStates := cpu.profileCStates()
+	maps.Copy(desiredCStates.States(), states)
+	if err := cpu.applyCStates(desiredCStates); err != nil {
+		return err
+	}
+	cpu.cStatesOverride.states = maps.Clone(states)
+	return nil
+}
+
+// ClearCStatesOverride removes the C-states override and restores the C-states of the CPU's power profile
+func (cpu *cpuImpl) ClearCStatesOverride() error {
+	if !IsFeatureSupported(CStatesFeature) {
+		return featureList.getFeatureIdError(CStatesFeature)
+	}
+	cpu.mutex.Lock()
+	defer cpu.mutex.Unlock()
+	cpu.cStatesOverride.mutex.Lock()
+	defer cpu.cStatesOverride.mutex.Unlock()
+
+	if cpu.cStatesOverride.states == nil {
+		return nil
+	}
+	if err := cpu.applyCStates(cpu.profileCStates()); err != nil {
+		return err
+	}
+	cpu.cStatesOverride.states = nil
+	return nil
+}
+
+// GetCStatesOverride returns the C-states override of the CPU, nil if there is none
+func (cpu *cpuImpl) GetCStatesOverride() map[string]bool {
+	cpu.cStatesOverride.mutex.Lock()
+	defer cpu.cStatesOverride.mutex.Unlock()
+	return maps.Clone(cpu.cStatesOverride.states)
+}
+
+// overrideCStates applies the C-states override of the CPU on top of the C-states of its power profile
+func (cpu *cpuImpl) overrideCStates(desiredCStates CStates) CStates {
+	cpu.cStatesOverride.mutex.Lock()
+	defer cpu.cStatesOverride.mutex.Unlock()
+
+	maps.Copy(desiredCStates.States(), cpu.cStatesOverride.states)
+	return desiredCStates
+}
@@ -0,0 +1,79 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCpuImpl_CStatesOverride(t *testing.T) {
+	defer setupCpuCStatesTests(map[string]map[string]map[string]string{
+		"cpu0": {
+			"state0": {"name": "C0", "disable": "0", "latency": "0"},
+			"state1": {"name": "C1", "disable": "0", "latency": "1"},
+			"state2": {"name": "C6", "disable": "1", "latency": "100"},
+		},
+	})()
+	allCPUCStatesInfo[0] = map[string]cstateInfo{
+		"C0": {StateNumber: 0, Latency: 0, DefaultStatus: true},
+		"C1": {StateNumber: 1, Latency: 1, DefaultStatus: true},
+		"C6": {StateNumber: 2, Latency: 100, DefaultStatus: true},
+	}
+	readDisabled := func(stateNumber int) string {
+		value, err := readCpuStringProperty(0, fmt.Sprintf(cStateDisableFileFmt, stateNumber))
+		assert.NoError(t, err)
+		return value
+	}
+
+	poolmk := new(poolMock)
+	profile := &profileImpl{cstates: cstatesImpl{maxLatencyUs: &[]int{1}[0]}}
+	poolmk.On("GetPowerProfile").Return(profile)
+	cpu := &cpuImpl{id: 0, mutex: &sync.Mutex{}, pool: poolmk, writeCache: newWriteCache()}
+	assert.NoError(t, cpu.updateCStates())
+	assert.Equal(t, "1", readDisabled(2))
+
+	assert.ErrorContains(t, cpu.SetCStatesOverride(nil), "cannot be empty")
+	assert.ErrorContains(t, cpu.SetCStatesOverride(map[string]bool{"C9": true}), "c-state C9 does not exist for cpu 0")
+	assert.Nil(t, cpu.GetCStatesOverride())
+
+	assert.NoError(t, cpu.SetCStatesOverride(map[string]bool{"C6": true}))
+	assert.Equal(t, "0", readDisabled(2))
+	assert.Equal(t, map[string]bool{"C6": true}, cpu.GetCStatesOverride())
+
+	// the override is kept when the profile is applied again
+	assert.NoError(t, cpu.updateCStates())
+	assert.Equal(t, "0", readDisabled(2))
+	assert.Equal(t, "0", readDisabled(1))
+
+	// C-states no longer overridden return to the profile
+	assert.NoError(t, cpu.SetCStatesOverride(map[string]bool{"C1": false}))
+	assert.Equal(t, "1", readDisabled(1))
+	assert.Equal(t, "1", readDisabled(2))
+
+	// clearing the override restores the C-states of the profile
+	assert.NoError(t, cpu.ClearCStatesOverride())
+	assert.Equal(t, "0", readDisabled(1))
+	assert.Nil(t, cpu.GetCStatesOverride())
+	assert.NoError(t, cpu.ClearCStatesOverride())
+
+	// without a profile the default C-states are restored
+	cpu.pool = nil
+	assert.NoError(t, cpu.SetCStatesOverride(map[string]bool{"C1": false}))
+	assert.NoError(t, cpu.ClearCStatesOverride())
+	assert.Equal(t, "0", readDisabled(1))
+	assert.Equal(t, "0", readDisabled(2))
+
+	// failed write does not register the override
+	assert.NoError(t, os.RemoveAll(filepath.Join(basePath, "cpu0", cStatesDir, "state1")))
+	assert.ErrorContains(t, cpu.SetCStatesOverride(map[string]bool{"C1": false}), "could not apply cstate C1 on cpu 0")
+	assert.Nil(t, cpu.GetCStatesOverride())
+
+	// feature not supported
+	featureList[CStatesFeature].err = fmt.Errorf("not supported")
+	assert.ErrorContains(t, cpu.SetCStatesOverride(map[string]bool{"C6": true}), "not supported")
+	assert.ErrorContains(t, cpu.ClearCStatesOverride(), "not supported")
+}
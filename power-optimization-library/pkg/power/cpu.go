@@ -19,6 +19,7 @@ type Cpu interface {
 	SetPool(pool Pool) error
 
 	getPool() Pool
+	moveToPool(pool Pool) (Pool, error)
 	doSetPool(pool Pool) error
 	consolidate() error
 	consolidate_unsafe() error
@@ -26,16 +27,46 @@ type Cpu interface {
 
 	SetCPUFrequency(frequency uint) error
 	GetCurrentCPUFrequency() (uint, error)
+	GetAvailableFrequencies() []uint
+	SnapFrequency(frequency uint) uint
+	GetEffectiveCPUFrequency() (uint, error)
+
+	SetFrequencyConstraint(requester FrequencyRequester, constraint FrequencyConstraint) error
+	ClearFrequencyConstraint(requester FrequencyRequester) error
+	GetFrequencyConstraints() map[FrequencyRequester]FrequencyConstraint
+	GetEffectiveFrequencyLimits() (uint, uint)
+	GetFrequencyLimitsExcluding(requester FrequencyRequester) (uint, uint)
+
+	SetEPPOverride(epp string) error
+	ClearEPPOverride() error
+	GetEPPOverride() string
+
+	SetCStatesOverride(states map[string]bool) error
+	ClearCStatesOverride() error
+	GetCStatesOverride() map[string]bool
+
+	readAperfMperf() (aperfMperfSample, error)
 
 	// used only to set initial pool when creating core instance
 	_setPoolProperty(pool Pool)
 }
 
 type cpuImpl struct {
-	id    uint
-	mutex sync.Locker
-	pool  Pool
-	core  Core
+	id         uint
+	mutex      sync.Locker
+	pool       Pool
+	core       Core
+	writeCache *writeCache
+	// frequency limits requested by the different control loops
+	freqConstraints freqConstraints
+	// EPP set by a control loop in place of the profile's
+	eppOverride eppOverride
+	// C-states set by a control loop in place of the profile's
+	cStatesOverride cStatesOverride
+	// previous APERF/MPERF reading used to compute the delivered frequency
+	effectiveFreq effectiveFreqState
+	// governor tunables overridden by the library, path -> key in sharedGovernorTunables
+	governorTunables map[string]string
 }
 
 func newCpu(coreID uint, core Core) (Cpu, error) {
@@ -48,9 +79,10 @@ func newCpu(coreID uint, core Core) (Cpu, error) {
 		core.setType(cType)
 	}
 	cpu := &cpuImpl{
-		id:    coreID,
-		mutex: &sync.Mutex{},
-		core:  core,
+		id:         coreID,
+		mutex:      &sync.Mutex{},
+		core:       core,
+		writeCache: newWriteCache(),
 	}
 
 	return cpu, nil
@@ -76,6 +108,16 @@ func (cpu *cpuImpl) consolidate_unsafe() error {
 // SetPool moves current core to a specified target pool
 // allowed movements are reservedPoolType <-> sharedPoolType and sharedPoolType <-> any exclusive pool
 func (cpu *cpuImpl) SetPool(targetPool Pool) error {
+	origPool, err := cpu.moveToPool(targetPool)
+	if err != nil || origPool == nil {
+		return err
+	}
+	return syncIdleInjection(origPool, targetPool)
+}
+
+// moveToPool moves the cpu like SetPool but leaves idle injection to the caller, returning the pool
+// the cpu was moved from, nil if it was in the target pool already
+func (cpu *cpuImpl) moveToPool(targetPool Pool) (Pool, error) {
 	/*
 		case 0: current and target pool are the same -> do nothing
 
@@ -93,7 +135,7 @@ func (cpu *cpuImpl) SetPool(targetPool Pool) error {
 
 	*/
 	if targetPool == nil {
-		return fmt.Errorf("target pool cannot be nil")
+		return nil, fmt.Errorf("target pool cannot be nil")
 	}
 
 	log.Info("Set pool", "cpu", cpu.id, "source pool", cpu.pool.Name(), "target pool", targetPool.Name())
@@ -101,32 +143,40 @@ func (cpu *cpuImpl) SetPool(targetPool Pool) error {
 	defer cpu.mutex.Unlock()
 
 	if cpu.pool == targetPool { // case 0,1,5
-		return nil
+		return nil, nil
 	}
 	reservedPool := cpu.pool.getHost().GetReservedPool()
 	sharedPool := cpu.pool.getHost().GetSharedPool()
 	if cpu.pool == reservedPool && targetPool.isExclusive() { // case 3
-		return fmt.Errorf("cannot move from reserved to exclusive pool")
+		return nil, fmt.Errorf("cannot move from reserved to exclusive pool")
 	}
 
 	if cpu.pool.isExclusive() && targetPool.isExclusive() { // case 7
-		return fmt.Errorf("cannot move exclusive to different exclusive pool")
+		return nil, fmt.Errorf("cannot move exclusive to different exclusive pool")
 	}
 
 	if cpu.pool.isExclusive() && targetPool == reservedPool { // case 9
-		return fmt.Errorf("cannot move from exclusive to reserved")
+		return nil, fmt.Errorf("cannot move from exclusive to reserved")
 	}
 
 	// cases 2,4,5,6,8
 	if targetPool == sharedPool || cpu.pool == sharedPool {
-		return cpu.doSetPool(targetPool)
+		origPool := cpu.pool
+		if err := cpu.doSetPool(targetPool); err != nil {
+			return nil, err
+		}
+		return origPool, nil
 	}
 	panic("we should never get here")
 }
 
 func (cpu *cpuImpl) doSetPool(pool Pool) error {
-	cpu.pool.poolMutex().Lock()
-	pool.poolMutex().Lock()
+	first, second := cpu.pool, pool
+	if poolLockRank(second) < poolLockRank(first) {
+		first, second = second, first
+	}
+	first.poolMutex().Lock()
+	second.poolMutex().Lock()
 	log.V(4).Info("acquired mutexes", "source", cpu.pool.Name(), "target", pool.Name(), "cpu", cpu.id)
 
 	origPool := cpu.pool
@@ -51,6 +51,12 @@ func (m *cpuMock) SetPool(pool Pool) error {
 	return m.Called(pool).Error(0)
 }
 
+func (m *cpuMock) moveToPool(pool Pool) (Pool, error) {
+	args := m.Called(pool)
+	origPool, _ := args.Get(0).(Pool)
+	return origPool, args.Error(1)
+}
+
 func (m *cpuMock) SetCPUFrequency(frequency uint) error {
 	return m.Called(frequency).Error(0)
 }
@@ -60,6 +66,78 @@ func (m *cpuMock) GetCurrentCPUFrequency() (uint, error) {
 	return args.Get(0).(uint), args.Error(1)
 }
 
+func (m *cpuMock) GetAvailableFrequencies() []uint {
+	args := m.Called().Get(0)
+	if args == nil {
+		return nil
+	}
+	return args.([]uint)
+}
+
+func (m *cpuMock) SnapFrequency(frequency uint) uint {
+	return m.Called(frequency).Get(0).(uint)
+}
+
+func (m *cpuMock) GetEffectiveCPUFrequency() (uint, error) {
+	args := m.Called()
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *cpuMock) readAperfMperf() (aperfMperfSample, error) {
+	args := m.Called()
+	return args.Get(0).(aperfMperfSample), args.Error(1)
+}
+
+func (m *cpuMock) SetFrequencyConstraint(requester FrequencyRequester, constraint FrequencyConstraint) error {
+	return m.Called(requester, constraint).Error(0)
+}
+
+func (m *cpuMock) ClearFrequencyConstraint(requester FrequencyRequester) error {
+	return m.Called(requester).Error(0)
+}
+
+func (m *cpuMock) GetFrequencyConstraints() map[FrequencyRequester]FrequencyConstraint {
+	return m.Called().Get(0).(map[FrequencyRequester]FrequencyConstraint)
+}
+
+func (m *cpuMock) GetEffectiveFrequencyLimits() (uint, uint) {
+	args := m.Called()
+	return args.Get(0).(uint), args.Get(1).(uint)
+}
+
+func (m *cpuMock) GetFrequencyLimitsExcluding(requester FrequencyRequester) (uint, uint) {
+	args := m.Called(requester)
+	return args.Get(0).(uint), args.Get(1).(uint)
+}
+
+func (m *cpuMock) SetEPPOverride(epp string) error {
+	return m.Called(epp).Error(0)
+}
+
+func (m *cpuMock) ClearEPPOverride() error {
+	return m.Called().Error(0)
+}
+
+func (m *cpuMock) GetEPPOverride() string {
+	return m.Called().String(0)
+}
+
+func (m *cpuMock) SetCStatesOverride(states map[string]bool) error {
+	return m.Called(states).Error(0)
+}
+
+func (m *cpuMock) ClearCStatesOverride() error {
+	return m.Called().Error(0)
+}
+
+func (m *cpuMock) GetCStatesOverride() map[string]bool {
+	args := m.Called().Get(0)
+	if args == nil {
+		return nil
+	}
+	return args.(map[string]bool)
+}
+
 type mutexMock struct {
 	mock.Mock
 }
@@ -88,8 +166,10 @@ func TestNewCore(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.NotNil(t, cpu.(*cpuImpl).mutex)
-	// we don't want to compare value of new mutex, so we set it to nil
+	assert.NotNil(t, cpu.(*cpuImpl).writeCache)
+	// we don't want to compare value of new mutex and write cache, so we set them to nil
 	cpu.(*cpuImpl).mutex = nil
+	cpu.(*cpuImpl).writeCache = nil
 	assert.Equal(t, &cpuImpl{
 		id:   0,
 		core: core,
@@ -104,6 +184,7 @@ func TestNewCore(t *testing.T) {
 	assert.NotNil(t, cpu.(*cpuImpl).mutex)
 	// Ensure P-States stuff was never read by ensuring related properties are 0
 	cpu.(*cpuImpl).mutex = nil
+	cpu.(*cpuImpl).writeCache = nil
 	assert.Equal(t, &cpuImpl{
 		id: 0,
 	}, cpu)
@@ -314,6 +395,13 @@ func TestCpuImpl_doSetPool(t *testing.T) {
 	)
 	targetPool.On("poolMutex").Return(targetPoolMutex)
 
+	// moving from the shared to an exclusive pool
+	host := new(hostMock)
+	host.On("GetSharedPool").Return(sourcePool)
+	sourcePool.On("isExclusive").Return(false)
+	sourcePool.On("getHost").Return(host)
+	targetPool.On("isExclusive").Return(true)
+
 	cpu = &cpuImpl{
 		pool: sourcePool,
 	}
@@ -341,6 +429,13 @@ func TestCpuImpl_doSetPool(t *testing.T) {
 	)
 	targetPool.On("poolMutex").Return(targetPoolMutex)
 
+	// moving from an exclusive to the shared pool
+	host = new(hostMock)
+	host.On("GetSharedPool").Return(targetPool)
+	sourcePool.On("isExclusive").Return(true)
+	targetPool.On("isExclusive").Return(false)
+	targetPool.On("getHost").Return(host)
+
 	cpu = &cpuImpl{
 		pool: sourcePool,
 	}
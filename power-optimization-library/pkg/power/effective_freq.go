@@ -0,0 +1,211 @@
+package power
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	msrDevicePathFmt = "/dev/cpu/%d/msr"
+	// IA32_MPERF counts at a fixed (base) frequency while the CPU is in C0
+	msrMperf uint32 = 0xE7
+	// IA32_APERF counts at the actual delivered frequency while the CPU is in C0
+	msrAperf uint32 = 0xE8
+
+	cpuBaseFreqFile = "cpufreq/base_frequency"
+)
+
+var (
+	// ErrEffectiveFreqNoProgress is returned when the MPERF counter did not advance between
+	// two samples, which happens if the CPU spent the whole interval idle
+	ErrEffectiveFreqNoProgress = errors.New("mperf counter did not advance since last sample")
+
+	// effectiveFreqSampleWindow is the measurement window used when no previous sample exists
+	effectiveFreqSampleWindow = 10 * time.Millisecond
+
+	msrReader MSRReader = &devMSRReader{pathFmt: msrDevicePathFmt}
+)
+
+// MSRReader reads model specific registers of a CPU
+type MSRReader interface {
+	ReadMSR(cpuID uint, register uint32) (uint64, error)
+}
+
+// devMSRReader reads MSRs through the msr kernel module character devices
+type devMSRReader struct {
+	pathFmt string
+}
+
+func (r *devMSRReader) ReadMSR(cpuID uint, register uint32) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf(r.pathFmt, cpuID))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	buf := make([]byte, 8)
+	if _, err := f.ReadAt(buf, int64(register)); err != nil {
+		return 0, fmt.Errorf("failed to read msr %#x of cpu %d: %w", register, cpuID, err)
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+// SetMSRReader replaces the reader used to access APERF/MPERF, e.g. with a fake in tests
+func SetMSRReader(reader MSRReader) {
+	msrReader = reader
+}
+
+func initEffectiveFrequency() featureStatus {
+	feature := featureStatus{
+		name:     "Effective-Frequency",
+		driver:   "msr",
+		initFunc: initEffectiveFrequency,
+	}
+	if _, err := msrReader.ReadMSR(0, msrAperf); err != nil {
+		feature.err = fmt.Errorf("failed to read APERF: %w", err)
+	}
+	return feature
+}
+
+// aperfMperfSample is a reading of the counters of a CPU along with the frequency MPERF counts at
+type aperfMperfSample struct {
+	aperf    uint64
+	mperf    uint64
+	baseFreq uint
+}
+
+// effectiveFreqState keeps the previous reading of a CPU for GetEffectiveCPUFrequency
+type effectiveFreqState struct {
+	mutex      sync.Mutex
+	lastSample *aperfMperfSample
+}
+
+// effectiveFreqBaselines keeps the previous readings of the CPUs of a pool for GetEffectiveFrequency,
+// separate from the ones of the CPUs so neither reader resets the interval of the other
+type effectiveFreqBaselines struct {
+	mutex   sync.Mutex
+	samples map[uint]aperfMperfSample // cpu id -> sample
+}
+
+func (cpu *cpuImpl) readAperfMperf() (aperfMperfSample, error) {
+	baseFreq, err := cpu.readBaseFrequency()
+	if err != nil {
+		return aperfMperfSample{}, fmt.Errorf("failed to read base frequency of cpu %d: %w", cpu.id, err)
+	}
+	// MPERF is read first so a (short) preemption between the reads can only
+	// overestimate the time base and never report a frequency above the real one
+	mperf, err := msrReader.ReadMSR(cpu.id, msrMperf)
+	if err != nil {
+		return aperfMperfSample{}, err
+	}
+	aperf, err := msrReader.ReadMSR(cpu.id, msrAperf)
+	if err != nil {
+		return aperfMperfSample{}, err
+	}
+	return aperfMperfSample{aperf: aperf, mperf: mperf, baseFreq: baseFreq}, nil
+}
+
+// readBaseFrequency returns the frequency MPERF is counting at, base_frequency is exposed
+// by intel_pstate, other drivers report the non-turbo frequency as cpuinfo_max_freq
+func (cpu *cpuImpl) readBaseFrequency() (uint, error) {
+	baseFreq, err := readCpuUintProperty(cpu.id, cpuBaseFreqFile)
+	if err == nil {
+		return baseFreq, nil
+	}
+	return readCpuUintProperty(cpu.id, cpuMaxFreqFile)
+}
+
+// effectiveFrequencySince computes the frequency delivered between two samples of a CPU
+func effectiveFrequencySince(prev aperfMperfSample, sample aperfMperfSample) (uint, error) {
+	// counters are free running 64 bit values, unsigned subtraction handles wrap around
+	deltaAperf := sample.aperf - prev.aperf
+	deltaMperf := sample.mperf - prev.mperf
+	if deltaMperf == 0 {
+		return 0, ErrEffectiveFreqNoProgress
+	}
+	return uint(float64(sample.baseFreq) * float64(deltaAperf) / float64(deltaMperf)), nil
+}
+
+// GetEffectiveCPUFrequency returns the average frequency in kHz the CPU delivered while not idle
+// since the previous call for this CPU, pool reads do not reset the interval. On the first call
+// the frequency is measured over a short window
+func (cpu *cpuImpl) GetEffectiveCPUFrequency() (uint, error) {
+	if !IsFeatureSupported(EffectiveFrequencyFeature) {
+		return 0, featureList.getFeatureIdError(EffectiveFrequencyFeature)
+	}
+	cpu.effectiveFreq.mutex.Lock()
+	defer cpu.effectiveFreq.mutex.Unlock()
+	prev := cpu.effectiveFreq.lastSample
+	if prev == nil {
+		baseline, err := cpu.readAperfMperf()
+		if err != nil {
+			return 0, err
+		}
+		prev = &baseline
+		time.Sleep(effectiveFreqSampleWindow)
+	}
+	sample, err := cpu.readAperfMperf()
+	if err != nil {
+		return 0, err
+	}
+	cpu.effectiveFreq.lastSample = &sample
+	return effectiveFrequencySince(*prev, sample)
+}
+
+// GetEffectiveFrequency returns the average effective frequency in kHz of all CPUs in the pool
+// since the previous call for this pool, per CPU reads do not reset the interval. CPUs that were
+// idle for the whole interval are not accounted for
+func (pool *poolImpl) GetEffectiveFrequency() (uint, error) {
+	if !IsFeatureSupported(EffectiveFrequencyFeature) {
+		return 0, featureList.getFeatureIdError(EffectiveFrequencyFeature)
+	}
+	pool.mutex.Lock()
+	cpus := make(CpuList, len(pool.cpus))
+	copy(cpus, pool.cpus)
+	pool.mutex.Unlock()
+
+	pool.effectiveFreq.mutex.Lock()
+	defer pool.effectiveFreq.mutex.Unlock()
+	// take baselines for cpus that joined since the previous call first, so only one measurement window is needed
+	baselines := make(map[uint]aperfMperfSample, len(cpus))
+	missing := false
+	for _, cpu := range cpus {
+		baseline, exists := pool.effectiveFreq.samples[cpu.GetID()]
+		if !exists {
+			var err error
+			if baseline, err = cpu.readAperfMperf(); err != nil {
+				return 0, err
+			}
+			missing = true
+		}
+		baselines[cpu.GetID()] = baseline
+	}
+	if missing {
+		time.Sleep(effectiveFreqSampleWindow)
+	}
+
+	// cpus that left the pool are dropped from the samples
+	samples := make(map[uint]aperfMperfSample, len(cpus))
+	var sum, count uint
+	for _, cpu := range cpus {
+		sample, err := cpu.readAperfMperf()
+		if err != nil {
+			return 0, err
+		}
+		samples[cpu.GetID()] = sample
+		freq, err := effectiveFrequencySince(baselines[cpu.GetID()], sample)
+		if errors.Is(err, ErrEffectiveFreqNoProgress) {
+			continue
+		}
+		sum += freq
+		count++
+	}
+	pool.effectiveFreq.samples = samples
+	if count == 0 {
+		return 0, ErrEffectiveFreqNoProgress
+	}
+	return sum / count, nil
+}
@@ -0,0 +1,191 @@
+package power
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMSRReader returns consecutive values from a per cpu, per register sequence
+type fakeMSRReader struct {
+	mutex  sync.Mutex
+	values map[uint]map[uint32][]uint64
+	err    error
+}
+
+func (r *fakeMSRReader) ReadMSR(cpuID uint, register uint32) (uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	seq := r.values[cpuID][register]
+	if len(seq) == 0 {
+		return 0, fmt.Errorf("no value for cpu %d msr %#x", cpuID, register)
+	}
+	r.values[cpuID][register] = seq[1:]
+	return seq[0], nil
+}
+
+func setupEffectiveFreqTests(t *testing.T, reader MSRReader, cpufiles map[string]map[string]string) func() {
+	teardown := setupCpuScalingTests(cpufiles)
+	origReader := msrReader
+	msrReader = reader
+	origWindow := effectiveFreqSampleWindow
+	effectiveFreqSampleWindow = 0
+	featureList[EffectiveFrequencyFeature].err = nil
+	return func() {
+		teardown()
+		msrReader = origReader
+		effectiveFreqSampleWindow = origWindow
+		featureList[EffectiveFrequencyFeature].err = uninitialisedErr
+	}
+}
+
+func TestDevMSRReader(t *testing.T) {
+	dir := t.TempDir()
+	content := make([]byte, int(msrAperf)+8)
+	binary.LittleEndian.PutUint64(content[msrAperf:], 123456789)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "0"), content, 0644))
+
+	reader := &devMSRReader{pathFmt: filepath.Join(dir, "%d")}
+	value, err := reader.ReadMSR(0, msrAperf)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(123456789), value)
+
+	_, err = reader.ReadMSR(1, msrAperf)
+	assert.Error(t, err)
+
+	// register beyond end of file
+	_, err = reader.ReadMSR(0, msrAperf+8)
+	assert.ErrorContains(t, err, "failed to read msr")
+}
+
+func TestInitEffectiveFrequency(t *testing.T) {
+	origReader := msrReader
+	defer func() { msrReader = origReader }()
+
+	msrReader = &fakeMSRReader{values: map[uint]map[uint32][]uint64{0: {msrAperf: {1}}}}
+	feature := initEffectiveFrequency()
+	assert.NoError(t, feature.err)
+	assert.Equal(t, "msr", feature.driver)
+
+	msrReader = &fakeMSRReader{err: fmt.Errorf("permission denied")}
+	assert.ErrorContains(t, initEffectiveFrequency().err, "permission denied")
+}
+
+func TestCpuImpl_GetEffectiveCPUFrequency(t *testing.T) {
+	reader := &fakeMSRReader{values: map[uint]map[uint32][]uint64{
+		0: {
+			msrMperf: {1000, 2000, 3000, 3000},
+			msrAperf: {5000, 6500, 7000, 7000},
+		},
+	}}
+	defer setupEffectiveFreqTests(t, reader, map[string]map[string]string{
+		"cpu0": {"max": "2000000", "min": "800000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+
+	// first call measures over the sample window, base frequency falls back to cpuinfo_max_freq
+	freq, err := cpu.GetEffectiveCPUFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3000000), freq)
+
+	// following calls use the previous sample as baseline
+	freq, err = cpu.GetEffectiveCPUFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1000000), freq)
+
+	// cpu idle for the whole interval
+	_, err = cpu.GetEffectiveCPUFrequency()
+	assert.ErrorIs(t, err, ErrEffectiveFreqNoProgress)
+
+	// read failure
+	reader.err = fmt.Errorf("read failed")
+	_, err = cpu.GetEffectiveCPUFrequency()
+	assert.ErrorContains(t, err, "read failed")
+
+	featureList[EffectiveFrequencyFeature].err = fmt.Errorf("not supported")
+	_, err = cpu.GetEffectiveCPUFrequency()
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestCpuImpl_GetEffectiveCPUFrequency_baseFrequency(t *testing.T) {
+	reader := &fakeMSRReader{values: map[uint]map[uint32][]uint64{
+		0: {
+			msrMperf: {0, 100},
+			msrAperf: {^uint64(0) - 49, 50},
+		},
+	}}
+	defer setupEffectiveFreqTests(t, reader, map[string]map[string]string{
+		"cpu0": {"max": "3700000", "min": "800000"},
+	})()
+	assert.NoError(t, os.WriteFile(filepath.Join(basePath, "cpu0", cpuBaseFreqFile), []byte("2000000\n"), 0644))
+	cpu := &cpuImpl{id: 0}
+
+	// base_frequency takes precedence over cpuinfo_max_freq, counter wrap around is handled
+	freq, err := cpu.GetEffectiveCPUFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2000000), freq)
+}
+
+func TestPoolImpl_GetEffectiveFrequency(t *testing.T) {
+	reader := &fakeMSRReader{values: map[uint]map[uint32][]uint64{
+		0: {msrMperf: {0, 100}, msrAperf: {0, 100}},
+		1: {msrMperf: {0, 100}, msrAperf: {0, 200}},
+		2: {msrMperf: {0, 0}, msrAperf: {0, 0}},
+	}}
+	defer setupEffectiveFreqTests(t, reader, map[string]map[string]string{
+		"cpu0": {"max": "1000000", "min": "800000"},
+		"cpu1": {"max": "1000000", "min": "800000"},
+		"cpu2": {"max": "1000000", "min": "800000"},
+	})()
+	pool := &poolImpl{mutex: &sync.Mutex{}, cpus: CpuList{&cpuImpl{id: 0}, &cpuImpl{id: 1}, &cpuImpl{id: 2}}}
+
+	// idle cpu2 is not accounted for
+	freq, err := pool.GetEffectiveFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1500000), freq)
+
+	// only idle cpus, the new pool takes its own baseline
+	idlePool := &poolImpl{mutex: &sync.Mutex{}, cpus: CpuList{pool.cpus[2]}}
+	reader.values[2] = map[uint32][]uint64{msrMperf: {0, 0}, msrAperf: {0, 0}}
+	_, err = idlePool.GetEffectiveFrequency()
+	assert.ErrorIs(t, err, ErrEffectiveFreqNoProgress)
+
+	featureList[EffectiveFrequencyFeature].err = fmt.Errorf("not supported")
+	_, err = pool.GetEffectiveFrequency()
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestEffectiveFrequency_independentReaders(t *testing.T) {
+	reader := &fakeMSRReader{values: map[uint]map[uint32][]uint64{
+		0: {
+			msrMperf: {0, 100, 200, 300, 400},
+			msrAperf: {0, 100, 300, 600, 1000},
+		},
+	}}
+	defer setupEffectiveFreqTests(t, reader, map[string]map[string]string{
+		"cpu0": {"max": "1000000", "min": "800000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+	pool := &poolImpl{mutex: &sync.Mutex{}, cpus: CpuList{cpu}}
+
+	// pool baseline at mperf 0, sampled at 100
+	freq, err := pool.GetEffectiveFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1000000), freq)
+	// cpu baseline at mperf 200, sampled at 300
+	freq, err = cpu.GetEffectiveCPUFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3000000), freq)
+	// the pool interval still starts at its own previous sample
+	freq, err = pool.GetEffectiveFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3000000), freq)
+}
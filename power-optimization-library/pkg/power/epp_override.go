@@ -0,0 +1,81 @@
+package power
+
+import (
+	"fmt"
+	"sync"
+)
+
+// eppOverride is an EPP set by a control loop in place of the EPP of the CPU's power profile
+type eppOverride struct {
+	mutex sync.Mutex
+	value string
+}
+
+// SetEPPOverride writes epp to the CPU and keeps it in place of the EPP of the CPU's power profile,
+// including when the CPU moves between pools, until ClearEPPOverride is called
+func (cpu *cpuImpl) SetEPPOverride(epp string) error {
+	if !IsFeatureSupported(EPPFeature) {
+		return featureList.getFeatureIdError(EPPFeature)
+	}
+	if epp == "" {
+		return newError(ErrInvalidValue, "EPP override of cpu %d cannot be empty", cpu.id)
+	}
+	cpu.eppOverride.mutex.Lock()
+	defer cpu.eppOverride.mutex.Unlock()
+
+	if err := cpu.writeEppValue(epp); err != nil {
+		return fmt.Errorf("failed to set EPP value for cpu %d: %w", cpu.id, err)
+	}
+	cpu.eppOverride.value = epp
+	return nil
+}
+
+// ClearEPPOverride removes the EPP override and restores the EPP of the CPU's power profile
+func (cpu *cpuImpl) ClearEPPOverride() error {
+	if !IsFeatureSupported(EPPFeature) {
+		return featureList.getFeatureIdError(EPPFeature)
+	}
+	cpu.mutex.Lock()
+	defer cpu.mutex.Unlock()
+	cpu.eppOverride.mutex.Lock()
+	defer cpu.eppOverride.mutex.Unlock()
+
+	if cpu.eppOverride.value == "" {
+		return nil
+	}
+	cpu.eppOverride.value = ""
+	epp := allCPUDefaultPStatesInfo[cpu.id].GetEpp()
+	if cpu.pool != nil {
+		if profile := cpu.pool.GetPowerProfile(); profile != nil {
+			epp = profile.GetPStates().GetEpp()
+		}
+	}
+	if epp == "" {
+		return nil
+	}
+	if err := cpu.writeEppValue(epp); err != nil {
+		return fmt.Errorf("failed to set EPP value for cpu %d: %w", cpu.id, err)
+	}
+	return nil
+}
+
+// GetEPPOverride returns the EPP override of the CPU, empty if there is none
+func (cpu *cpuImpl) GetEPPOverride() string {
+	cpu.eppOverride.mutex.Lock()
+	defer cpu.eppOverride.mutex.Unlock()
+	return cpu.eppOverride.value
+}
+
+// writeProfileEpp writes the EPP of the CPU's power profile unless it is overridden
+func (cpu *cpuImpl) writeProfileEpp(epp string) error {
+	cpu.eppOverride.mutex.Lock()
+	defer cpu.eppOverride.mutex.Unlock()
+
+	if cpu.eppOverride.value != "" {
+		epp = cpu.eppOverride.value
+	}
+	if epp == "" {
+		return nil
+	}
+	return cpu.writeEppValue(epp)
+}
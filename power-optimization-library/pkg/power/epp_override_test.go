@@ -0,0 +1,69 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCpuImpl_EPPOverride(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "3000", "min": "1000", "epp": "power"},
+	})()
+	featureList[EPPFeature].err = nil
+	defer func() { featureList[EPPFeature].err = uninitialisedErr }()
+	typeCopy := coreTypes
+	coreTypes = CoreTypeList{&CpuFrequencySet{min: 1000, max: 3000}}
+	defer func() { coreTypes = typeCopy }()
+	readEpp := func() string {
+		epp, err := readCpuStringProperty(0, eppFile)
+		assert.NoError(t, err)
+		return epp
+	}
+
+	poolmk := new(poolMock)
+	profile := &profileImpl{pstates: &pstatesImpl{
+		minFreq:  intstr.FromInt(1000),
+		maxFreq:  intstr.FromInt(3000),
+		epp:      "balance_power",
+		governor: cpuPolicyPowersave,
+	}}
+	poolmk.On("GetPowerProfile").Return(profile)
+	cpu := &cpuImpl{id: 0, mutex: &sync.Mutex{}, pool: poolmk}
+
+	assert.ErrorContains(t, cpu.SetEPPOverride(""), "cannot be empty")
+	assert.NoError(t, cpu.SetEPPOverride("performance"))
+	assert.Equal(t, "performance", readEpp())
+	assert.Equal(t, "performance", cpu.GetEPPOverride())
+
+	// the override is kept when the profile is applied again
+	assert.NoError(t, cpu.setDriverValues(profile.GetPStates()))
+	assert.Equal(t, "performance", readEpp())
+
+	// clearing the override restores the EPP of the profile
+	assert.NoError(t, cpu.ClearEPPOverride())
+	assert.Equal(t, "balance_power", readEpp())
+	assert.Empty(t, cpu.GetEPPOverride())
+	assert.NoError(t, cpu.ClearEPPOverride())
+
+	// without a profile the default EPP is restored
+	cpu.pool = nil
+	assert.NoError(t, cpu.SetEPPOverride("balance_performance"))
+	assert.NoError(t, cpu.ClearEPPOverride())
+	assert.Equal(t, defaultEpp, readEpp())
+
+	// failed write does not register the override
+	assert.NoError(t, os.RemoveAll(filepath.Join(basePath, "cpu0", "cpufreq")))
+	assert.ErrorContains(t, cpu.SetEPPOverride("performance"), "failed to set EPP value for cpu 0")
+	assert.Empty(t, cpu.GetEPPOverride())
+
+	// feature not supported
+	featureList[EPPFeature].err = fmt.Errorf("not supported")
+	assert.ErrorContains(t, cpu.SetEPPOverride("performance"), "not supported")
+	assert.ErrorContains(t, cpu.ClearEPPOverride(), "not supported")
+}
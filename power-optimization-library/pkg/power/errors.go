@@ -0,0 +1,62 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+// Sentinel errors classifying library failures, test for them with errors.Is
+var (
+	// ErrUnsupported is returned when the feature or value is not supported by the host
+	ErrUnsupported = errors.New("unsupported")
+	// ErrInvalidValue is returned for malformed or inconsistent requests
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrOutOfRange is returned when a value is outside the range allowed by the hardware
+	ErrOutOfRange = errors.New("out of range")
+	// ErrWriteFailed is returned when writing to sysfs failed
+	ErrWriteFailed = errors.New("write failed")
+	// ErrBusy is returned when the kernel rejected a write because the resource is in use
+	ErrBusy = errors.New("busy")
+	// ErrIdleInjection is returned when CPUs were moved between pools but idle injection was not updated
+	// to the new pool membership
+	ErrIdleInjection = errors.New("idle injection not updated")
+)
+
+// Error classifies Err with Kind, usually one of the sentinel errors, without changing its message
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the Kind of the error, the wrapped error is matched through Unwrap
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func newError(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// writeError classifies a failed sysfs write, a missing attribute means the host does not support it
+func writeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Error{Kind: ErrUnsupported, Err: err}
+	}
+	if errors.Is(err, syscall.EBUSY) {
+		return &Error{Kind: ErrBusy, Err: err}
+	}
+	return &Error{Kind: ErrWriteFailed, Err: err}
+}
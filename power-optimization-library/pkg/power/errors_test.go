@@ -0,0 +1,56 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	err := newError(ErrOutOfRange, "value %d too high", 10)
+	assert.EqualError(t, err, "value 10 too high")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.NotErrorIs(t, err, ErrInvalidValue)
+
+	// classification survives further wrapping
+	wrapped := fmt.Errorf("failed to apply: %w", err)
+	assert.ErrorIs(t, wrapped, ErrOutOfRange)
+	var powerErr *Error
+	assert.True(t, errors.As(wrapped, &powerErr))
+	assert.Equal(t, ErrOutOfRange, powerErr.Kind)
+}
+
+func TestWriteError(t *testing.T) {
+	assert.NoError(t, writeError(nil))
+
+	busy := &os.PathError{Op: "write", Path: "/sys/foo", Err: syscall.EBUSY}
+	assert.ErrorIs(t, writeError(busy), ErrBusy)
+	assert.ErrorIs(t, writeError(busy), syscall.EBUSY)
+	assert.Equal(t, busy.Error(), writeError(busy).Error())
+
+	missing := &os.PathError{Op: "open", Path: "/sys/foo", Err: fs.ErrNotExist}
+	assert.ErrorIs(t, writeError(missing), ErrUnsupported)
+
+	denied := &os.PathError{Op: "open", Path: "/sys/foo", Err: fs.ErrPermission}
+	assert.ErrorIs(t, writeError(denied), ErrWriteFailed)
+	assert.NotErrorIs(t, writeError(denied), ErrBusy)
+}
+
+func TestFeatureSet_getFeatureIdErrorUnsupported(t *testing.T) {
+	featureErr := fmt.Errorf("EPP file does not exist")
+	set := FeatureSet{EPPFeature: &featureStatus{err: featureErr}}
+
+	err := set.getFeatureIdError(EPPFeature)
+	assert.EqualError(t, err, featureErr.Error())
+	assert.ErrorIs(t, err, featureErr)
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorIs(t, set.getFeatureIdError(CStatesFeature), ErrUnsupported)
+
+	set[EPPFeature].err = nil
+	assert.NoError(t, set.getFeatureIdError(EPPFeature))
+}
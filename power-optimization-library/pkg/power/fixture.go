@@ -0,0 +1,307 @@
+package power
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A fixture is a gzipped tarball holding the part of a host's sysfs and procfs the library reads. It is laid out as
+//
+//	cpu/          subset of /sys/devices/system/cpu
+//	modules       copy of /proc/modules
+//	thermal/      the intel_powerclamp cooling device of /sys/class/thermal
+//	powerclamp/   /sys/module/intel_powerclamp/parameters
+//	powercap/     subset of /sys/class/powercap
+//	lscpu         the lscpu keys used by the library
+const (
+	fixtureCpuDir        = "cpu"
+	fixtureModulesFile   = "modules"
+	fixtureThermalDir    = "thermal"
+	fixturePowerclampDir = "powerclamp"
+	fixturePowercapDir   = "powercap"
+	fixtureLscpuFile     = "lscpu"
+)
+
+var powercapBasePath = "/sys/class/powercap"
+
+// files captured relative to basePath
+var fixtureCpuGlobs = []string{
+	"online",
+	"possible",
+	"present",
+	cStatesDrvPath,
+	"cpu[0-9]*/cpufreq/*",
+	"cpu[0-9]*/topology/*",
+	"cpu[0-9]*/cpuidle/state*/*",
+	"cpu[0-9]*/cache/index*/*",
+	uncoreDirName + "/*/*",
+}
+
+// directories captured relative to basePath, their presence is the information
+var fixtureCpuDirGlobs = []string{
+	"cpu[0-9]*/" + numaNodeGlob,
+}
+
+// files captured relative to powercapBasePath
+var fixturePowercapGlobs = []string{
+	"*/name",
+	"*/enabled",
+	"*/constraint_*",
+	"*/max_power_range_uw",
+	"*/max_energy_range_uj",
+}
+
+var fixtureLscpuKeys = []string{"Architecture", "Vendor ID"}
+
+// CaptureFixture writes a fixture of the current host to w. Files that do not exist or cannot be read
+// are left out, so a fixture of a host without e.g. uncore support simply has no uncore files.
+// MSRs are not captured, the effective frequency feature is unsupported when running against a fixture.
+func CaptureFixture(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	captureErr := func() error {
+		for _, pattern := range fixtureCpuGlobs {
+			if err := captureGlob(tw, basePath, pattern, fixtureCpuDir); err != nil {
+				return err
+			}
+		}
+		for _, pattern := range fixtureCpuDirGlobs {
+			if err := captureDirGlob(tw, basePath, pattern, fixtureCpuDir); err != nil {
+				return err
+			}
+		}
+		if err := captureFile(tw, kernelModulesFilePath, fixtureModulesFile); err != nil {
+			return err
+		}
+		if device := findPowerclampDevice(); device != "" {
+			for _, file := range []string{coolingTypeFile, coolingMaxStateFile, coolingCurStateFile} {
+				name := filepath.Join(fixtureThermalDir, filepath.Base(device), file)
+				if err := captureFile(tw, filepath.Join(device, file), name); err != nil {
+					return err
+				}
+			}
+		}
+		if err := captureFile(tw, filepath.Join(powerclampParamsPath, powerclampCpumaskFile),
+			filepath.Join(fixturePowerclampDir, powerclampCpumaskFile)); err != nil {
+			return err
+		}
+		for _, pattern := range fixturePowercapGlobs {
+			if err := captureGlob(tw, powercapBasePath, pattern, fixturePowercapDir); err != nil {
+				return err
+			}
+		}
+		lscpu := strings.Builder{}
+		for _, key := range fixtureLscpuKeys {
+			if value, err := GetFromLscpu("^" + key + ":"); err == nil {
+				fmt.Fprintf(&lscpu, "%s: %s\n", key, value)
+			}
+		}
+		return writeFixtureEntry(tw, fixtureLscpuFile, []byte(lscpu.String()))
+	}()
+
+	return errors.Join(captureErr, tw.Close(), gz.Close())
+}
+
+func captureGlob(tw *tar.Writer, root string, pattern string, prefix string) error {
+	matches, err := filepath.Glob(filepath.Join(root, pattern))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		rel, err := filepath.Rel(root, match)
+		if err != nil {
+			return err
+		}
+		if err := captureFile(tw, match, filepath.Join(prefix, rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// captureDirGlob adds empty directory entries for the directories matching pattern
+func captureDirGlob(tw *tar.Writer, root string, pattern string, prefix string) error {
+	matches, err := filepath.Glob(filepath.Join(root, pattern))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || !info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(root, match)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     filepath.ToSlash(filepath.Join(prefix, rel)) + "/",
+			Mode:     0755,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to fixture: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// captureFile adds a regular file to the archive, unreadable and missing files are skipped
+func captureFile(tw *tar.Writer, src string, name string) error {
+	info, err := os.Stat(src)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+	// sysfs reports a fixed size for its attributes, so the content is read before writing the header
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return nil
+	}
+	return writeFixtureEntry(tw, name, content)
+}
+
+func writeFixtureEntry(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filepath.ToSlash(name),
+		Mode:     0644,
+		Size:     int64(len(content)),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to fixture: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to fixture: %w", name, err)
+	}
+	return nil
+}
+
+// ExtractFixture unpacks a fixture tarball into dir
+func ExtractFixture(fixture string, dir string) error {
+	f, err := os.Open(fixture)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %w", fixture, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read fixture %s: %w", fixture, err)
+		}
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid fixture entry %s", header.Name)
+		}
+		target := filepath.Join(dir, header.Name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// loadFixture points the library at a fixture. A tarball is extracted to a temporary directory which is
+// not removed, as the library keeps writing to it. Paths already set in conf take precedence.
+func loadFixture(conf *LibConfig) error {
+	dir := conf.FixturePath
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to load fixture: %w", err)
+	}
+	if !info.IsDir() {
+		if dir, err = os.MkdirTemp("", "power-fixture-"); err != nil {
+			return fmt.Errorf("failed to load fixture: %w", err)
+		}
+		if err := ExtractFixture(conf.FixturePath, dir); err != nil {
+			return err
+		}
+	}
+
+	setDefault := func(value *string, fixturePath string) {
+		if *value == "" {
+			*value = filepath.Join(dir, fixturePath)
+		}
+	}
+	setDefault(&conf.CpuPath, fixtureCpuDir)
+	setDefault(&conf.ModulePath, fixtureModulesFile)
+	setDefault(&conf.ThermalPath, fixtureThermalDir)
+	setDefault(&conf.PowerclampParamsPath, fixturePowerclampDir)
+	powercapBasePath = filepath.Join(dir, fixturePowercapDir)
+
+	if conf.Cores == 0 {
+		cpus, _ := filepath.Glob(filepath.Join(conf.CpuPath, "cpu[0-9]*"))
+		conf.Cores = uint(len(cpus))
+	}
+	GetFromLscpu = fixtureLscpu(filepath.Join(dir, fixtureLscpuFile))
+	// MSRs of the machine running the library have nothing to do with the fixture
+	if _, ok := msrReader.(*devMSRReader); ok {
+		msrReader = fixtureMSRReader{}
+	}
+	return nil
+}
+
+// fixtureLscpu returns a GetFromLscpu replacement reading the lscpu file of a fixture
+func fixtureLscpu(path string) func(regex string) (string, error) {
+	return func(regex string) (string, error) {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return "", err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := scanner.Text(); re.MatchString(line) {
+				_, value, _ := strings.Cut(line, ":")
+				return strings.Join(strings.Fields(value), ""), nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%s not found in fixture lscpu", regex)
+	}
+}
+
+type fixtureMSRReader struct{}
+
+func (fixtureMSRReader) ReadMSR(cpuID uint, register uint32) (uint64, error) {
+	return 0, fmt.Errorf("msr %#x of cpu %d is not available in a fixture", register, cpuID)
+}
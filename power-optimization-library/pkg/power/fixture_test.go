@@ -0,0 +1,190 @@
+package power
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupFixtureHost emulates a two CPU host below a temporary directory and points the library at it
+func setupFixtureHost(t *testing.T) func() {
+	origBasePath := basePath
+	origModulesPath := kernelModulesFilePath
+	origThermalPath := thermalBasePath
+	origParamsPath := powerclampParamsPath
+	origPowercapPath := powercapBasePath
+	origGetFromLscpu := GetFromLscpu
+	origGetNumOfCpusFunc := getNumberOfCpus
+	origMSRReader := msrReader
+	origCoreTypes := coreTypes
+	origDefaultPStates := allCPUDefaultPStatesInfo
+	origAvailableFreqs := allCPUAvailableFreqs
+	origAvailableGovs := availableGovs
+	origCStatesInfo := allCPUCStatesInfo
+	origIdleInjectionDevice := idleInjectionDevice
+	origIdleInjectionMaxState := idleInjectionMaxState
+
+	tmpDir := t.TempDir()
+	basePath = filepath.Join(tmpDir, "cpu")
+	kernelModulesFilePath = filepath.Join(tmpDir, "modules")
+	thermalBasePath = filepath.Join(tmpDir, "thermal")
+	powerclampParamsPath = filepath.Join(tmpDir, "parameters")
+	powercapBasePath = filepath.Join(tmpDir, "powercap")
+	GetFromLscpu = TestGetFromLscpu
+
+	files := map[string]string{
+		filepath.Join(basePath, "online"):                                              "0-1\n",
+		filepath.Join(basePath, cStatesDrvPath):                                        "intel_idle\n",
+		kernelModulesFilePath:                                                          "intel_powerclamp 20480 0 - Live 0xffffffffc0a5e000\n",
+		filepath.Join(thermalBasePath, "cooling_device0", coolingTypeFile):             "Processor\n",
+		filepath.Join(thermalBasePath, "cooling_device3", coolingTypeFile):             "intel_powerclamp\n",
+		filepath.Join(thermalBasePath, "cooling_device3", coolingMaxStateFile):         "50\n",
+		filepath.Join(thermalBasePath, "cooling_device3", coolingCurStateFile):         "0\n",
+		filepath.Join(powerclampParamsPath, powerclampCpumaskFile):                     "0\n",
+		filepath.Join(powercapBasePath, "intel-rapl:0", "name"):                        "package-0\n",
+		filepath.Join(powercapBasePath, "intel-rapl:0", "constraint_0_power_limit_uw"): "150000000\n",
+	}
+	for i := 0; i < 2; i++ {
+		cpu := filepath.Join(basePath, fmt.Sprint("cpu", i))
+		files[filepath.Join(cpu, pStatesDrvFile)] = "intel_pstate\n"
+		files[filepath.Join(cpu, cpuMaxFreqFile)] = "3500000\n"
+		files[filepath.Join(cpu, cpuMinFreqFile)] = "800000\n"
+		files[filepath.Join(cpu, scalingMaxFile)] = "3500000\n"
+		files[filepath.Join(cpu, scalingMinFile)] = "800000\n"
+		files[filepath.Join(cpu, scalingGovFile)] = "powersave\n"
+		files[filepath.Join(cpu, availGovFile)] = "performance powersave\n"
+		files[filepath.Join(cpu, eppFile)] = "balance_performance\n"
+		files[filepath.Join(cpu, packageIdFile)] = "0\n"
+		files[filepath.Join(cpu, dieIdFile)] = "0\n"
+		files[filepath.Join(cpu, coreIdFile)] = fmt.Sprint(i, "\n")
+		files[filepath.Join(cpu, fmt.Sprintf(cStateNameFileFmt, 0))] = "POLL\n"
+		files[filepath.Join(cpu, fmt.Sprintf(cStateLatencyFileFmt, 0))] = "0\n"
+		files[filepath.Join(cpu, fmt.Sprintf(cStateDisableFileFmt, 0))] = "0\n"
+		files[filepath.Join(cpu, "cache", "index3", cacheLevelFile)] = "3\n"
+		files[filepath.Join(cpu, "cache", "index3", cacheTypeFile)] = "Unified\n"
+		files[filepath.Join(cpu, "cache", "index3", cacheIdFile)] = "0\n"
+		assert.NoError(t, os.MkdirAll(filepath.Join(cpu, "node0"), os.ModePerm))
+	}
+	for path, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return func() {
+		basePath = origBasePath
+		kernelModulesFilePath = origModulesPath
+		thermalBasePath = origThermalPath
+		powerclampParamsPath = origParamsPath
+		powercapBasePath = origPowercapPath
+		GetFromLscpu = origGetFromLscpu
+		getNumberOfCpus = origGetNumOfCpusFunc
+		msrReader = origMSRReader
+		coreTypes = origCoreTypes
+		allCPUDefaultPStatesInfo = origDefaultPStates
+		allCPUAvailableFreqs = origAvailableFreqs
+		availableGovs = origAvailableGovs
+		allCPUCStatesInfo = origCStatesInfo
+		idleInjectionDevice = origIdleInjectionDevice
+		idleInjectionMaxState = origIdleInjectionMaxState
+		for _, status := range featureList {
+			status.err = uninitialisedErr
+		}
+	}
+}
+
+func TestCaptureFixture(t *testing.T) {
+	defer setupFixtureHost(t)()
+	hostCpuPath := basePath
+	// tarballs are extracted below TMPDIR
+	t.Setenv("TMPDIR", t.TempDir())
+
+	fixture := filepath.Join(t.TempDir(), "host.tar.gz")
+	f, err := os.Create(fixture)
+	assert.NoError(t, err)
+	assert.NoError(t, CaptureFixture(f))
+	assert.NoError(t, f.Close())
+
+	dir := t.TempDir()
+	assert.NoError(t, ExtractFixture(fixture, dir))
+	for file, expected := range map[string]string{
+		"cpu/online":                        "0-1\n",
+		"cpu/cpu1/cpufreq/cpuinfo_max_freq": "3500000\n",
+		"cpu/cpu0/cpuidle/state0/name":      "POLL\n",
+		"modules":                           "intel_powerclamp 20480 0 - Live 0xffffffffc0a5e000\n",
+		"thermal/cooling_device3/type":      "intel_powerclamp\n",
+		"powerclamp/cpumask":                "0\n",
+		"powercap/intel-rapl:0/name":        "package-0\n",
+		"lscpu":                             "Architecture: x86_64\nVendor ID: GenuineIntel\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err, file)
+		assert.Equal(t, expected, string(content), file)
+	}
+	assert.DirExists(t, filepath.Join(dir, "cpu", "cpu1", "node0"))
+	// only the powerclamp cooling device is captured
+	assert.NoDirExists(t, filepath.Join(dir, "thermal", "cooling_device0"))
+
+	// the library runs against the fixture without touching the emulated host
+	GetFromLscpu = func(string) (string, error) { return "", fmt.Errorf("lscpu not available") }
+	host, err := CreateInstanceWithConf("fixture", LibConfig{FixturePath: fixture})
+	assert.ErrorContains(t, err, "not available in a fixture")
+	assert.NotNil(t, host)
+	assert.Len(t, *host.GetAllCpus(), 2)
+	assert.Equal(t, "x86_64", host.GetArchitecture())
+	assert.Equal(t, "GenuineIntel", host.GetVendorID())
+	assert.True(t, IsFeatureSupported(FrequencyScalingFeature, EPPFeature, CStatesFeature, IdleInjectionFeature))
+	assert.False(t, IsFeatureSupported(EffectiveFrequencyFeature))
+	assert.Equal(t, uint(50), GetIdleInjectionMaxPercent())
+	assert.Len(t, *host.Topology().NumaNode(0).CPUs(), 2)
+	assert.Len(t, *host.Topology().CacheDomain(0).CPUs(), 2)
+	assert.NotEqual(t, hostCpuPath, basePath)
+
+	// extracted directory can be used directly
+	_, err = CreateInstanceWithConf("fixture", LibConfig{FixturePath: dir})
+	assert.ErrorContains(t, err, "not available in a fixture")
+	assert.Equal(t, filepath.Join(dir, fixtureCpuDir), basePath)
+}
+
+func TestExtractFixture_errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.Error(t, ExtractFixture(filepath.Join(tmpDir, "missing.tar.gz"), tmpDir))
+
+	notGzip := filepath.Join(tmpDir, "plain")
+	assert.NoError(t, os.WriteFile(notGzip, []byte("plain"), 0644))
+	assert.ErrorContains(t, ExtractFixture(notGzip, tmpDir), "failed to read fixture")
+
+	escaping := filepath.Join(tmpDir, "escaping.tar.gz")
+	f, err := os.Create(escaping)
+	assert.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	assert.NoError(t, writeFixtureEntry(tw, "../outside", []byte("x")))
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	assert.NoError(t, f.Close())
+	assert.ErrorContains(t, ExtractFixture(escaping, filepath.Join(tmpDir, "out")), "invalid fixture entry")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "outside"))
+
+	_, err = CreateInstanceWithConf("fixture", LibConfig{FixturePath: filepath.Join(tmpDir, "missing")})
+	assert.ErrorContains(t, err, "failed to load fixture")
+}
+
+func TestFixtureLscpu(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lscpu")
+	assert.NoError(t, os.WriteFile(path, []byte("Architecture: aarch64\nVendor ID:   ARM Ltd\n"), 0644))
+	lscpu := fixtureLscpu(path)
+
+	value, err := lscpu("^Architecture:")
+	assert.NoError(t, err)
+	assert.Equal(t, "aarch64", value)
+	value, err = lscpu("^Vendor ID:")
+	assert.NoError(t, err)
+	assert.Equal(t, "ARMLtd", value)
+	_, err = lscpu("^Model name:")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,172 @@
+package power
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FrequencyRequester identifies a control loop that places frequency limits on a CPU
+type FrequencyRequester string
+
+const (
+	// ProfileRequester is used by the library for limits coming from the pool's power profile
+	ProfileRequester  FrequencyRequester = "profile"
+	ScalerRequester   FrequencyRequester = "scaler"
+	ThermalRequester  FrequencyRequester = "thermal"
+	PowerCapRequester FrequencyRequester = "powercap"
+)
+
+// FrequencyConstraint is a min/max frequency request in kHz. A zero Min or Max means
+// the requester does not constrain that bound
+type FrequencyConstraint struct {
+	Min uint `json:"min,omitempty"`
+	Max uint `json:"max,omitempty"`
+}
+
+// freqConstraints aggregates frequency requests from multiple requesters, similarly to the
+// kernel's frequency QoS: the effective minimum is the highest requested minimum and
+// the effective maximum is the lowest requested maximum. If they conflict the maximum wins
+type freqConstraints struct {
+	mutex    sync.Mutex
+	requests map[FrequencyRequester]FrequencyConstraint
+}
+
+// effective returns the aggregated limits bounded by hwMin and hwMax
+func (c *freqConstraints) effective(hwMin, hwMax uint) (uint, uint) {
+	return c.effectiveExcluding("", hwMin, hwMax)
+}
+
+// effectiveExcluding returns the limits aggregated from all requests but the one of excluded
+func (c *freqConstraints) effectiveExcluding(excluded FrequencyRequester, hwMin, hwMax uint) (uint, uint) {
+	min, max := hwMin, hwMax
+	for requester, request := range c.requests {
+		if requester == excluded {
+			continue
+		}
+		if request.Min != 0 && request.Min > min {
+			min = request.Min
+		}
+		if request.Max != 0 && request.Max < max {
+			max = request.Max
+		}
+	}
+	if min > max {
+		min = max
+	}
+	return min, max
+}
+
+// SetFrequencyConstraint registers or replaces the limits requested by requester and
+// writes the resulting effective limits to the CPU
+func (cpu *cpuImpl) SetFrequencyConstraint(requester FrequencyRequester, constraint FrequencyConstraint) error {
+	if !IsFeatureSupported(FrequencyScalingFeature) {
+		return featureList.getFeatureIdError(FrequencyScalingFeature)
+	}
+	if constraint.Min != 0 && constraint.Max != 0 && constraint.Min > constraint.Max {
+		return newError(ErrInvalidValue, "minimum frequency %d cannot be higher than maximum frequency %d", constraint.Min, constraint.Max)
+	}
+	return cpu.setFrequencyConstraint(requester, constraint)
+}
+
+func (cpu *cpuImpl) setFrequencyConstraint(requester FrequencyRequester, constraint FrequencyConstraint) error {
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+
+	if cpu.freqConstraints.requests == nil {
+		cpu.freqConstraints.requests = map[FrequencyRequester]FrequencyConstraint{}
+	}
+	prev, existed := cpu.freqConstraints.requests[requester]
+	cpu.freqConstraints.requests[requester] = constraint
+	if err := cpu.writeEffectiveFrequencyLimits(); err != nil {
+		if existed {
+			cpu.freqConstraints.requests[requester] = prev
+		} else {
+			delete(cpu.freqConstraints.requests, requester)
+		}
+		return err
+	}
+	return nil
+}
+
+// ClearFrequencyConstraint removes the limits requested by requester and writes the
+// resulting effective limits to the CPU
+func (cpu *cpuImpl) ClearFrequencyConstraint(requester FrequencyRequester) error {
+	if !IsFeatureSupported(FrequencyScalingFeature) {
+		return featureList.getFeatureIdError(FrequencyScalingFeature)
+	}
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+
+	if _, exists := cpu.freqConstraints.requests[requester]; !exists {
+		return nil
+	}
+	delete(cpu.freqConstraints.requests, requester)
+	return cpu.writeEffectiveFrequencyLimits()
+}
+
+// GetFrequencyConstraints returns a copy of all currently registered requests
+func (cpu *cpuImpl) GetFrequencyConstraints() map[FrequencyRequester]FrequencyConstraint {
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+
+	requests := make(map[FrequencyRequester]FrequencyConstraint, len(cpu.freqConstraints.requests))
+	for requester, constraint := range cpu.freqConstraints.requests {
+		requests[requester] = constraint
+	}
+	return requests
+}
+
+// GetEffectiveFrequencyLimits returns the min and max frequency resulting from all requests
+func (cpu *cpuImpl) GetEffectiveFrequencyLimits() (uint, uint) {
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+	return cpu.effectiveFrequencyLimits()
+}
+
+// GetFrequencyLimitsExcluding returns the min and max frequency resulting from all requests but the one of
+// requester, i.e. the range requester can place its own limits in
+func (cpu *cpuImpl) GetFrequencyLimitsExcluding(requester FrequencyRequester) (uint, uint) {
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+	hwMin, hwMax := cpu.GetAbsMinMax()
+	min, max := cpu.freqConstraints.effectiveExcluding(requester, hwMin, hwMax)
+	return snapLimits(cpu.availableFrequencies(), min, max)
+}
+
+// effectiveFrequencyLimits returns the aggregated limits snapped to the available frequencies,
+// must be called with freqConstraints mutex held
+func (cpu *cpuImpl) effectiveFrequencyLimits() (uint, uint) {
+	min, max := cpu.freqConstraints.effective(cpu.GetAbsMinMax())
+	return snapLimits(cpu.availableFrequencies(), min, max)
+}
+
+// must be called with freqConstraints mutex held
+func (cpu *cpuImpl) writeEffectiveFrequencyLimits() error {
+	min, max := cpu.effectiveFrequencyLimits()
+	log.V(5).Info("writing effective frequency limits", "cpu", cpu.id, "min", min, "max", max, "requests", cpu.freqConstraints.requests)
+	if err := cpu.writeScalingMaxFreq(max); err != nil {
+		return fmt.Errorf("failed to set MaxFreq value for cpu %d: %w", cpu.id, err)
+	}
+	if err := cpu.writeScalingMinFreq(min); err != nil {
+		return fmt.Errorf("failed to set MinFreq value for cpu %d: %w", cpu.id, err)
+	}
+	return nil
+}
+
+// clampToEffectiveLimits bounds a frequency target to the effective limits,
+// targets are left untouched if no requests are registered
+func (cpu *cpuImpl) clampToEffectiveLimits(frequency uint) uint {
+	cpu.freqConstraints.mutex.Lock()
+	defer cpu.freqConstraints.mutex.Unlock()
+	if len(cpu.freqConstraints.requests) == 0 {
+		return frequency
+	}
+	min, max := cpu.effectiveFrequencyLimits()
+	if frequency < min {
+		return min
+	}
+	if frequency > max {
+		return max
+	}
+	return frequency
+}
@@ -0,0 +1,160 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreqConstraints_effective(t *testing.T) {
+	tests := []struct {
+		name        string
+		requests    map[FrequencyRequester]FrequencyConstraint
+		expectedMin uint
+		expectedMax uint
+	}{
+		{
+			name:        "no requests",
+			expectedMin: 1000,
+			expectedMax: 3000,
+		},
+		{
+			name: "single request",
+			requests: map[FrequencyRequester]FrequencyConstraint{
+				ProfileRequester: {Min: 1500, Max: 2500},
+			},
+			expectedMin: 1500,
+			expectedMax: 2500,
+		},
+		{
+			name: "highest min and lowest max win",
+			requests: map[FrequencyRequester]FrequencyConstraint{
+				ProfileRequester:  {Min: 1200, Max: 2800},
+				ThermalRequester:  {Max: 2000},
+				PowerCapRequester: {Min: 1400, Max: 2400},
+			},
+			expectedMin: 1400,
+			expectedMax: 2000,
+		},
+		{
+			name: "conflicting requests, max wins",
+			requests: map[FrequencyRequester]FrequencyConstraint{
+				ProfileRequester: {Min: 2500, Max: 3000},
+				ThermalRequester: {Max: 1800},
+			},
+			expectedMin: 1800,
+			expectedMax: 1800,
+		},
+		{
+			name: "requests outside hardware limits are bounded",
+			requests: map[FrequencyRequester]FrequencyConstraint{
+				ScalerRequester: {Min: 500, Max: 5000},
+			},
+			expectedMin: 1000,
+			expectedMax: 3000,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			constraints := freqConstraints{requests: tc.requests}
+			min, max := constraints.effective(1000, 3000)
+			assert.Equal(t, tc.expectedMin, min)
+			assert.Equal(t, tc.expectedMax, max)
+		})
+	}
+}
+
+func TestCpuImpl_SetFrequencyConstraint(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "3000", "min": "1000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+	readLimits := func() (uint, uint) {
+		min, err := readCpuUintProperty(0, scalingMinFile)
+		assert.NoError(t, err)
+		max, err := readCpuUintProperty(0, scalingMaxFile)
+		assert.NoError(t, err)
+		return min, max
+	}
+
+	assert.NoError(t, cpu.SetFrequencyConstraint(ProfileRequester, FrequencyConstraint{Min: 1200, Max: 2800}))
+	min, max := readLimits()
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2800), max)
+
+	// a second requester can only narrow the range
+	assert.NoError(t, cpu.SetFrequencyConstraint(ThermalRequester, FrequencyConstraint{Max: 2000}))
+	min, max = readLimits()
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2000), max)
+	min, max = cpu.GetEffectiveFrequencyLimits()
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2000), max)
+	assert.Equal(t, map[FrequencyRequester]FrequencyConstraint{
+		ProfileRequester: {Min: 1200, Max: 2800},
+		ThermalRequester: {Max: 2000},
+	}, cpu.GetFrequencyConstraints())
+
+	// setspeed targets are bounded by the effective limits
+	assert.NoError(t, cpu.SetCPUFrequency(2500))
+	setspeed, err := readCpuUintProperty(0, scalingSetSpeedFile)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2000), setspeed)
+
+	// clearing a request restores the remaining limits
+	assert.NoError(t, cpu.ClearFrequencyConstraint(ThermalRequester))
+	min, max = readLimits()
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2800), max)
+	assert.NoError(t, cpu.ClearFrequencyConstraint(ThermalRequester))
+
+	// invalid request
+	assert.ErrorContains(t, cpu.SetFrequencyConstraint(ScalerRequester, FrequencyConstraint{Min: 2000, Max: 1500}), "cannot be higher")
+
+	// failed write does not register the request
+	assert.NoError(t, os.RemoveAll(filepath.Join(basePath, "cpu0", "cpufreq")))
+	assert.ErrorContains(t, cpu.SetFrequencyConstraint(ScalerRequester, FrequencyConstraint{Max: 1500}), "failed to set MaxFreq value for cpu 0")
+	assert.NotContains(t, cpu.GetFrequencyConstraints(), ScalerRequester)
+
+	// feature not supported
+	featureList[FrequencyScalingFeature].err = fmt.Errorf("not supported")
+	assert.ErrorContains(t, cpu.SetFrequencyConstraint(ScalerRequester, FrequencyConstraint{Max: 1500}), "not supported")
+	assert.ErrorContains(t, cpu.ClearFrequencyConstraint(ProfileRequester), "not supported")
+}
+
+func TestCpuImpl_GetFrequencyLimitsExcluding(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "3000", "min": "1000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+
+	assert.NoError(t, cpu.SetFrequencyConstraint(ProfileRequester, FrequencyConstraint{Min: 1200, Max: 2800}))
+	assert.NoError(t, cpu.SetFrequencyConstraint(ScalerRequester, FrequencyConstraint{Max: 1500}))
+	min, max := cpu.GetEffectiveFrequencyLimits()
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(1500), max)
+
+	// the scaler places its limits within the range of the other requesters
+	min, max = cpu.GetFrequencyLimitsExcluding(ScalerRequester)
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2800), max)
+	min, max = cpu.GetFrequencyLimitsExcluding(ThermalRequester)
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(1500), max)
+}
+
+func TestCpuImpl_SetCPUFrequency_noConstraints(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "3000", "min": "1000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+
+	// without any requests the target is written as is
+	assert.NoError(t, cpu.SetCPUFrequency(5000))
+	setspeed, err := readCpuUintProperty(0, scalingSetSpeedFile)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5000), setspeed)
+}
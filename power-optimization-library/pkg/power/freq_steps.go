@@ -0,0 +1,107 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// drivers such as acpi-cpufreq only accept the discrete frequencies listed in this file
+const scalingAvailFreqsFile = "cpufreq/scaling_available_frequencies"
+
+// available frequencies of each cpu in ascending order, nil if the driver accepts any frequency within the hardware limits
+var allCPUAvailableFreqs [][]uint
+
+// readAvailableFrequencies returns the discrete frequencies a cpu accepts in ascending order,
+// nil is returned if the scaling driver does not list them
+func readAvailableFrequencies(cpuID uint) ([]uint, error) {
+	value, err := readCpuStringProperty(cpuID, scalingAvailFreqsFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	freqs := make([]uint, 0, len(fields))
+	for _, field := range fields {
+		freq, err := strconv.ParseUint(field, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid available frequency %q of cpu %d: %w", field, cpuID, err)
+		}
+		freqs = append(freqs, uint(freq))
+	}
+	slices.Sort(freqs)
+	return slices.Compact(freqs), nil
+}
+
+// GetAvailableFrequencies returns the discrete frequencies in kHz the CPU accepts in ascending order.
+// An empty list means any frequency within the hardware limits can be set
+func (cpu *cpuImpl) GetAvailableFrequencies() []uint {
+	return slices.Clone(cpu.availableFrequencies())
+}
+
+func (cpu *cpuImpl) availableFrequencies() []uint {
+	if !featureList.isFeatureIdSupported(FrequencyScalingFeature) || int(cpu.id) >= len(allCPUAvailableFreqs) {
+		return nil
+	}
+	return allCPUAvailableFreqs[cpu.id]
+}
+
+// SnapFrequency returns the available frequency nearest to frequency, ties are resolved towards the lower
+// frequency. The frequency is returned unchanged if the CPU accepts any frequency
+func (cpu *cpuImpl) SnapFrequency(frequency uint) uint {
+	return snapNearest(cpu.availableFrequencies(), frequency)
+}
+
+// snapLimits rounds the minimum up and the maximum down to the nearest available frequencies, so the
+// resulting range never exceeds the requested one. If no available frequency lies within the range the
+// maximum wins, in line with the aggregation of frequency constraints
+func snapLimits(freqs []uint, min, max uint) (uint, uint) {
+	if len(freqs) == 0 {
+		return min, max
+	}
+	min, max = snapUp(freqs, min), snapDown(freqs, max)
+	if min > max {
+		min = max
+	}
+	return min, max
+}
+
+// snapUp returns the lowest available frequency not below frequency, or the highest one if there is none
+func snapUp(freqs []uint, frequency uint) uint {
+	i, _ := slices.BinarySearch(freqs, frequency)
+	if i == len(freqs) {
+		return freqs[len(freqs)-1]
+	}
+	return freqs[i]
+}
+
+// snapDown returns the highest available frequency not above frequency, or the lowest one if there is none
+func snapDown(freqs []uint, frequency uint) uint {
+	i, found := slices.BinarySearch(freqs, frequency)
+	if found {
+		return freqs[i]
+	}
+	if i == 0 {
+		return freqs[0]
+	}
+	return freqs[i-1]
+}
+
+func snapNearest(freqs []uint, frequency uint) uint {
+	if len(freqs) == 0 {
+		return frequency
+	}
+	lower, upper := snapDown(freqs, frequency), snapUp(freqs, frequency)
+	// outside of the available range both are the closest bound
+	if lower > frequency || upper < frequency || upper-frequency >= frequency-lower {
+		return lower
+	}
+	return upper
+}
@@ -0,0 +1,103 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAvailableFrequencies(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "2401000", "min": "1000000", "available_frequencies": "2401000 2400000 1800000 1000000 "},
+		"cpu1": {"max": "2400000", "min": "1000000"},
+	})()
+
+	freqs, err := readAvailableFrequencies(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1000000, 1800000, 2400000, 2401000}, freqs)
+
+	// drivers that do not list frequencies accept any value
+	freqs, err = readAvailableFrequencies(1)
+	assert.NoError(t, err)
+	assert.Nil(t, freqs)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(basePath, "cpu1", scalingAvailFreqsFile), []byte("1000000 fast\n"), 0644))
+	_, err = readAvailableFrequencies(1)
+	assert.ErrorContains(t, err, "invalid available frequency \"fast\" of cpu 1")
+}
+
+func TestSnapFrequencies(t *testing.T) {
+	freqs := []uint{1000, 1500, 2000, 3000}
+
+	assert.Equal(t, uint(1500), snapUp(freqs, 1200))
+	assert.Equal(t, uint(1500), snapUp(freqs, 1500))
+	assert.Equal(t, uint(3000), snapUp(freqs, 3500))
+	assert.Equal(t, uint(1000), snapDown(freqs, 1200))
+	assert.Equal(t, uint(2000), snapDown(freqs, 2000))
+	assert.Equal(t, uint(1000), snapDown(freqs, 500))
+
+	assert.Equal(t, uint(1000), snapNearest(freqs, 500))
+	assert.Equal(t, uint(1000), snapNearest(freqs, 1200))
+	assert.Equal(t, uint(1500), snapNearest(freqs, 1300))
+	// ties are resolved towards the lower frequency
+	assert.Equal(t, uint(2000), snapNearest(freqs, 2500))
+	assert.Equal(t, uint(3000), snapNearest(freqs, 4000))
+	assert.Equal(t, uint(1234), snapNearest(nil, 1234))
+
+	min, max := snapLimits(freqs, 1200, 2800)
+	assert.Equal(t, uint(1500), min)
+	assert.Equal(t, uint(2000), max)
+	// no available frequency within the range, the maximum wins
+	min, max = snapLimits(freqs, 2200, 2800)
+	assert.Equal(t, uint(2000), min)
+	assert.Equal(t, uint(2000), max)
+	min, max = snapLimits(nil, 1200, 2800)
+	assert.Equal(t, uint(1200), min)
+	assert.Equal(t, uint(2800), max)
+}
+
+func TestCpuImpl_availableFrequencies(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"max": "3000", "min": "1000", "available_frequencies": "3000 2000 1500 1000"},
+	})()
+	cpu := &cpuImpl{id: 0}
+	readLimits := func() (uint, uint) {
+		min, err := readCpuUintProperty(0, scalingMinFile)
+		assert.NoError(t, err)
+		max, err := readCpuUintProperty(0, scalingMaxFile)
+		assert.NoError(t, err)
+		return min, max
+	}
+
+	freqs := cpu.GetAvailableFrequencies()
+	assert.Equal(t, []uint{1000, 1500, 2000, 3000}, freqs)
+	freqs[0] = 0
+	assert.Equal(t, uint(1000), allCPUAvailableFreqs[0][0])
+	assert.Equal(t, uint(2000), cpu.SnapFrequency(2200))
+
+	// written limits stay within the requested range
+	assert.NoError(t, cpu.SetFrequencyConstraint(ProfileRequester, FrequencyConstraint{Min: 1200, Max: 2800}))
+	min, max := readLimits()
+	assert.Equal(t, uint(1500), min)
+	assert.Equal(t, uint(2000), max)
+	min, max = cpu.GetEffectiveFrequencyLimits()
+	assert.Equal(t, uint(1500), min)
+	assert.Equal(t, uint(2000), max)
+
+	// setspeed targets are snapped within the effective limits
+	assert.NoError(t, cpu.SetCPUFrequency(1700))
+	setspeed, err := readCpuUintProperty(0, scalingSetSpeedFile)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1500), setspeed)
+	assert.NoError(t, cpu.SetCPUFrequency(2900))
+	setspeed, err = readCpuUintProperty(0, scalingSetSpeedFile)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2000), setspeed)
+
+	// frequencies are only snapped while scaling is supported
+	featureList[FrequencyScalingFeature].err = uninitialisedErr
+	assert.Empty(t, cpu.GetAvailableFrequencies())
+	assert.Equal(t, uint(2200), cpu.SnapFrequency(2200))
+}
@@ -0,0 +1,207 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// governorTunablesDir is the directory of a governor's tunables, relative to a cpu directory when the
+// governor is instantiated per policy, or to basePath when it is shared by all policies
+const governorTunablesDir = "cpufreq"
+
+// tunables accepted for each governor, governors not listed here have none
+var knownGovernorTunables = map[string][]string{
+	cpuPolicySchedutil: {"rate_limit_us"},
+	cpuPolicyOndemand: {"up_threshold", "sampling_rate", "sampling_down_factor", "ignore_nice_load", "powersave_bias",
+		"io_is_busy"},
+	cpuPolicyConservative: {"up_threshold", "down_threshold", "freq_step", "sampling_rate", "sampling_down_factor",
+		"ignore_nice_load"},
+}
+
+// ValidateGovernorTunables validates tunables requested for a governor. Names are checked against the attributes
+// the governor exposes in sysfs if it is currently in use, and against the known tunables of the governor otherwise
+func ValidateGovernorTunables(governor string, tunables map[string]int) error {
+	if len(tunables) == 0 {
+		return nil
+	}
+	if governor == "" {
+		governor = defaultGovernor
+	}
+	known, exists := knownGovernorTunables[governor]
+	if !exists {
+		return newError(ErrUnsupported, "governor %s has no tunables", governor)
+	}
+	names := make([]string, 0, len(tunables))
+	for name := range tunables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return newError(ErrInvalidValue, "%s is not a tunable of governor %s, use one of %v", name, governor, known)
+		}
+		if tunables[name] < 0 {
+			return newError(ErrInvalidValue, "governor tunable %s must be a non-negative integer, got %d", name, tunables[name])
+		}
+		if exposed, inUse := exposedGovernorTunables(governor); inUse && !slices.Contains(exposed, name) {
+			return newError(ErrUnsupported, "governor %s does not expose %s on this system", governor, name)
+		}
+	}
+	return nil
+}
+
+// exposedGovernorTunables lists the attributes of the governor's tunables directory of cpu 0 or the global one.
+// The directories only exist while the governor is in use, false is returned if neither exists
+func exposedGovernorTunables(governor string) ([]string, bool) {
+	dirs := []string{
+		filepath.Join(basePath, "cpu0", governorTunablesDir, governor),
+		filepath.Join(basePath, governorTunablesDir, governor),
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		exposed := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			exposed = append(exposed, entry.Name())
+		}
+		return exposed, true
+	}
+	return nil, false
+}
+
+// governorTunablePath returns the path of a tunable of the cpu's governor, preferring the policy level attribute
+// over the global one. An empty string is returned if the governor does not expose the tunable
+func (cpu *cpuImpl) governorTunablePath(governor string, name string) string {
+	paths := []string{
+		filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), governorTunablesDir, governor, name),
+		filepath.Join(basePath, governorTunablesDir, governor, name),
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// sharedGovernorTunable is a tunable file overridden for the cpus in users. The file is shared by the cpus of a
+// policy, and by all policies of the host for governors without per policy tunables, e.g. with acpi-cpufreq
+type sharedGovernorTunable struct {
+	original string
+	users    map[uint]struct{}
+}
+
+// sharedGovernorTunables tracks the overridden tunables once per file, so the original value is read before the
+// first cpu overrides it and restored when the last cpu releases it. The mutex also serialises cpus consolidated
+// in parallel
+var sharedGovernorTunables = struct {
+	sync.Mutex
+	tunables map[string]*sharedGovernorTunable // resolved path -> tunable
+}{tunables: map[string]*sharedGovernorTunable{}}
+
+// governorTunableKey resolves the cpufreq symlink of a cpu to the policy directory, so the cpus of a policy share
+// the same key for a tunable
+func governorTunableKey(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// restoreGovernorTunables writes back the original value of the tunables overridden by the library that are not
+// requested by pstates. It runs before the governor is changed, while the overridden tunables still exist
+func (cpu *cpuImpl) restoreGovernorTunables(pstates PStates) error {
+	var errs []error
+	for path, key := range cpu.governorTunables {
+		governor, name := filepath.Base(filepath.Dir(path)), filepath.Base(path)
+		if _, requested := pstates.GetGovernorTunables()[name]; requested && governor == pstates.GetGovernor() {
+			continue
+		}
+		if err := cpu.releaseSharedGovernorTunable(key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore governor tunable %s: %w", name, writeError(err)))
+			continue
+		}
+		delete(cpu.governorTunables, path)
+	}
+	return errors.Join(errs...)
+}
+
+// applyGovernorTunables writes the tunables requested by pstates, remembering the original values so they can be
+// restored once no cpu uses them. Tunables shared by several cpus are last writer wins
+func (cpu *cpuImpl) applyGovernorTunables(pstates PStates) error {
+	var errs []error
+	names := make([]string, 0, len(pstates.GetGovernorTunables()))
+	for name := range pstates.GetGovernorTunables() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := cpu.governorTunablePath(pstates.GetGovernor(), name)
+		if path == "" {
+			errs = append(errs, newError(ErrUnsupported, "governor %s of cpu %d does not expose %s", pstates.GetGovernor(), cpu.id, name))
+			continue
+		}
+		value := fmt.Sprint(pstates.GetGovernorTunables()[name])
+		if err := cpu.applySharedGovernorTunable(path, value); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set governor tunable %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applySharedGovernorTunable writes a tunable and registers the cpu as one of its users, reading the original
+// value if no other cpu overrides the same file
+func (cpu *cpuImpl) applySharedGovernorTunable(path string, value string) error {
+	sharedGovernorTunables.Lock()
+	defer sharedGovernorTunables.Unlock()
+
+	key := governorTunableKey(path)
+	tunable, overridden := sharedGovernorTunables.tunables[key]
+	if !overridden {
+		original, err := readStringFromFile(path)
+		if err != nil {
+			return err
+		}
+		tunable = &sharedGovernorTunable{original: strings.TrimSpace(original), users: map[uint]struct{}{}}
+	}
+	if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+		return writeError(err)
+	}
+	tunable.users[cpu.id] = struct{}{}
+	sharedGovernorTunables.tunables[key] = tunable
+	if cpu.governorTunables == nil {
+		cpu.governorTunables = map[string]string{}
+	}
+	cpu.governorTunables[path] = key
+	return nil
+}
+
+// releaseSharedGovernorTunable unregisters the cpu from the users of a tunable and writes back the original
+// value once no cpu uses it anymore
+func (cpu *cpuImpl) releaseSharedGovernorTunable(key string) error {
+	sharedGovernorTunables.Lock()
+	defer sharedGovernorTunables.Unlock()
+
+	tunable, overridden := sharedGovernorTunables.tunables[key]
+	if !overridden {
+		return nil
+	}
+	delete(tunable.users, cpu.id)
+	if len(tunable.users) > 0 {
+		return nil
+	}
+	if err := os.WriteFile(key, []byte(tunable.original), 0644); err != nil && !os.IsNotExist(err) {
+		tunable.users[cpu.id] = struct{}{}
+		return err
+	}
+	delete(sharedGovernorTunables.tunables, key)
+	return nil
+}
@@ -0,0 +1,188 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGovernorTunables(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"governor": cpuPolicySchedutil},
+	})()
+
+	assert.NoError(t, ValidateGovernorTunables(cpuPolicyPerformance, nil))
+	// schedutil is not in use, the known tunables are accepted
+	assert.NoError(t, ValidateGovernorTunables(cpuPolicySchedutil, map[string]int{"rate_limit_us": 500}))
+
+	err := ValidateGovernorTunables(cpuPolicyPerformance, map[string]int{"rate_limit_us": 500})
+	assert.ErrorContains(t, err, "governor performance has no tunables")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	err = ValidateGovernorTunables(cpuPolicySchedutil, map[string]int{"up_threshold": 80})
+	assert.ErrorContains(t, err, "up_threshold is not a tunable of governor schedutil")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	err = ValidateGovernorTunables(cpuPolicyOndemand, map[string]int{"up_threshold": -1})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	// once the governor is in use, its attributes are checked
+	ondemandDir := filepath.Join(basePath, governorTunablesDir, cpuPolicyOndemand)
+	assert.NoError(t, os.MkdirAll(ondemandDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(ondemandDir, "up_threshold"), []byte("95\n"), 0644))
+	assert.NoError(t, ValidateGovernorTunables(cpuPolicyOndemand, map[string]int{"up_threshold": 80}))
+	err = ValidateGovernorTunables(cpuPolicyOndemand, map[string]int{"io_is_busy": 1})
+	assert.ErrorContains(t, err, "governor ondemand does not expose io_is_busy")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestCpuImpl_governorTunables(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"governor": cpuPolicySchedutil},
+	})()
+	policyDir := filepath.Join(basePath, "cpu0", governorTunablesDir, cpuPolicySchedutil)
+	globalDir := filepath.Join(basePath, governorTunablesDir, cpuPolicyConservative)
+	for dir, file := range map[string]string{policyDir: "rate_limit_us", globalDir: "freq_step"} {
+		assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("2000\n"), 0644))
+	}
+	readTunable := func(path string) string {
+		value, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		return string(value)
+	}
+	cpu := &cpuImpl{id: 0}
+
+	// policy level tunable
+	schedutil := &pstatesImpl{governor: cpuPolicySchedutil, governorTunables: map[string]int{"rate_limit_us": 500}}
+	assert.NoError(t, cpu.restoreGovernorTunables(schedutil))
+	assert.NoError(t, cpu.applyGovernorTunables(schedutil))
+	assert.Equal(t, "500", readTunable(filepath.Join(policyDir, "rate_limit_us")))
+	// reapplying keeps the original value
+	schedutil.governorTunables["rate_limit_us"] = 100
+	assert.NoError(t, cpu.restoreGovernorTunables(schedutil))
+	assert.NoError(t, cpu.applyGovernorTunables(schedutil))
+	assert.Equal(t, "100", readTunable(filepath.Join(policyDir, "rate_limit_us")))
+	assert.Equal(t, "2000", sharedGovernorTunables.tunables[filepath.Join(policyDir, "rate_limit_us")].original)
+
+	// moving to a profile without tunables restores the original value
+	conservative := &pstatesImpl{governor: cpuPolicyConservative, governorTunables: map[string]int{"freq_step": 10}}
+	assert.NoError(t, cpu.restoreGovernorTunables(conservative))
+	assert.Equal(t, "2000", readTunable(filepath.Join(policyDir, "rate_limit_us")))
+
+	// global tunable
+	assert.NoError(t, cpu.applyGovernorTunables(conservative))
+	assert.Equal(t, "10", readTunable(filepath.Join(globalDir, "freq_step")))
+	assert.NoError(t, cpu.restoreGovernorTunables(&pstatesImpl{governor: cpuPolicyPowersave}))
+	assert.Equal(t, "2000", readTunable(filepath.Join(globalDir, "freq_step")))
+	assert.Empty(t, cpu.governorTunables)
+
+	// tunable not exposed by the governor
+	err := cpu.applyGovernorTunables(&pstatesImpl{governor: cpuPolicyOndemand, governorTunables: map[string]int{"up_threshold": 80}})
+	assert.ErrorContains(t, err, "governor ondemand of cpu 0 does not expose up_threshold")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestCpuImpl_sharedGovernorTunables(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"governor": cpuPolicyConservative},
+		"cpu1": {"governor": cpuPolicyConservative},
+		"cpu2": {"governor": cpuPolicyConservative},
+	})()
+	globalDir := filepath.Join(basePath, governorTunablesDir, cpuPolicyConservative)
+	assert.NoError(t, os.MkdirAll(globalDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(globalDir, "freq_step"), []byte("5\n"), 0644))
+	readTunable := func() string {
+		value, err := os.ReadFile(filepath.Join(globalDir, "freq_step"))
+		assert.NoError(t, err)
+		return string(value)
+	}
+	cpus := []*cpuImpl{{id: 0}, {id: 1}, {id: 2}}
+	conservative := &pstatesImpl{governor: cpuPolicyConservative, governorTunables: map[string]int{"freq_step": 10}}
+	powersave := &pstatesImpl{governor: cpuPolicyPowersave}
+
+	// all cpus of the profile share the tunable, the original is read before the first one overrides it
+	var wg sync.WaitGroup
+	for _, cpu := range cpus {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, cpu.applyGovernorTunables(conservative))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "10", readTunable())
+
+	// cpus leaving the profile keep the tunable for the others, the last one restores the original value
+	for _, cpu := range cpus[:2] {
+		assert.NoError(t, cpu.restoreGovernorTunables(powersave))
+		assert.Equal(t, "10", readTunable())
+		assert.Empty(t, cpu.governorTunables)
+	}
+	assert.NoError(t, cpus[2].restoreGovernorTunables(powersave))
+	assert.Equal(t, "5", readTunable())
+	assert.Empty(t, sharedGovernorTunables.tunables)
+
+	// releasing twice does not restore the tunable under the cpus using it again
+	assert.NoError(t, cpus[0].applyGovernorTunables(conservative))
+	assert.NoError(t, cpus[1].applyGovernorTunables(conservative))
+	assert.NoError(t, cpus[0].restoreGovernorTunables(powersave))
+	assert.NoError(t, cpus[0].restoreGovernorTunables(powersave))
+	assert.Equal(t, "10", readTunable())
+	assert.NoError(t, cpus[1].restoreGovernorTunables(powersave))
+	assert.Equal(t, "5", readTunable())
+}
+
+func TestCpuImpl_policyGovernorTunables(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{})()
+	// cpu0 and cpu1 belong to the same policy, their cpufreq directories link to it
+	policyDir := filepath.Join(basePath, governorTunablesDir, "policy0")
+	assert.NoError(t, os.MkdirAll(filepath.Join(policyDir, cpuPolicyConservative), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(policyDir, cpuPolicyConservative, "freq_step"), []byte("5\n"), 0644))
+	for _, cpu := range []string{"cpu0", "cpu1"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(basePath, cpu), os.ModePerm))
+		assert.NoError(t, os.Symlink(filepath.Join("..", governorTunablesDir, "policy0"), filepath.Join(basePath, cpu, governorTunablesDir)))
+	}
+	readTunable := func() string {
+		value, err := os.ReadFile(filepath.Join(policyDir, cpuPolicyConservative, "freq_step"))
+		assert.NoError(t, err)
+		return string(value)
+	}
+	cpus := []*cpuImpl{{id: 0}, {id: 1}}
+	conservative := &pstatesImpl{governor: cpuPolicyConservative, governorTunables: map[string]int{"freq_step": 10}}
+	powersave := &pstatesImpl{governor: cpuPolicyPowersave}
+
+	// the second cpu of the policy does not take the value written for the first one as the original
+	for _, cpu := range cpus {
+		assert.NoError(t, cpu.applyGovernorTunables(conservative))
+	}
+	assert.Equal(t, "10", readTunable())
+	assert.Len(t, sharedGovernorTunables.tunables, 1)
+
+	assert.NoError(t, cpus[0].restoreGovernorTunables(powersave))
+	assert.Equal(t, "10", readTunable())
+	assert.NoError(t, cpus[1].restoreGovernorTunables(powersave))
+	assert.Equal(t, "5", readTunable())
+	assert.Empty(t, sharedGovernorTunables.tunables)
+}
+
+func TestNewPowerProfile_governorTunables(t *testing.T) {
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"governor": cpuPolicyPowersave, "max": "3000", "min": "1000"},
+	})()
+	featureList[CStatesFeature].err = nil
+	defer func() { featureList[CStatesFeature].err = uninitialisedErr }()
+	coreTypes = CoreTypeList{&CpuFrequencySet{min: 1000, max: 3000}}
+	availableGovs = []string{cpuPolicyPowersave, cpuPolicySchedutil}
+
+	profile, err := NewPowerProfile("latency", nil, nil, cpuPolicySchedutil, "", nil, nil, map[string]int{"rate_limit_us": 500})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"rate_limit_us": 500}, profile.GetPStates().GetGovernorTunables())
+
+	profile, err = NewPowerProfile("latency", nil, nil, cpuPolicySchedutil, "", nil, nil, map[string]int{"freq_step": 5})
+	assert.ErrorContains(t, err, "invalid P-states configuration: freq_step is not a tunable of governor schedutil")
+	assert.Nil(t, profile)
+}
@@ -44,6 +44,12 @@ type Host interface {
 	Topology() Topology
 	// returns number of distinct core types
 	NumCoreTypes() uint
+
+	SetIdleInjection(pool Pool, idlePercent uint) error
+	GetIdleInjection() (Pool, uint)
+
+	// GetState returns a serializable snapshot of the host for debugging
+	GetState() HostState
 }
 
 // create a pre-populated Host object
@@ -0,0 +1,266 @@
+package power
+
+import (
+	"slices"
+	"sort"
+)
+
+// HostState is a serializable snapshot of everything the library knows about the host,
+// intended for debugging. It carries json tags and can be marshalled to JSON or YAML
+type HostState struct {
+	Name          string              `json:"name"`
+	Architecture  string              `json:"architecture"`
+	VendorID      string              `json:"vendorID"`
+	Features      []FeatureState      `json:"features"`
+	CoreTypes     []FrequencyRange    `json:"coreTypes,omitempty"`
+	Topology      TopologyState       `json:"topology"`
+	Pools         []PoolState         `json:"pools"`
+	Cpus          []CpuState          `json:"cpus"`
+	IdleInjection *IdleInjectionState `json:"idleInjection,omitempty"`
+}
+
+type FeatureState struct {
+	Name      string `json:"name"`
+	Driver    string `json:"driver,omitempty"`
+	Supported bool   `json:"supported"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FrequencyRange is a min/max frequency pair in kHz
+type FrequencyRange struct {
+	Min uint `json:"min"`
+	Max uint `json:"max"`
+}
+
+type TopologyState struct {
+	Packages     []PackageState     `json:"packages"`
+	NumaNodes    []NumaNodeState    `json:"numaNodes,omitempty"`
+	CacheDomains []CacheDomainState `json:"cacheDomains,omitempty"`
+}
+
+type NumaNodeState struct {
+	ID   uint   `json:"id"`
+	Cpus []uint `json:"cpus"`
+}
+
+type CacheDomainState struct {
+	ID    uint   `json:"id"`
+	Level uint   `json:"level"`
+	Cpus  []uint `json:"cpus"`
+}
+
+type PackageState struct {
+	ID       uint           `json:"id"`
+	Dies     []DieState     `json:"dies,omitempty"`
+	Clusters []ClusterState `json:"clusters,omitempty"`
+}
+
+type DieState struct {
+	ID uint `json:"id"`
+	// Uncore is the uncore frequency range applied to the die, set only if the uncore feature is supported
+	Uncore *FrequencyRange `json:"uncore,omitempty"`
+	Cores  []CoreState     `json:"cores"`
+}
+
+type ClusterState struct {
+	ID    uint        `json:"id"`
+	Cores []CoreState `json:"cores"`
+}
+
+type CoreState struct {
+	ID uint `json:"id"`
+	// Type is the index of the core's frequency range in HostState.CoreTypes
+	Type uint   `json:"type"`
+	Cpus []uint `json:"cpus"`
+}
+
+type PoolState struct {
+	Name      string        `json:"name"`
+	Exclusive bool          `json:"exclusive"`
+	Cpus      []uint        `json:"cpus"`
+	Profile   *ProfileState `json:"profile,omitempty"`
+}
+
+type ProfileState struct {
+	Name         string          `json:"name"`
+	MinFreq      string          `json:"minFreq,omitempty"`
+	MaxFreq      string          `json:"maxFreq,omitempty"`
+	Governor     string          `json:"governor,omitempty"`
+	Epp          string          `json:"epp,omitempty"`
+	CStates      map[string]bool `json:"cStates,omitempty"`
+	MaxLatencyUs *int            `json:"maxLatencyUs,omitempty"`
+}
+
+type CpuState struct {
+	ID   uint   `json:"id"`
+	Pool string `json:"pool"`
+	// Applied holds the values last written by the library, keyed by file relative to the cpu's sysfs directory
+	Applied              map[string]string                          `json:"applied,omitempty"`
+	FrequencyConstraints map[FrequencyRequester]FrequencyConstraint `json:"frequencyConstraints,omitempty"`
+	EffectiveFreqLimits  FrequencyRange                             `json:"effectiveFreqLimits"`
+	// AvailableFrequencies lists the discrete frequencies accepted by the driver, empty if any frequency is accepted
+	AvailableFrequencies []uint `json:"availableFrequencies,omitempty"`
+}
+
+type IdleInjectionState struct {
+	Pool    string `json:"pool"`
+	Percent uint   `json:"percent"`
+}
+
+// GetState returns a snapshot of the host's current state
+func (host *hostImpl) GetState() HostState {
+	state := HostState{
+		Name:         host.name,
+		Architecture: host.architecture,
+		VendorID:     host.vendorId,
+		Features:     []FeatureState{},
+		Pools:        []PoolState{},
+		Cpus:         []CpuState{},
+	}
+
+	features := host.GetFeaturesInfo()
+	ids := make([]featureID, 0, len(features))
+	for id := range features {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		feature := features[id]
+		featureState := FeatureState{Name: feature.Name(), Driver: feature.Driver(), Supported: feature.isSupported()}
+		if feature.err != nil {
+			featureState.Error = feature.err.Error()
+		}
+		state.Features = append(state.Features, featureState)
+	}
+
+	for _, coreType := range host.GetFreqRanges() {
+		state.CoreTypes = append(state.CoreTypes, FrequencyRange{Min: coreType.GetMin(), Max: coreType.GetMax()})
+	}
+
+	// the pools and the pools of the CPUs are read together under the pool mutexes, in their lock
+	// order, so the snapshot is consistent with CPUs being moved
+	pools := PoolList{}
+	for _, pool := range append(PoolList{host.reservedPool, host.sharedPool}, host.exclusivePools...) {
+		if pool != nil {
+			pools = append(pools, pool)
+		}
+	}
+	for _, pool := range pools {
+		pool.poolMutex().Lock()
+	}
+	for _, pool := range pools {
+		state.Pools = append(state.Pools, poolState(pool))
+	}
+	cpuPools := map[uint]string{}
+	if host.topology != nil {
+		for _, cpu := range *host.topology.CPUs() {
+			if pool := cpu.getPool(); pool != nil {
+				cpuPools[cpu.GetID()] = pool.Name()
+			}
+		}
+	}
+	for _, pool := range pools {
+		pool.poolMutex().Unlock()
+	}
+
+	if host.topology != nil {
+		state.Topology = topologyState(host.topology)
+		for _, cpu := range *host.topology.CPUs() {
+			cpuState := cpuState(cpu)
+			cpuState.Pool = cpuPools[cpu.GetID()]
+			state.Cpus = append(state.Cpus, cpuState)
+		}
+		sort.Slice(state.Cpus, func(i, j int) bool { return state.Cpus[i].ID < state.Cpus[j].ID })
+	}
+
+	if pool, percent := host.GetIdleInjection(); pool != nil {
+		state.IdleInjection = &IdleInjectionState{Pool: pool.Name(), Percent: percent}
+	}
+	return state
+}
+
+func topologyState(topology Topology) TopologyState {
+	state := TopologyState{Packages: []PackageState{}}
+	for _, pkg := range *topology.Packages() {
+		pkgState := PackageState{ID: pkg.getID()}
+		for _, die := range *pkg.Dies() {
+			dieState := DieState{ID: die.getID(), Cores: coresState(*die.Cores())}
+			if uncore, ok := die.getEffectiveUncore().(*uncoreFreq); ok && IsFeatureSupported(UncoreFeature) {
+				dieState.Uncore = &FrequencyRange{Min: uncore.min, Max: uncore.max}
+			}
+			pkgState.Dies = append(pkgState.Dies, dieState)
+		}
+		sort.Slice(pkgState.Dies, func(i, j int) bool { return pkgState.Dies[i].ID < pkgState.Dies[j].ID })
+		// clusters are only discovered on aarch64 and are not part of the Package interface
+		if cpuPkg, ok := pkg.(*cpuPackage); ok {
+			for _, cluster := range cpuPkg.clusters {
+				pkgState.Clusters = append(pkgState.Clusters, ClusterState{ID: cluster.getID(), Cores: coresState(*cluster.Cores())})
+			}
+			sort.Slice(pkgState.Clusters, func(i, j int) bool { return pkgState.Clusters[i].ID < pkgState.Clusters[j].ID })
+		}
+		state.Packages = append(state.Packages, pkgState)
+	}
+	sort.Slice(state.Packages, func(i, j int) bool { return state.Packages[i].ID < state.Packages[j].ID })
+
+	for _, node := range *topology.NumaNodes() {
+		cpus := node.CPUs().IDs()
+		slices.Sort(cpus)
+		state.NumaNodes = append(state.NumaNodes, NumaNodeState{ID: node.GetID(), Cpus: cpus})
+	}
+	for _, domain := range *topology.CacheDomains() {
+		cpus := domain.CPUs().IDs()
+		slices.Sort(cpus)
+		state.CacheDomains = append(state.CacheDomains, CacheDomainState{ID: domain.GetID(), Level: domain.Level(), Cpus: cpus})
+	}
+	return state
+}
+
+func coresState(cores []Core) []CoreState {
+	states := make([]CoreState, 0, len(cores))
+	for _, core := range cores {
+		cpus := core.CPUs().IDs()
+		slices.Sort(cpus)
+		states = append(states, CoreState{ID: core.getID(), Type: core.GetType(), Cpus: cpus})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	return states
+}
+
+// poolState returns the state of a pool, the caller holds the pool mutex
+func poolState(pool Pool) PoolState {
+	cpus := pool.Cpus().IDs()
+	slices.Sort(cpus)
+	state := PoolState{Name: pool.Name(), Exclusive: pool.isExclusive(), Cpus: cpus}
+	if profile := pool.GetPowerProfile(); profile != nil {
+		state.Profile = profileState(profile)
+	}
+	return state
+}
+
+func profileState(profile Profile) *ProfileState {
+	state := &ProfileState{Name: profile.Name()}
+	if pstates := profile.GetPStates(); pstates != nil {
+		minFreq, maxFreq := pstates.GetMinFreq(), pstates.GetMaxFreq()
+		state.MinFreq = minFreq.String()
+		state.MaxFreq = maxFreq.String()
+		state.Governor = pstates.GetGovernor()
+		state.Epp = pstates.GetEpp()
+	}
+	if cstates := profile.GetCStates(); cstates != nil {
+		state.CStates = cstates.States()
+		state.MaxLatencyUs = cstates.GetMaxLatencyUs()
+	}
+	return state
+}
+
+// cpuState returns the state of a CPU but its pool, which is read under the pool mutexes
+func cpuState(cpu Cpu) CpuState {
+	state := CpuState{ID: cpu.GetID(), FrequencyConstraints: cpu.GetFrequencyConstraints()}
+	if impl, ok := cpu.(*cpuImpl); ok {
+		state.Applied = impl.writeCache.snapshot()
+	}
+	minFreq, maxFreq := cpu.GetEffectiveFrequencyLimits()
+	state.EffectiveFreqLimits = FrequencyRange{Min: minFreq, Max: maxFreq}
+	state.AvailableFrequencies = cpu.GetAvailableFrequencies()
+	return state
+}
@@ -0,0 +1,119 @@
+package power
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestHostImpl_GetState(t *testing.T) {
+	defer setupFixtureHost(t)()
+	msrReader = fixtureMSRReader{}
+	availFreqs := filepath.Join(basePath, "cpu1", scalingAvailFreqsFile)
+	assert.NoError(t, os.WriteFile(availFreqs, []byte("3500000 3000000 2500000 2000000 800000\n"), 0644))
+
+	host, err := CreateInstance("node1")
+	assert.NotNil(t, host)
+	assert.ErrorContains(t, err, "not available in a fixture")
+
+	assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{0, 1}))
+	pool, err := host.AddExclusivePool("performance")
+	assert.NoError(t, err)
+	profile, err := NewPowerProfile("performance", &intstr.IntOrString{Type: intstr.Int, IntVal: 2000}, &intstr.IntOrString{Type: intstr.Int, IntVal: 3000}, "performance", "performance", map[string]bool{"POLL": true}, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pool.SetPowerProfile(profile))
+	assert.NoError(t, pool.MoveCpuIDs([]uint{1}))
+	assert.NoError(t, host.GetAllCpus().ByID(1).SetFrequencyConstraint(ScalerRequester, FrequencyConstraint{Max: 2500000}))
+	assert.NoError(t, host.SetIdleInjection(host.GetSharedPool(), 10))
+
+	state := host.GetState()
+	assert.Equal(t, "node1", state.Name)
+	assert.Equal(t, "x86_64", state.Architecture)
+	assert.Equal(t, "GenuineIntel", state.VendorID)
+	assert.Len(t, state.Features, len(featureList))
+	assert.Equal(t, FeatureState{Name: "Frequency-Scaling", Driver: "intel_pstate", Supported: true}, state.Features[0])
+	assert.Contains(t, state.Features, FeatureState{
+		Name:   "Effective-Frequency",
+		Driver: "msr",
+		Error:  "failed to read APERF: msr 0xe8 of cpu 0 is not available in a fixture",
+	})
+	assert.Equal(t, []FrequencyRange{{Min: 800000, Max: 3500000}}, state.CoreTypes)
+
+	assert.Equal(t, TopologyState{Packages: []PackageState{{
+		ID: 0,
+		Dies: []DieState{{ID: 0, Cores: []CoreState{
+			{ID: 0, Cpus: []uint{0}},
+			{ID: 1, Cpus: []uint{1}},
+		}}},
+	}},
+		NumaNodes:    []NumaNodeState{{ID: 0, Cpus: []uint{0, 1}}},
+		CacheDomains: []CacheDomainState{{ID: 0, Level: 3, Cpus: []uint{0, 1}}},
+	}, state.Topology)
+
+	assert.Equal(t, []PoolState{
+		{Name: reservedPoolName, Cpus: []uint{}},
+		{Name: sharedPoolName, Cpus: []uint{0}},
+		{Name: "performance", Exclusive: true, Cpus: []uint{1}, Profile: &ProfileState{
+			Name:     "performance",
+			MinFreq:  "2000000",
+			MaxFreq:  "3000000",
+			Governor: "performance",
+			Epp:      "performance",
+			CStates:  map[string]bool{"POLL": true},
+		}},
+	}, state.Pools)
+
+	assert.Len(t, state.Cpus, 2)
+	assert.Equal(t, sharedPoolName, state.Cpus[0].Pool)
+	assert.Equal(t, "performance", state.Cpus[1].Pool)
+	assert.Equal(t, "performance", state.Cpus[1].Applied[scalingGovFile])
+	assert.Equal(t, "2500000", state.Cpus[1].Applied[scalingMaxFile])
+	assert.Equal(t, FrequencyConstraint{Max: 2500000}, state.Cpus[1].FrequencyConstraints[ScalerRequester])
+	assert.Equal(t, FrequencyRange{Min: 2000000, Max: 2500000}, state.Cpus[1].EffectiveFreqLimits)
+	assert.Empty(t, state.Cpus[0].AvailableFrequencies)
+	assert.Equal(t, []uint{800000, 2000000, 2500000, 3000000, 3500000}, state.Cpus[1].AvailableFrequencies)
+	assert.Equal(t, &IdleInjectionState{Pool: sharedPoolName, Percent: 10}, state.IdleInjection)
+
+	data, err := json.Marshal(state)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"scaler":{"max":2500000}`)
+	idleInjection = idleInjectionState{}
+}
+
+func TestHostImpl_GetStateWhileMovingCpus(t *testing.T) {
+	defer setupFixtureHost(t)()
+	msrReader = fixtureMSRReader{}
+	host, _ := CreateInstance("node1")
+	assert.NotNil(t, host)
+	assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{0, 1}))
+	pool, err := host.AddExclusivePool("performance")
+	assert.NoError(t, err)
+
+	// run with -race, the snapshot must not race with the pools being updated
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, pool.MoveCpuIDs([]uint{1}))
+			assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{1}))
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		state := host.GetState()
+		// a CPU is in exactly one pool in any snapshot
+		cpus := []uint{}
+		for _, poolState := range state.Pools {
+			cpus = append(cpus, poolState.Cpus...)
+		}
+		assert.ElementsMatch(t, []uint{0, 1}, cpus)
+	}
+}
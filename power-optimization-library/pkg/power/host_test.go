@@ -15,6 +15,22 @@ type hostMock struct {
 	mock.Mock
 }
 
+func (m *hostMock) SetIdleInjection(pool Pool, idlePercent uint) error {
+	return m.Called(pool, idlePercent).Error(0)
+}
+
+func (m *hostMock) GetIdleInjection() (Pool, uint) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint)
+	}
+	return args.Get(0).(Pool), args.Get(1).(uint)
+}
+
+func (m *hostMock) GetState() HostState {
+	return m.Called().Get(0).(HostState)
+}
+
 func (m *hostMock) Topology() Topology {
 	return m.Called().Get(0).(Topology)
 }
@@ -0,0 +1,185 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	powerclampCoolingType = "intel_powerclamp"
+	powerclampKmodName    = "intel_powerclamp"
+
+	coolingDeviceGlob     = "cooling_device*"
+	coolingTypeFile       = "type"
+	coolingCurStateFile   = "cur_state"
+	coolingMaxStateFile   = "max_state"
+	powerclampCpumaskFile = "cpumask"
+)
+
+var (
+	thermalBasePath      = "/sys/class/thermal"
+	powerclampParamsPath = "/sys/module/intel_powerclamp/parameters"
+
+	// path to the intel_powerclamp cooling device, populated during library initialisation
+	idleInjectionDevice string
+	// maximum idle percentage accepted by the cooling device
+	idleInjectionMaxState uint
+
+	idleInjection = idleInjectionState{}
+)
+
+// idleInjectionState tracks the pool idle injection is applied to. The cooling device is
+// system-wide, so only a single pool can be targeted at a time
+type idleInjectionState struct {
+	mutex       sync.Mutex
+	pool        Pool
+	percent     uint
+	appliedMask string
+}
+
+func initIdleInjection() featureStatus {
+	feature := featureStatus{
+		name:     "Idle-Injection",
+		driver:   powerclampCoolingType,
+		initFunc: initIdleInjection,
+	}
+
+	idleInjectionDevice = findPowerclampDevice()
+	if idleInjectionDevice == "" {
+		feature.err = fmt.Errorf("no %s cooling device found", powerclampCoolingType)
+		return feature
+	}
+
+	// without the cpumask parameter idle would be injected on every CPU, including exclusive ones
+	if _, err := os.Stat(filepath.Join(powerclampParamsPath, powerclampCpumaskFile)); err != nil {
+		feature.err = fmt.Errorf("%s does not support cpumask: %w", powerclampKmodName, err)
+		return feature
+	}
+
+	maxState, err := readUintFromFile(filepath.Join(idleInjectionDevice, coolingMaxStateFile))
+	if err != nil {
+		feature.err = fmt.Errorf("failed to read idle injection max state: %w", err)
+		return feature
+	}
+	idleInjectionMaxState = maxState
+	return feature
+}
+
+// findPowerclampDevice returns the path of the intel_powerclamp cooling device or an empty string
+func findPowerclampDevice() string {
+	devices, _ := filepath.Glob(filepath.Join(thermalBasePath, coolingDeviceGlob))
+	for _, device := range devices {
+		coolingType, err := readStringFromFile(filepath.Join(device, coolingTypeFile))
+		if err == nil && strings.TrimSpace(coolingType) == powerclampCoolingType {
+			return device
+		}
+	}
+	return ""
+}
+
+// GetIdleInjectionMaxPercent returns the highest idle percentage that can be requested
+func GetIdleInjectionMaxPercent() uint {
+	return idleInjectionMaxState
+}
+
+// SetIdleInjection requests idlePercent of forced idle time on all CPUs of pool, the CPUs
+// are kept in sync with the pool's membership. Setting 0 disables idle injection
+func (host *hostImpl) SetIdleInjection(pool Pool, idlePercent uint) error {
+	if !IsFeatureSupported(IdleInjectionFeature) {
+		return featureList.getFeatureIdError(IdleInjectionFeature)
+	}
+	if pool == nil {
+		return newError(ErrInvalidValue, "target pool cannot be nil")
+	}
+	if idlePercent > idleInjectionMaxState {
+		return newError(ErrOutOfRange, "idle percentage %d exceeds maximum of %d", idlePercent, idleInjectionMaxState)
+	}
+
+	idleInjection.mutex.Lock()
+	defer idleInjection.mutex.Unlock()
+
+	if idlePercent == 0 {
+		idleInjection.pool = nil
+		idleInjection.percent = 0
+		return writeIdleInjectionState(0)
+	}
+	idleInjection.pool = pool
+	idleInjection.percent = idlePercent
+	return idleInjection.apply()
+}
+
+// GetIdleInjection returns the pool idle injection is applied to and the requested idle percentage
+func (host *hostImpl) GetIdleInjection() (Pool, uint) {
+	idleInjection.mutex.Lock()
+	defer idleInjection.mutex.Unlock()
+	return idleInjection.pool, idleInjection.percent
+}
+
+// syncIdleInjection re-applies idle injection if any of the pools is the one idle injection
+// is applied to, called whenever pool membership changes. Failures are classified as ErrIdleInjection,
+// as the membership change itself succeeded
+func syncIdleInjection(pools ...Pool) error {
+	idleInjection.mutex.Lock()
+	defer idleInjection.mutex.Unlock()
+	if idleInjection.pool == nil || !slices.Contains(pools, idleInjection.pool) {
+		return nil
+	}
+	if err := idleInjection.apply(); err != nil {
+		return &Error{Kind: ErrIdleInjection, Err: err}
+	}
+	return nil
+}
+
+// must be called with the mutex held, the pool's cpus are read under the pool mutex as
+// membership changes can run concurrently
+func (s *idleInjectionState) apply() error {
+	s.pool.poolMutex().Lock()
+	cpuIDs := s.pool.Cpus().IDs()
+	s.pool.poolMutex().Unlock()
+	if len(cpuIDs) == 0 {
+		s.appliedMask = ""
+		return writeIdleInjectionState(0)
+	}
+	mask := formatCpuMask(cpuIDs)
+	if mask != s.appliedMask {
+		// the kernel rejects cpumask changes while injection is active
+		if err := writeIdleInjectionState(0); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(powerclampParamsPath, powerclampCpumaskFile), []byte(mask), 0644); err != nil {
+			s.appliedMask = ""
+			return fmt.Errorf("failed to set idle injection cpumask: %w", writeError(err))
+		}
+		s.appliedMask = mask
+	}
+	log.V(4).Info("applying idle injection", "pool", s.pool.Name(), "percent", s.percent, "cpumask", mask)
+	return writeIdleInjectionState(s.percent)
+}
+
+func writeIdleInjectionState(percent uint) error {
+	if err := os.WriteFile(filepath.Join(idleInjectionDevice, coolingCurStateFile), []byte(fmt.Sprint(percent)), 0644); err != nil {
+		return fmt.Errorf("failed to set idle injection state: %w", writeError(err))
+	}
+	return nil
+}
+
+// formatCpuMask formats cpu IDs as a bitmap of comma separated 32 bit hex groups,
+// most significant group first, the same format as /proc/irq/*/smp_affinity
+func formatCpuMask(cpuIDs []uint) string {
+	if len(cpuIDs) == 0 {
+		return "0"
+	}
+	groups := make([]uint32, slices.Max(cpuIDs)/32+1)
+	for _, id := range cpuIDs {
+		groups[id/32] |= 1 << (id % 32)
+	}
+	formatted := make([]string, len(groups))
+	for i, group := range groups {
+		formatted[len(groups)-1-i] = fmt.Sprintf("%08x", group)
+	}
+	return strings.Join(formatted, ",")
+}
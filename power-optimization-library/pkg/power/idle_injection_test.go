@@ -0,0 +1,185 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupIdleInjectionTests(t *testing.T, coolingTypes []string, withCpumask bool) func() {
+	origThermalPath := thermalBasePath
+	origParamsPath := powerclampParamsPath
+	origDevice := idleInjectionDevice
+	origMaxState := idleInjectionMaxState
+
+	tmpDir := t.TempDir()
+	thermalBasePath = filepath.Join(tmpDir, "thermal")
+	powerclampParamsPath = filepath.Join(tmpDir, "parameters")
+	for i, coolingType := range coolingTypes {
+		device := filepath.Join(thermalBasePath, fmt.Sprint("cooling_device", i))
+		assert.NoError(t, os.MkdirAll(device, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(device, coolingTypeFile), []byte(coolingType+"\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(device, coolingMaxStateFile), []byte("50\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(device, coolingCurStateFile), []byte("0\n"), 0644))
+	}
+	assert.NoError(t, os.MkdirAll(powerclampParamsPath, os.ModePerm))
+	if withCpumask {
+		assert.NoError(t, os.WriteFile(filepath.Join(powerclampParamsPath, powerclampCpumaskFile), []byte("0\n"), 0644))
+	}
+
+	return func() {
+		thermalBasePath = origThermalPath
+		powerclampParamsPath = origParamsPath
+		idleInjectionDevice = origDevice
+		idleInjectionMaxState = origMaxState
+		idleInjection = idleInjectionState{}
+		featureList[IdleInjectionFeature].err = uninitialisedErr
+	}
+}
+
+func TestInitIdleInjection(t *testing.T) {
+	defer setupIdleInjectionTests(t, []string{"Processor", "intel_powerclamp"}, true)()
+	feature := initIdleInjection()
+	assert.NoError(t, feature.err)
+	assert.Equal(t, filepath.Join(thermalBasePath, "cooling_device1"), idleInjectionDevice)
+	assert.Equal(t, uint(50), GetIdleInjectionMaxPercent())
+}
+
+func TestInitIdleInjection_errors(t *testing.T) {
+	teardown := setupIdleInjectionTests(t, []string{"Processor"}, true)
+	assert.ErrorContains(t, initIdleInjection().err, "no intel_powerclamp cooling device found")
+	teardown()
+
+	teardown = setupIdleInjectionTests(t, []string{"intel_powerclamp"}, false)
+	assert.ErrorContains(t, initIdleInjection().err, "does not support cpumask")
+	teardown()
+}
+
+func TestFormatCpuMask(t *testing.T) {
+	assert.Equal(t, "0", formatCpuMask([]uint{}))
+	assert.Equal(t, "0000000f", formatCpuMask([]uint{0, 1, 2, 3}))
+	assert.Equal(t, "80000000", formatCpuMask([]uint{31}))
+	assert.Equal(t, "00000100,00000000", formatCpuMask([]uint{40}))
+	assert.Equal(t, "00000001,00000000,80000001", formatCpuMask([]uint{64, 31, 0}))
+}
+
+func TestHostImpl_SetIdleInjection(t *testing.T) {
+	defer setupIdleInjectionTests(t, []string{"intel_powerclamp"}, true)()
+	assert.NoError(t, initIdleInjection().err)
+	readState := func() (string, string) {
+		state, _ := readStringFromFile(filepath.Join(idleInjectionDevice, coolingCurStateFile))
+		mask, _ := readStringFromFile(filepath.Join(powerclampParamsPath, powerclampCpumaskFile))
+		return state, mask
+	}
+
+	host := &hostImpl{}
+	pool := &poolImpl{name: "shared", mutex: &sync.Mutex{}, cpus: CpuList{&cpuImpl{id: 2}, &cpuImpl{id: 3}}}
+
+	// feature not supported
+	featureList[IdleInjectionFeature].err = fmt.Errorf("not supported")
+	assert.ErrorContains(t, host.SetIdleInjection(pool, 10), "not supported")
+	featureList[IdleInjectionFeature].err = nil
+
+	assert.ErrorContains(t, host.SetIdleInjection(nil, 10), "cannot be nil")
+	assert.ErrorContains(t, host.SetIdleInjection(pool, 60), "exceeds maximum")
+
+	assert.NoError(t, host.SetIdleInjection(pool, 25))
+	state, mask := readState()
+	assert.Equal(t, "25", state)
+	assert.Equal(t, "0000000c", mask)
+	targetPool, percent := host.GetIdleInjection()
+	assert.Equal(t, Pool(pool), targetPool)
+	assert.Equal(t, uint(25), percent)
+
+	// membership change of an unrelated pool does nothing
+	pool.cpus = append(pool.cpus, &cpuImpl{id: 4})
+	assert.NoError(t, syncIdleInjection(&poolImpl{}))
+	_, mask = readState()
+	assert.Equal(t, "0000000c", mask)
+
+	// membership change of the target pool updates the mask
+	assert.NoError(t, syncIdleInjection(&poolImpl{}, pool))
+	state, mask = readState()
+	assert.Equal(t, "25", state)
+	assert.Equal(t, "0000001c", mask)
+
+	// empty pool stops injection
+	pool.cpus = CpuList{}
+	assert.NoError(t, syncIdleInjection(pool))
+	state, _ = readState()
+	assert.Equal(t, "0", state)
+
+	// disabling
+	pool.cpus = CpuList{&cpuImpl{id: 2}}
+	assert.NoError(t, host.SetIdleInjection(pool, 0))
+	state, _ = readState()
+	assert.Equal(t, "0", state)
+	targetPool, percent = host.GetIdleInjection()
+	assert.Nil(t, targetPool)
+	assert.Equal(t, uint(0), percent)
+}
+
+func TestPool_MoveCpusIdleInjection(t *testing.T) {
+	defer setupFixtureHost(t)()
+	defer func() { idleInjection = idleInjectionState{} }()
+	msrReader = fixtureMSRReader{}
+	host, _ := CreateInstance("node1")
+	assert.NotNil(t, host)
+	assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{0, 1}))
+	pool, err := host.AddExclusivePool("performance")
+	assert.NoError(t, err)
+	assert.NoError(t, host.SetIdleInjection(pool, 10))
+	cpumask := filepath.Join(powerclampParamsPath, powerclampCpumaskFile)
+
+	// idle injection is updated once for the batch, a failed update is reported apart from the moves
+	assert.NoError(t, os.Remove(cpumask))
+	assert.NoError(t, os.Mkdir(cpumask, 0755))
+	err = pool.MoveCpuIDs([]uint{0, 1})
+	assert.ErrorIs(t, err, ErrIdleInjection)
+	assert.ElementsMatch(t, []uint{0, 1}, pool.Cpus().IDs())
+
+	assert.NoError(t, os.Remove(cpumask))
+	assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{1}))
+	mask, _ := readStringFromFile(cpumask)
+	assert.Equal(t, "00000001", mask)
+	state, _ := readStringFromFile(filepath.Join(idleInjectionDevice, coolingCurStateFile))
+	assert.Equal(t, "10", state)
+}
+
+func TestPool_concurrentMovesIdleInjection(t *testing.T) {
+	defer setupFixtureHost(t)()
+	defer func() { idleInjection = idleInjectionState{} }()
+	msrReader = fixtureMSRReader{}
+	host, _ := CreateInstance("node1")
+	assert.NotNil(t, host)
+	assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{0, 1}))
+	pool, err := host.AddExclusivePool("performance")
+	assert.NoError(t, err)
+	assert.NoError(t, host.SetIdleInjection(host.GetSharedPool(), 10))
+
+	// the cpumask is computed while the other cpu changes pools
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, pool.MoveCpuIDs([]uint{0}))
+			assert.NoError(t, host.GetSharedPool().MoveCpuIDs([]uint{0}))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		cpu := host.GetAllCpus().ByID(1)
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, cpu.SetPool(pool))
+			assert.NoError(t, cpu.SetPool(host.GetSharedPool()))
+		}
+	}()
+	wg.Wait()
+	mask, _ := readStringFromFile(filepath.Join(powerclampParamsPath, powerclampCpumaskFile))
+	assert.Equal(t, formatCpuMask(host.GetSharedPool().Cpus().IDs()), mask)
+}
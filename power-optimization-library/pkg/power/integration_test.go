@@ -91,7 +91,7 @@ func doConcurrentMoveCPUSetProfile(t *testing.T) {
 	assert.ElementsMatch(t, *instance.GetReservedPool().Cpus(), *instance.GetAllCpus())
 	assert.Empty(t, *instance.GetSharedPool().Cpus())
 
-	powerProfile, err := NewPowerProfile("pwr", &intstr.IntOrString{Type: intstr.Int, IntVal: 100}, &intstr.IntOrString{Type: intstr.Int, IntVal: 1000}, "performance", "performance", map[string]bool{"C1": true, "C6": false}, nil)
+	powerProfile, err := NewPowerProfile("pwr", &intstr.IntOrString{Type: intstr.Int, IntVal: 100}, &intstr.IntOrString{Type: intstr.Int, IntVal: 1000}, "performance", "performance", map[string]bool{"C1": true, "C6": false}, nil, nil)
 	assert.NoError(t, err)
 
 	moveCoresErrChan := make(chan error)
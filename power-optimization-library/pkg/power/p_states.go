@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -39,10 +38,11 @@ const (
 
 // pstatesImpl is a struct that contains the configurable parameters for the CPU P-states
 type pstatesImpl struct {
-	minFreq  intstr.IntOrString
-	maxFreq  intstr.IntOrString
-	epp      string
-	governor string
+	minFreq          intstr.IntOrString
+	maxFreq          intstr.IntOrString
+	epp              string
+	governor         string
+	governorTunables map[string]int
 }
 
 // PStates provides access to CPU P-state configuration
@@ -51,6 +51,7 @@ type PStates interface {
 	GetMaxFreq() intstr.IntOrString
 	GetGovernor() string
 	GetEpp() string
+	GetGovernorTunables() map[string]int
 }
 
 func (p *pstatesImpl) GetMinFreq() intstr.IntOrString {
@@ -69,6 +70,11 @@ func (p *pstatesImpl) GetEpp() string {
 	return p.epp
 }
 
+// GetGovernorTunables returns the governor's sysfs tunables set by the profile, keyed by attribute name
+func (p *pstatesImpl) GetGovernorTunables() map[string]int {
+	return p.governorTunables
+}
+
 type (
 	CpuFrequencySet struct {
 		min uint
@@ -197,6 +203,7 @@ func GetAvailableGovernors() []string {
 func generateDefaultPStates() error {
 	numCpus := getNumberOfCpus()
 	allCPUDefaultPStatesInfo = make([]pstatesImpl, numCpus)
+	allCPUAvailableFreqs = make([][]uint, numCpus)
 	for cpuID := uint(0); cpuID < numCpus; cpuID++ {
 		cpuInfoMaxFreq, err := readCpuUintProperty(cpuID, cpuMaxFreqFile)
 		if err != nil {
@@ -207,6 +214,11 @@ func generateDefaultPStates() error {
 			return err
 		}
 
+		allCPUAvailableFreqs[cpuID], err = readAvailableFrequencies(cpuID)
+		if err != nil {
+			return err
+		}
+
 		_, err = readCpuStringProperty(cpuID, eppFile)
 		epp := defaultEpp
 		if os.IsNotExist(errors.Unwrap(err)) {
@@ -236,13 +248,17 @@ func (cpu *cpuImpl) updateFrequencies() error {
 
 // setDriverValues is an entrypoint to power governor feature consolidation
 func (cpu *cpuImpl) setDriverValues(pstates PStates) error {
+	if err := cpu.restoreGovernorTunables(pstates); err != nil {
+		return fmt.Errorf("failed to restore governor tunables for cpu %d: %w", cpu.id, err)
+	}
 	if err := cpu.writeGovernorValue(pstates.GetGovernor()); err != nil {
 		return fmt.Errorf("failed to set governor for cpu %d: %w", cpu.id, err)
 	}
-	if pstates.GetEpp() != "" {
-		if err := cpu.writeEppValue(pstates.GetEpp()); err != nil {
-			return fmt.Errorf("failed to set EPP value for cpu %d: %w", cpu.id, err)
-		}
+	if err := cpu.applyGovernorTunables(pstates); err != nil {
+		return fmt.Errorf("failed to set governor tunables for cpu %d: %w", cpu.id, err)
+	}
+	if err := cpu.writeProfileEpp(pstates.GetEpp()); err != nil {
+		return fmt.Errorf("failed to set EPP value for cpu %d: %w", cpu.id, err)
 	}
 	cpuAbsMinFreq, cpuAbsMaxFreq := cpu.GetAbsMinMax()
 	systemMinFreq, systemMaxFreq := coreTypes.getAbsMinMaxFreq()
@@ -263,17 +279,12 @@ func (cpu *cpuImpl) setDriverValues(pstates PStates) error {
 	if maxRequestedFreq > cpuAbsMaxFreq || minRequestedFreq < cpuAbsMinFreq {
 		// If maxFreq and minFreq are not within the system's absolute min and max, then we can't set the values to
 		// the hardware min and max.
-		return fmt.Errorf("setting frequency %d-%d aborted as frequency range is min: %d max: %d. resetting to default",
+		return newError(ErrOutOfRange, "setting frequency %d-%d aborted as frequency range is min: %d max: %d. resetting to default",
 			pstates.GetMinFreq().IntVal, pstates.GetMaxFreq().IntVal, cpuAbsMinFreq, cpuAbsMaxFreq)
 	}
 
-	if err := cpu.writeScalingMaxFreq(maxRequestedFreq); err != nil {
-		return fmt.Errorf("failed to set MaxFreq value for cpu %d: %w", cpu.id, err)
-	}
-	if err := cpu.writeScalingMinFreq(minRequestedFreq); err != nil {
-		return fmt.Errorf("failed to set MinFreq value for cpu %d: %w", cpu.id, err)
-	}
-	return nil
+	// the profile is one of possibly many requesters, the effective limits are written
+	return cpu.setFrequencyConstraint(ProfileRequester, FrequencyConstraint{Min: minRequestedFreq, Max: maxRequestedFreq})
 }
 
 func (cpu *cpuImpl) getFreqsToScale(pstates PStates) (uint, uint, error) {
@@ -282,7 +293,7 @@ func (cpu *cpuImpl) getFreqsToScale(pstates PStates) (uint, uint, error) {
 	// on both ARM and x86.
 	// Update this when the operator will expose E/P cores.
 	if pstates.GetMinFreq().Type != pstates.GetMaxFreq().Type {
-		return 0, 0, fmt.Errorf("min and max frequencies are not of the same type")
+		return 0, 0, newError(ErrInvalidValue, "min and max frequencies are not of the same type")
 	}
 
 	cpuMaxFreq := allCPUDefaultPStatesInfo[cpu.id].maxFreq.IntVal
@@ -324,57 +335,28 @@ func getFreqFromIntOrString(requestedFreq intstr.IntOrString, minFreq, maxFreq u
 }
 
 func (cpu *cpuImpl) writeGovernorValue(governor string) error {
-	return os.WriteFile(filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), scalingGovFile), []byte(governor), 0644)
+	return cpu.writeCpuProperty(scalingGovFile, governor)
 }
 
 func (cpu *cpuImpl) writeEppValue(eppValue string) error {
-	return os.WriteFile(filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), eppFile), []byte(eppValue), 0644)
+	return cpu.writeCpuProperty(eppFile, eppValue)
 }
 
 func (cpu *cpuImpl) writeScalingMaxFreq(freq uint) error {
-	scalingFile := filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), scalingMaxFile)
-	f, err := os.OpenFile(
-		scalingFile,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0644,
-	)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	_, err = f.WriteString(fmt.Sprint(freq))
-	if err != nil {
-		return err
-	}
-	return nil
+	return cpu.writeCpuProperty(scalingMaxFile, fmt.Sprint(freq))
 }
 
 func (cpu *cpuImpl) writeScalingMinFreq(freq uint) error {
-	scalingFile := filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), scalingMinFile)
-	f, err := os.OpenFile(
-		scalingFile,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0644,
-	)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	_, err = f.WriteString(fmt.Sprint(freq))
-	if err != nil {
-		return err
-	}
-	return nil
+	return cpu.writeCpuProperty(scalingMinFile, fmt.Sprint(freq))
 }
 
 // SetCPUFrequency sets the CPU frequency in kHz for the specified CPU using the userspace governor.
+// The frequency is bounded by the effective frequency limits of the CPU and snapped to the nearest
+// available frequency if the driver only accepts discrete values.
 func (cpu *cpuImpl) SetCPUFrequency(frequency uint) error {
-	scalingSetspeedPath := filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), scalingSetSpeedFile)
+	frequency = cpu.SnapFrequency(cpu.clampToEffectiveLimits(frequency))
 	// Write the desired frequency
-	err := os.WriteFile(scalingSetspeedPath, []byte(fmt.Sprintf("%d", frequency)), 0644)
-	if err != nil {
+	if err := cpu.writeCpuProperty(scalingSetSpeedFile, fmt.Sprint(frequency)); err != nil {
 		return fmt.Errorf("failed to set frequency for CPU %d: %w", cpu.id, err)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -16,6 +17,7 @@ func setupCpuScalingTests(cpufiles map[string]map[string]string) func() {
 	origBasePath := basePath
 	basePath = "testing/cpus"
 	allCPUDefaultPStatesInfoCopy := allCPUDefaultPStatesInfo
+	allCPUAvailableFreqsCopy := allCPUAvailableFreqs
 	typeCopy := coreTypes
 	// backup pointer to function that gets all CPUs
 	// replace it with our controlled function
@@ -28,6 +30,7 @@ func setupCpuScalingTests(cpufiles map[string]map[string]string) func() {
 	// Initialize allCPUDefaultPStatesInfo for all CPUs in the map
 	numCpus := len(cpufiles)
 	allCPUDefaultPStatesInfo = make([]pstatesImpl, numCpus)
+	allCPUAvailableFreqs = make([][]uint, numCpus)
 
 	// Set up default P-states info for each CPU
 	for cpuName, cpuDetails := range cpufiles {
@@ -61,6 +64,14 @@ func setupCpuScalingTests(cpufiles map[string]map[string]string) func() {
 		if epp, ok := cpuDetails["epp"]; ok {
 			allCPUDefaultPStatesInfo[cpuID].epp = epp
 		}
+		if freqs, ok := cpuDetails["available_frequencies"]; ok {
+			for _, freq := range strings.Fields(freqs) {
+				if freqInt, err := strconv.Atoi(freq); err == nil {
+					allCPUAvailableFreqs[cpuID] = append(allCPUAvailableFreqs[cpuID], uint(freqInt))
+				}
+			}
+			slices.Sort(allCPUAvailableFreqs[cpuID])
+		}
 	}
 	for cpuName, cpuDetails := range cpufiles {
 		cpudir := filepath.Join(basePath, cpuName)
@@ -87,6 +98,8 @@ func setupCpuScalingTests(cpufiles map[string]map[string]string) func() {
 				os.WriteFile(filepath.Join(cpudir, scalingGovFile), []byte(value+"\n"), 0644)
 			case "available_governors":
 				os.WriteFile(filepath.Join(cpudir, availGovFile), []byte(value+"\n"), 0644)
+			case "available_frequencies":
+				os.WriteFile(filepath.Join(cpudir, scalingAvailFreqsFile), []byte(value+"\n"), 0644)
 			}
 		}
 	}
@@ -97,11 +110,14 @@ func setupCpuScalingTests(cpufiles map[string]map[string]string) func() {
 		basePath = origBasePath
 		// revert get number of system cpus function
 		getNumberOfCpus = origGetNumOfCpusFunc
+		// forget shared governor tunables overridden by the test
+		sharedGovernorTunables.tunables = map[string]*sharedGovernorTunable{}
 		// revert scaling driver feature to un initialised state
 		featureList[FrequencyScalingFeature].err = uninitialisedErr
 		coreTypes = typeCopy
 		// revert default pstates
 		allCPUDefaultPStatesInfo = allCPUDefaultPStatesInfoCopy
+		allCPUAvailableFreqs = allCPUAvailableFreqsCopy
 	}
 }
 
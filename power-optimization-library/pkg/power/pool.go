@@ -1,7 +1,9 @@
 package power
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 )
 
@@ -11,6 +13,8 @@ type poolImpl struct {
 	mutex        sync.Locker
 	host         Host
 	powerProfile Profile
+	// previous APERF/MPERF readings of the pool's CPUs
+	effectiveFreq effectiveFreqBaselines
 }
 
 type Pool interface {
@@ -29,6 +33,8 @@ type Pool interface {
 	SetPowerProfile(profile Profile) error
 	GetPowerProfile() Profile
 
+	GetEffectiveFrequency() (uint, error)
+
 	poolMutex() sync.Locker
 
 	// private interface members
@@ -73,6 +79,19 @@ func (pool *poolImpl) poolMutex() sync.Locker {
 	return pool.mutex
 }
 
+// poolLockRank orders the pool mutexes. Pools are locked reserved first, then shared, then exclusive,
+// so moves in opposite directions and snapshots of all pools cannot deadlock
+func poolLockRank(pool Pool) int {
+	switch {
+	case pool.isExclusive():
+		return 2
+	case pool == pool.getHost().GetSharedPool():
+		return 1
+	default:
+		return 0
+	}
+}
+
 func (pool *poolImpl) SetPowerProfile(profile Profile) error {
 	log.V(4).Info("SetPowerProfile mutex lock", "pool", pool.name)
 	pool.mutex.Lock()
@@ -81,13 +100,9 @@ func (pool *poolImpl) SetPowerProfile(profile Profile) error {
 		pool.mutex.Unlock()
 		log.V(4).Info("SetPowerProfile mutex unlock", "pool", pool.name)
 	}()
-	for _, cpu := range pool.cpus {
-		err := cpu.consolidate()
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return forEachCpuParallel(pool.cpus, func(cpu Cpu) error {
+		return cpu.consolidate()
+	})
 }
 
 func (pool *poolImpl) GetPowerProfile() Profile {
@@ -102,6 +117,34 @@ func (pool *poolImpl) isExclusive() bool {
 	return false
 }
 
+// poolMoves moves CPUs between pools and updates idle injection once for the whole batch
+type poolMoves struct {
+	pools PoolList
+}
+
+// move moves cpu to pool, recording the pools whose membership changed
+func (m *poolMoves) move(cpu Cpu, pool Pool) error {
+	origPool, err := cpu.moveToPool(pool)
+	if err != nil || origPool == nil {
+		return err
+	}
+	for _, changed := range []Pool{origPool, pool} {
+		if !slices.Contains(m.pools, changed) {
+			m.pools = append(m.pools, changed)
+		}
+	}
+	return nil
+}
+
+// done updates idle injection to the moves made, even if the batch stopped at moveErr. A failed update
+// is reported as ErrIdleInjection next to moveErr
+func (m *poolMoves) done(moveErr error) error {
+	if len(m.pools) == 0 {
+		return moveErr
+	}
+	return errors.Join(moveErr, syncIdleInjection(m.pools...))
+}
+
 type sharedPoolType struct {
 	poolImpl
 }
@@ -114,12 +157,13 @@ func (sharedPool *sharedPoolType) MoveCpuIDs(cpuIDs []uint) error {
 	return sharedPool.MoveCpus(cpus)
 }
 func (sharedPool *sharedPoolType) MoveCpus(cpus CpuList) error {
+	moves := poolMoves{}
 	for _, cpu := range cpus {
-		if err := cpu.SetPool(sharedPool); err != nil {
-			return err
+		if err := moves.move(cpu, sharedPool); err != nil {
+			return moves.done(err)
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 func (sharedPool *sharedPoolType) SetCpuIDs(cpuIDs []uint) error {
 	cores, err := sharedPool.host.GetAllCpus().ManyByIDs(cpuIDs)
@@ -132,22 +176,21 @@ func (sharedPool *sharedPoolType) SetCpuIDs(cpuIDs []uint) error {
 // SetCpus on shared pool with place all desired cpus in shared pool
 // undesired cpus that were in the shared pool will be placed in the reserved pool
 func (sharedPool *sharedPoolType) SetCpus(requestedCores CpuList) error {
+	moves := poolMoves{}
 	for _, cpu := range *sharedPool.host.GetAllCpus() {
 		if requestedCores.Contains(cpu) {
-			err := cpu.SetPool(sharedPool)
-			if err != nil {
-				return err
+			if err := moves.move(cpu, sharedPool); err != nil {
+				return moves.done(err)
 			}
 		} else {
 			if cpu.getPool() == sharedPool { // move cpus we don't want if the shared pool to reserved, don't touch any exclusive
-				err := cpu.SetPool(sharedPool.host.GetReservedPool())
-				if err != nil {
-					return err
+				if err := moves.move(cpu, sharedPool.host.GetReservedPool()); err != nil {
+					return moves.done(err)
 				}
 			}
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 
 func (sharedPool *sharedPoolType) Clear() error {
@@ -169,12 +212,13 @@ func (reservedPool *reservedPoolType) MoveCpuIDs(cpuIDs []uint) error {
 	return reservedPool.MoveCpus(cpus)
 }
 func (reservedPool *reservedPoolType) MoveCpus(cpus CpuList) error {
+	moves := poolMoves{}
 	for _, cpu := range cpus {
-		if err := cpu.SetPool(reservedPool); err != nil {
-			return err
+		if err := moves.move(cpu, reservedPool); err != nil {
+			return moves.done(err)
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 func (reservedPool *reservedPoolType) SetCpuIDs(cpuIDs []uint) error {
 	cpus, err := reservedPool.host.GetAllCpus().ManyByIDs(cpuIDs)
@@ -200,27 +244,25 @@ func (reservedPool *reservedPoolType) SetCpus(cores CpuList) error {
 	*/
 
 	sharedPool := reservedPool.host.GetSharedPool()
-
+	moves := poolMoves{}
 	for _, cpu := range *reservedPool.host.GetAllCpus() {
 		if cores.Contains(cpu) { // case 2,4, 6
 			if cpu.getPool().isExclusive() { // case 2
-				return fmt.Errorf("cpus cannot be moved directly from exclusive to reserved pool")
+				return moves.done(fmt.Errorf("cpus cannot be moved directly from exclusive to reserved pool"))
 			}
-			err := cpu.SetPool(reservedPool) // case 4
-			if err != nil {
-				return err
+			if err := moves.move(cpu, reservedPool); err != nil { // case 4
+				return moves.done(err)
 			}
 		} else { // case 1,3,5
 			if cpu.getPool() == reservedPool { // case 5
-				err := cpu.SetPool(sharedPool)
-				if err != nil {
-					return err
+				if err := moves.move(cpu, sharedPool); err != nil {
+					return moves.done(err)
 				}
 			}
 			continue // 1,3 do nothing
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 
 func (reservedPool *reservedPoolType) Remove() error {
@@ -243,12 +285,13 @@ func (pool *exclusivePoolType) MoveCpuIDs(cpuIDs []uint) error {
 	return pool.MoveCpus(cpus)
 }
 func (pool *exclusivePoolType) MoveCpus(cpus CpuList) error {
+	moves := poolMoves{}
 	for _, cpu := range cpus {
-		if err := cpu.SetPool(pool); err != nil {
-			return err
+		if err := moves.move(cpu, pool); err != nil {
+			return moves.done(err)
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 func (pool *exclusivePoolType) SetCpuIDs(cpuIDs []uint) error {
 	cpus, err := pool.host.GetAllCpus().ManyByIDs(cpuIDs)
@@ -259,23 +302,22 @@ func (pool *exclusivePoolType) SetCpuIDs(cpuIDs []uint) error {
 }
 
 func (pool *exclusivePoolType) SetCpus(requestedCores CpuList) error {
+	moves := poolMoves{}
 	for _, cpu := range *pool.host.GetAllCpus() {
 		if requestedCores.Contains(cpu) {
-			err := cpu.SetPool(pool)
-			if err != nil {
-				return err
+			if err := moves.move(cpu, pool); err != nil {
+				return moves.done(err)
 			}
 		} else {
 			if cpu.getPool() != pool {
 				continue
 			}
-			err := cpu.SetPool(pool.host.GetSharedPool())
-			if err != nil {
-				return err
+			if err := moves.move(cpu, pool.host.GetSharedPool()); err != nil {
+				return moves.done(err)
 			}
 		}
 	}
-	return nil
+	return moves.done(nil)
 }
 
 func (pool *exclusivePoolType) Clear() error {
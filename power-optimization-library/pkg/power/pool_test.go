@@ -82,6 +82,11 @@ func (m *poolMock) GetPowerProfile() Profile {
 	return args.(Profile)
 }
 
+func (m *poolMock) GetEffectiveFrequency() (uint, error) {
+	args := m.Called()
+	return args.Get(0).(uint), args.Error(1)
+}
+
 func TestPoolList(t *testing.T) {
 	p1 := new(poolMock)
 	p1.On("Name").Return("pool1")
@@ -143,8 +148,8 @@ func TestExclusivePoolType_MoveCpus(t *testing.T) {
 	mockCore := new(cpuMock)
 	mockCore2 := new(cpuMock)
 	p := new(exclusivePoolType)
-	mockCore.On("SetPool", p).Return(nil)
-	mockCore2.On("SetPool", p).Return(nil)
+	mockCore.On("moveToPool", p).Return(nil, nil)
+	mockCore2.On("moveToPool", p).Return(nil, nil)
 
 	assert.NoError(t, p.MoveCpus(CpuList{mockCore, mockCore2}))
 
@@ -154,7 +159,7 @@ func TestExclusivePoolType_MoveCpus(t *testing.T) {
 	//failed to set
 	setPoolErr := fmt.Errorf("")
 	mockCore = new(cpuMock)
-	mockCore.On("SetPool", p).Return(setPoolErr)
+	mockCore.On("moveToPool", p).Return(nil, setPoolErr)
 
 	assert.ErrorIs(t, p.MoveCpus(CpuList{mockCore}), setPoolErr)
 	mockCore.AssertExpectations(t)
@@ -175,8 +180,8 @@ func TestSharedPoolType_MoveCpus(t *testing.T) {
 	mockCore := new(cpuMock)
 	mockCore2 := new(cpuMock)
 	p := new(sharedPoolType)
-	mockCore.On("SetPool", p).Return(nil)
-	mockCore2.On("SetPool", p).Return(nil)
+	mockCore.On("moveToPool", p).Return(nil, nil)
+	mockCore2.On("moveToPool", p).Return(nil, nil)
 
 	assert.NoError(t, p.MoveCpus(CpuList{mockCore, mockCore2}))
 
@@ -186,7 +191,7 @@ func TestSharedPoolType_MoveCpus(t *testing.T) {
 	//failed to set
 	setPoolErr := fmt.Errorf("")
 	mockCore = new(cpuMock)
-	mockCore.On("SetPool", p).Return(setPoolErr)
+	mockCore.On("moveToPool", p).Return(nil, setPoolErr)
 
 	assert.ErrorIs(t, p.MoveCpus(CpuList{mockCore}), setPoolErr)
 	mockCore.AssertExpectations(t)
@@ -207,8 +212,8 @@ func TestReservedPoolType_MoveCpus(t *testing.T) {
 	mockCore := new(cpuMock)
 	mockCore2 := new(cpuMock)
 	p := new(reservedPoolType)
-	mockCore.On("SetPool", p).Return(nil)
-	mockCore2.On("SetPool", p).Return(nil)
+	mockCore.On("moveToPool", p).Return(nil, nil)
+	mockCore2.On("moveToPool", p).Return(nil, nil)
 
 	assert.NoError(t, p.MoveCpus(CpuList{mockCore, mockCore2}))
 
@@ -218,7 +223,7 @@ func TestReservedPoolType_MoveCpus(t *testing.T) {
 	//failed to set
 	setPoolErr := fmt.Errorf("")
 	mockCore = new(cpuMock)
-	mockCore.On("SetPool", p).Return(setPoolErr)
+	mockCore.On("moveToPool", p).Return(nil, setPoolErr)
 
 	assert.ErrorIs(t, p.MoveCpus(CpuList{mockCore}), setPoolErr)
 	mockCore.AssertExpectations(t)
@@ -290,9 +295,9 @@ func TestSharedPoolType_SetCores(t *testing.T) {
 	for i := range allCores {
 		core := new(cpuMock)
 		if i >= 2 && i < 5 {
-			core.On("SetPool", sharedPool).Return(nil)
+			core.On("moveToPool", sharedPool).Return(nil, nil)
 		} else {
-			core.On("SetPool", reservedPool).Return(nil)
+			core.On("moveToPool", reservedPool).Return(nil, nil)
 			core.On("getPool").Return(sharedPool)
 		}
 		allCores[i] = core
@@ -308,7 +313,7 @@ func TestSharedPoolType_SetCores(t *testing.T) {
 	// setPool error
 	err := fmt.Errorf("borked")
 	allCores[0] = new(cpuMock)
-	allCores[0].(*cpuMock).On("SetPool", mock.Anything).Return(err)
+	allCores[0].(*cpuMock).On("moveToPool", mock.Anything).Return(nil, err)
 	assert.ErrorIs(t, sharedPool.SetCpus(allCores), err)
 
 }
@@ -340,14 +345,14 @@ func TestReservedPoolType_SetCores(t *testing.T) {
 		case 4:
 			core.On("getPool").Return(sharedPool)
 			requestedSetCores.add(core)
-			core.On("SetPool", reservedPool).Return(nil)
+			core.On("moveToPool", reservedPool).Return(nil, nil)
 		case 5:
 			core.On("getPool").Return(reservedPool)
-			core.On("SetPool", sharedPool).Return(nil)
+			core.On("moveToPool", sharedPool).Return(nil, nil)
 		case 6:
 			core.On("getPool").Return(reservedPool)
 			requestedSetCores.add(core)
-			core.On("SetPool", reservedPool).Return(nil)
+			core.On("moveToPool", reservedPool).Return(nil, nil)
 		}
 		allCores.add(core)
 	}
@@ -377,11 +382,11 @@ func TestExclusivePoolType_SetCores(t *testing.T) {
 		switch i {
 		case 0:
 			core.On("getPool").Return(exclusivePool)
-			core.On("SetPool", sharedPool).Return(nil)
+			core.On("moveToPool", sharedPool).Return(nil, nil)
 		case 1:
 			core.On("getPool").Return(sharedPool)
 		case 2:
-			core.On("SetPool", exclusivePool).Return(nil)
+			core.On("moveToPool", exclusivePool).Return(nil, nil)
 		}
 
 		allCores[i] = core
@@ -397,7 +402,7 @@ func TestExclusivePoolType_SetCores(t *testing.T) {
 	// setPool error
 	err := fmt.Errorf("borked")
 	allCores[0] = new(cpuMock)
-	allCores[0].(*cpuMock).On("SetPool", mock.Anything).Return(err)
+	allCores[0].(*cpuMock).On("moveToPool", mock.Anything).Return(nil, err)
 	assert.ErrorIs(t, exclusivePool.SetCpus(CpuList{allCores[0]}), err)
 }
 
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -23,12 +24,25 @@ const (
 	EPPFeature
 	CStatesFeature
 	UncoreFeature
+	EffectiveFrequencyFeature
+	IdleInjectionFeature
 )
 
 type LibConfig struct {
 	CpuPath    string
 	ModulePath string
 	Cores      uint
+	// WriteVerifyPeriod is the maximum time an unchanged sysfs value is skipped before
+	// being written again. Negative value disables write elision, zero keeps the default
+	WriteVerifyPeriod time.Duration
+	// MaxParallelWrites bounds the number of CPUs configured concurrently, zero keeps the default
+	MaxParallelWrites uint
+	// ThermalPath and PowerclampParamsPath locate the idle injection cooling device and its parameters
+	ThermalPath          string
+	PowerclampParamsPath string
+	// FixturePath runs the library against a fixture produced by CaptureFixture instead of the host, either the
+	// tarball or a directory it was extracted to. Cores defaults to the number of CPUs in the fixture
+	FixturePath string
 }
 
 // initialized with null logger, can be set to proper logger with SetLogger
@@ -52,9 +66,17 @@ var featureList FeatureSet = map[featureID]*featureStatus{
 		err:      uninitialisedErr,
 		initFunc: initUncore,
 	},
+	EffectiveFrequencyFeature: {
+		err:      uninitialisedErr,
+		initFunc: initEffectiveFrequency,
+	},
+	IdleInjectionFeature: {
+		err:      uninitialisedErr,
+		initFunc: initIdleInjection,
+	},
 }
 var uninitialisedErr = fmt.Errorf("feature uninitialized")
-var undefinederr = fmt.Errorf("feature undefined")
+var undefinederr = newError(ErrUnsupported, "feature undefined")
 
 // featureStatus stores feature name, driver and if feature is not supported, error describing the reason
 type featureStatus struct {
@@ -120,7 +142,10 @@ func (set *FeatureSet) getFeatureIdError(id featureID) error {
 	if !exists {
 		return undefinederr
 	}
-	return feature.err
+	if feature.err == nil || errors.Is(feature.err, ErrUnsupported) {
+		return feature.err
+	}
+	return &Error{Kind: ErrUnsupported, Err: feature.err}
 }
 
 // CreateInstance initialises the power library
@@ -140,12 +165,29 @@ func CreateInstance(hostName string) (Host, error) {
 	return host, allErrors
 }
 func CreateInstanceWithConf(hostname string, conf LibConfig) (Host, error) {
+	if conf.FixturePath != "" {
+		if err := loadFixture(&conf); err != nil {
+			return nil, err
+		}
+	}
 	if conf.CpuPath != "" {
 		basePath = conf.CpuPath
 	}
 	if conf.ModulePath != "" {
 		kernelModulesFilePath = conf.ModulePath
 	}
+	if conf.WriteVerifyPeriod != 0 {
+		writeVerifyPeriod = conf.WriteVerifyPeriod
+	}
+	if conf.MaxParallelWrites != 0 {
+		maxParallelWrites = conf.MaxParallelWrites
+	}
+	if conf.ThermalPath != "" {
+		thermalBasePath = conf.ThermalPath
+	}
+	if conf.PowerclampParamsPath != "" {
+		powerclampParamsPath = conf.PowerclampParamsPath
+	}
 	getNumberOfCpus = func() uint { return conf.Cores }
 	return CreateInstance(hostname)
 }
@@ -37,7 +37,8 @@ func (p *profileImpl) GetCStates() CStates {
 
 // NewPowerProfile creates a new power profile with both P-states and C-states configuration
 // C-states can be configured either with explicit names or latency-based filtering
-func NewPowerProfile(name string, minFreq, maxFreq *intstr.IntOrString, governor, epp string, cstates map[string]bool, maxLatencyUs *int) (Profile, error) {
+// governorTunables optionally sets sysfs tunables of the governor, e.g. rate_limit_us of schedutil
+func NewPowerProfile(name string, minFreq, maxFreq *intstr.IntOrString, governor, epp string, cstates map[string]bool, maxLatencyUs *int, governorTunables map[string]int) (Profile, error) {
 	if !featureList.isFeatureIdSupported(FrequencyScalingFeature) {
 		return nil, featureList.getFeatureIdError(FrequencyScalingFeature)
 	}
@@ -51,6 +52,10 @@ func NewPowerProfile(name string, minFreq, maxFreq *intstr.IntOrString, governor
 		return nil, fmt.Errorf("invalid P-states configuration: %w", err)
 	}
 
+	if err := ValidateGovernorTunables(governor, governorTunables); err != nil {
+		return nil, fmt.Errorf("invalid P-states configuration: %w", err)
+	}
+
 	if !featureList.isFeatureIdSupported(CStatesFeature) {
 		return nil, featureList.getFeatureIdError(CStatesFeature)
 	}
@@ -69,10 +74,11 @@ func NewPowerProfile(name string, minFreq, maxFreq *intstr.IntOrString, governor
 	return &profileImpl{
 		name: name,
 		pstates: &pstatesImpl{
-			maxFreq:  finalMaxFreq,
-			minFreq:  finalMinFreq,
-			epp:      epp,
-			governor: governor,
+			maxFreq:          finalMaxFreq,
+			minFreq:          finalMinFreq,
+			epp:              epp,
+			governor:         governor,
+			governorTunables: governorTunables,
 		},
 		cstates: cstatesImpl{states: cstates, maxLatencyUs: maxLatencyUs},
 	}, nil
@@ -136,14 +142,14 @@ func ValidatePStates(minFreq, maxFreq intstr.IntOrString, governor, epp string)
 	switch minFreq.Type {
 	case intstr.Int:
 		if minFreq.IntVal < 0 {
-			return fmt.Errorf("min frequency must be a non-negative integer, got %d", minFreq.IntVal)
+			return newError(ErrInvalidValue, "min frequency must be a non-negative integer, got %d", minFreq.IntVal)
 		}
 		if maxFreq.IntVal < minFreq.IntVal {
-			return fmt.Errorf("max frequency (%d) cannot be lower than the min frequency (%d)", maxFreq.IntVal, minFreq.IntVal)
+			return newError(ErrInvalidValue, "max frequency (%d) cannot be lower than the min frequency (%d)", maxFreq.IntVal, minFreq.IntVal)
 		}
 		// Validate the min and max frequency values against the absolute minimum and maximum frequency of the system.
 		if minFreq.IntVal < int32(absoluteMinimumFrequency/1000) || maxFreq.IntVal > int32(absoluteMaximumFrequency/1000) {
-			return fmt.Errorf("max and min frequency must be within the range %d-%d", absoluteMinimumFrequency, absoluteMaximumFrequency)
+			return newError(ErrOutOfRange, "max and min frequency must be within the range %d-%d", absoluteMinimumFrequency, absoluteMaximumFrequency)
 		}
 	case intstr.String:
 		// Parse the min and max frequency values from the string.
@@ -159,7 +165,7 @@ func ValidatePStates(minFreq, maxFreq intstr.IntOrString, governor, epp string)
 		}
 		// Validate the min and max frequency values against each other.
 		if maxFreqValInt < minFreqValInt {
-			return fmt.Errorf("max frequency (%s) cannot be lower than the min frequency (%s)", maxFreq.StrVal, minFreq.StrVal)
+			return newError(ErrInvalidValue, "max frequency (%s) cannot be lower than the min frequency (%s)", maxFreq.StrVal, minFreq.StrVal)
 		}
 	default:
 		return errors.NewServiceUnavailable("max and min frequency must be either Int or String")
@@ -169,11 +175,11 @@ func ValidatePStates(minFreq, maxFreq intstr.IntOrString, governor, epp string)
 		governor = defaultGovernor
 	}
 	if !checkGov(governor) {
-		return fmt.Errorf("governor %s is not supported, please use one of the following: %v", governor, availableGovs)
+		return newError(ErrUnsupported, "governor %s is not supported, please use one of the following: %v", governor, availableGovs)
 	}
 
 	if epp != "" && governor == cpuPolicyPerformance && epp != cpuPolicyPerformance {
-		return fmt.Errorf("'%s' epp can be used with '%s' governor", cpuPolicyPerformance, cpuPolicyPerformance)
+		return newError(ErrInvalidValue, "'%s' epp can be used with '%s' governor", cpuPolicyPerformance, cpuPolicyPerformance)
 	}
 
 	return nil
@@ -182,15 +188,15 @@ func ValidatePStates(minFreq, maxFreq intstr.IntOrString, governor, epp string)
 // ValidateCStates validates a new C-states configuration
 func ValidateCStates(states map[string]bool, maxLatencyUs *int) error {
 	if len(states) > 0 && maxLatencyUs != nil {
-		return fmt.Errorf("cannot specify both explicit C-state names and latency-based configuration")
+		return newError(ErrInvalidValue, "cannot specify both explicit C-state names and latency-based configuration")
 	}
 
 	if maxLatencyUs != nil && *maxLatencyUs < 0 {
-		return fmt.Errorf("maxLatencyUs must be a non-negative integer, got %d", *maxLatencyUs)
+		return newError(ErrInvalidValue, "maxLatencyUs must be a non-negative integer, got %d", *maxLatencyUs)
 	} else if len(states) > 0 {
 		for name := range states {
 			if !slices.Contains(GetAvailableCStates(), name) {
-				return fmt.Errorf("c-state %s does not exist on this system", name)
+				return newError(ErrUnsupported, "c-state %s does not exist on this system", name)
 			}
 		}
 	}
@@ -32,6 +32,7 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{},
 		nil,
+		nil,
 	)
 	assert.ErrorIs(t, err, uninitialisedErr)
 	assert.Nil(t, profile)
@@ -52,6 +53,7 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{"C1": true, "C6": false},
 		nil,
+		nil,
 	)
 	assert.NoError(t, err)
 	assert.Equal(t, "name", profile.Name())
@@ -71,6 +73,7 @@ func TestNewProfile(t *testing.T) {
 		cpuPolicyPerformance,
 		nil,
 		&maxLatency,
+		nil,
 	)
 	assert.NoError(t, err)
 	assert.Equal(t, "name", profile.Name())
@@ -85,6 +88,7 @@ func TestNewProfile(t *testing.T) {
 		"name", nil,
 		&intstr.IntOrString{Type: intstr.Int, IntVal: 100},
 		cpuPolicyPerformance, "epp", map[string]bool{}, nil,
+		nil,
 	)
 	assert.ErrorContains(t, err, fmt.Sprintf("'%s' epp can be used with '%s' governor", cpuPolicyPerformance, cpuPolicyPerformance))
 	assert.Nil(t, profile)
@@ -98,8 +102,10 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{},
 		nil,
+		nil,
 	)
 	assert.ErrorContains(t, err, "max frequency (10) cannot be lower than the min frequency (100)")
+	assert.ErrorIs(t, err, ErrInvalidValue)
 	assert.Nil(t, profile)
 
 	// Max frequency cannot be lower than the min frequency - percentages.
@@ -111,6 +117,7 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{},
 		nil,
+		nil,
 	)
 	assert.ErrorContains(t, err, "max frequency (80%) cannot be lower than the min frequency (95%)")
 	assert.Nil(t, profile)
@@ -123,6 +130,7 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{},
 		nil,
+		nil,
 	)
 	assert.ErrorContains(t, err, "governor something random is not supported, please use one of the following")
 	assert.Nil(t, profile)
@@ -135,6 +143,7 @@ func TestNewProfile(t *testing.T) {
 		"epp",
 		map[string]bool{"C7": true},
 		nil,
+		nil,
 	)
 	assert.ErrorContains(t, err, "c-state C7 does not exist on this system")
 	assert.Nil(t, profile)
@@ -1,6 +1,12 @@
 package power
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 const (
 	cpuTopologyDir = "topology/"
@@ -8,6 +14,13 @@ const (
 	dieIdFile      = cpuTopologyDir + "die_id"
 	coreIdFile     = cpuTopologyDir + "core_id"
 	clusterIdFile  = cpuTopologyDir + "cluster_id"
+
+	numaNodeGlob        = "node[0-9]*"
+	cacheIndexGlob      = "cache/index[0-9]*"
+	cacheLevelFile      = "level"
+	cacheTypeFile       = "type"
+	cacheIdFile         = "id"
+	cacheSharedCpusFile = "shared_cpu_list"
 )
 
 type topologyTypeObj interface {
@@ -27,6 +40,8 @@ type (
 		allCpus      CpuList
 		uncore       Uncore
 		architecture string
+		numaNodes    numaNodeList
+		cacheDomains cacheDomainList
 	}
 
 	Topology interface {
@@ -35,6 +50,10 @@ type (
 		getArchitecture() string
 		Packages() *[]Package
 		Package(id uint) Package
+		NumaNodes() *[]NumaNode
+		NumaNode(id uint) NumaNode
+		CacheDomains() *[]CacheDomain
+		CacheDomain(id uint) CacheDomain
 	}
 )
 
@@ -97,6 +116,36 @@ func (s *cpuTopology) getID() uint {
 	return 0
 }
 
+// NumaNodes returns the NUMA nodes sorted by id, empty if the kernel exposes no NUMA information
+func (s *cpuTopology) NumaNodes() *[]NumaNode {
+	nodes := make([]NumaNode, 0, len(s.numaNodes))
+	for _, node := range s.numaNodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].GetID() < nodes[j].GetID() })
+	return &nodes
+}
+
+func (s *cpuTopology) NumaNode(id uint) NumaNode {
+	node := s.numaNodes[id]
+	return node
+}
+
+// CacheDomains returns the last level cache domains sorted by id
+func (s *cpuTopology) CacheDomains() *[]CacheDomain {
+	domains := make([]CacheDomain, 0, len(s.cacheDomains))
+	for _, domain := range s.cacheDomains {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].GetID() < domains[j].GetID() })
+	return &domains
+}
+
+func (s *cpuTopology) CacheDomain(id uint) CacheDomain {
+	domain := s.cacheDomains[id]
+	return domain
+}
+
 // cpu socket represents a physical cpu package
 type (
 	cpuPackage struct {
@@ -361,8 +410,61 @@ func (c *cpuCore) getID() uint {
 	return c.id
 }
 
+// NUMA nodes and cache domains cut across the package/die/core tree, e.g. with sub-NUMA clustering a package
+// holds several NUMA nodes each with its own LLC, so they hold references to cpus discovered by the tree
+type (
+	numaNode struct {
+		id   uint
+		cpus CpuList
+	}
+	// NumaNode is a set of cpus sharing a memory controller, as linked by /sys/devices/system/cpu/cpuN/nodeM
+	NumaNode interface {
+		GetID() uint
+		CPUs() *CpuList
+	}
+)
+
+func (n *numaNode) GetID() uint {
+	return n.id
+}
+
+func (n *numaNode) CPUs() *CpuList {
+	return &n.cpus
+}
+
+type (
+	cacheDomain struct {
+		id    uint
+		level uint
+		cpus  CpuList
+	}
+	// CacheDomain is a set of cpus sharing the last level cache
+	CacheDomain interface {
+		GetID() uint
+		// Level returns the cache level, usually 3
+		Level() uint
+		CPUs() *CpuList
+	}
+)
+
+func (c *cacheDomain) GetID() uint {
+	return c.id
+}
+
+func (c *cacheDomain) Level() uint {
+	return c.level
+}
+
+func (c *cacheDomain) CPUs() *CpuList {
+	return &c.cpus
+}
+
 type packageList map[uint]Package
 
+type numaNodeList map[uint]NumaNode
+
+type cacheDomainList map[uint]CacheDomain
+
 type dieList map[uint]Die
 
 type clusterList map[uint]Cluster
@@ -376,11 +478,76 @@ var discoverTopology = func(arch string) (Topology, error) {
 		packages:     packageList{},
 		uncore:       defaultUncore,
 		architecture: arch,
+		numaNodes:    numaNodeList{},
+		cacheDomains: cacheDomainList{},
 	}
 	for i := uint(0); i < numOfCores; i++ {
 		if _, err := topology.addCpu(i); err != nil {
 			return nil, err
 		}
 	}
+	// NUMA and cache information is optional, cpus without it are not part of any node or domain
+	for _, cpu := range topology.allCpus {
+		if nodeID, ok := readCpuNumaNode(cpu.GetID()); ok {
+			if _, exists := topology.numaNodes[nodeID]; !exists {
+				topology.numaNodes[nodeID] = &numaNode{id: nodeID, cpus: CpuList{}}
+			}
+			topology.numaNodes[nodeID].(*numaNode).cpus.add(cpu)
+		}
+		if domainID, level, ok := readCpuLLC(cpu.GetID()); ok {
+			if _, exists := topology.cacheDomains[domainID]; !exists {
+				topology.cacheDomains[domainID] = &cacheDomain{id: domainID, level: level, cpus: CpuList{}}
+			}
+			topology.cacheDomains[domainID].(*cacheDomain).cpus.add(cpu)
+		}
+	}
 	return topology, nil
 }
+
+// readCpuNumaNode returns the NUMA node a cpu belongs to, based on the cpuN/nodeM link
+func readCpuNumaNode(cpuID uint) (uint, bool) {
+	nodes, _ := filepath.Glob(filepath.Join(basePath, fmt.Sprint("cpu", cpuID), numaNodeGlob))
+	for _, node := range nodes {
+		if id, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(node), "node"), 10, 32); err == nil {
+			return uint(id), true
+		}
+	}
+	return 0, false
+}
+
+// readCpuLLC returns the id and level of the highest level data or unified cache of a cpu. The id comes from
+// cache/indexN/id, or the first cpu of shared_cpu_list on kernels not exposing cache ids
+func readCpuLLC(cpuID uint) (uint, uint, bool) {
+	indexes, _ := filepath.Glob(filepath.Join(basePath, fmt.Sprint("cpu", cpuID), cacheIndexGlob))
+	var llcIndex string
+	var llcLevel uint
+	for _, index := range indexes {
+		if cacheType, err := readStringFromFile(filepath.Join(index, cacheTypeFile)); err != nil ||
+			strings.TrimSpace(cacheType) == "Instruction" {
+			continue
+		}
+		level, err := readUintFromFile(filepath.Join(index, cacheLevelFile))
+		if err == nil && level > llcLevel {
+			llcIndex, llcLevel = index, level
+		}
+	}
+	if llcIndex == "" {
+		return 0, 0, false
+	}
+	if id, err := readUintFromFile(filepath.Join(llcIndex, cacheIdFile)); err == nil {
+		return id, llcLevel, true
+	}
+	sharedCpus, err := readStringFromFile(filepath.Join(llcIndex, cacheSharedCpusFile))
+	if err != nil {
+		return 0, 0, false
+	}
+	first := strings.FieldsFunc(strings.TrimSpace(sharedCpus), func(r rune) bool { return r == ',' || r == '-' })
+	if len(first) == 0 {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(first[0], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint(id), llcLevel, true
+}
@@ -1,6 +1,7 @@
 package power
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -90,6 +91,46 @@ func (m *mockCpuTopology) Package(id uint) Package {
 	return r0
 }
 
+func (m *mockCpuTopology) NumaNodes() *[]NumaNode {
+	ret := m.Called()
+
+	var r0 *[]NumaNode
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*[]NumaNode)
+	}
+	return r0
+}
+
+func (m *mockCpuTopology) NumaNode(id uint) NumaNode {
+	ret := m.Called(id)
+
+	var r0 NumaNode
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(NumaNode)
+	}
+	return r0
+}
+
+func (m *mockCpuTopology) CacheDomains() *[]CacheDomain {
+	ret := m.Called()
+
+	var r0 *[]CacheDomain
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*[]CacheDomain)
+	}
+	return r0
+}
+
+func (m *mockCpuTopology) CacheDomain(id uint) CacheDomain {
+	ret := m.Called(id)
+
+	var r0 CacheDomain
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(CacheDomain)
+	}
+	return r0
+}
+
 type mockCpuPackage struct {
 	mock.Mock
 }
@@ -448,6 +489,66 @@ func (s *topologyTestSuite) TestCpuImpl_discoverTopology() {
 	assert.ElementsMatch(t, topologyObj.packages[1].(*cpuPackage).dies[0].(*cpuDie).cores[1].(*cpuCore).cpus.IDs(), []uint{3, 7})
 }
 
+func (s *topologyTestSuite) TestCpuImpl_discoverNumaAndCache() {
+	t := s.T()
+	// 1 package with sub-NUMA clustering, cpus 0,1 are in node 0 and cpus 2,3 in node 1 each with its own L3
+	cpus := map[string]map[string]string{}
+	for i := 0; i < 4; i++ {
+		cpus[fmt.Sprint("cpu", i)] = map[string]string{"pkg": "0", "die": "0", "core": fmt.Sprint(i)}
+	}
+	defer setupTopologyTest(cpus)()
+
+	writeCache := func(cpu string, index string, files map[string]string) {
+		dir := filepath.Join(basePath, cpu, "cache", index)
+		assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+		for file, value := range files {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(value+"\n"), 0644))
+		}
+	}
+	for i := 0; i < 4; i++ {
+		cpu := fmt.Sprint("cpu", i)
+		assert.NoError(t, os.MkdirAll(filepath.Join(basePath, cpu, fmt.Sprint("node", i/2)), os.ModePerm))
+		writeCache(cpu, "index0", map[string]string{"level": "1", "type": "Data", "id": fmt.Sprint(i)})
+		writeCache(cpu, "index1", map[string]string{"level": "1", "type": "Instruction", "id": fmt.Sprint(i)})
+		writeCache(cpu, "index2", map[string]string{"level": "2", "type": "Unified", "id": fmt.Sprint(i)})
+		if i < 2 {
+			writeCache(cpu, "index3", map[string]string{"level": "3", "type": "Unified", "id": "0", "shared_cpu_list": "0-1"})
+		} else {
+			// no cache id, the domain is named after the first cpu sharing the cache
+			writeCache(cpu, "index3", map[string]string{"level": "3", "type": "Unified", "shared_cpu_list": "2-3"})
+		}
+	}
+
+	topology, err := discoverTopology("x86_64")
+	assert.NoError(t, err)
+
+	nodes := *topology.NumaNodes()
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, uint(0), nodes[0].GetID())
+	assert.ElementsMatch(t, []uint{0, 1}, nodes[0].CPUs().IDs())
+	assert.ElementsMatch(t, []uint{2, 3}, topology.NumaNode(1).CPUs().IDs())
+	assert.Nil(t, topology.NumaNode(2))
+	// cpus are shared with the package tree
+	assert.Equal(t, topology.Package(0).CPUs().ByID(2), topology.NumaNode(1).CPUs().ByID(2))
+
+	domains := *topology.CacheDomains()
+	assert.Len(t, domains, 2)
+	assert.Equal(t, uint(3), domains[0].Level())
+	assert.ElementsMatch(t, []uint{0, 1}, topology.CacheDomain(0).CPUs().IDs())
+	assert.ElementsMatch(t, []uint{2, 3}, topology.CacheDomain(2).CPUs().IDs())
+	assert.Nil(t, topology.CacheDomain(1))
+}
+
+func (s *topologyTestSuite) TestCpuImpl_discoverTopologyWithoutNuma() {
+	t := s.T()
+	defer setupTopologyTest(map[string]map[string]string{"cpu0": {"pkg": "0", "die": "0", "core": "0"}})()
+
+	topology, err := discoverTopology("x86_64")
+	assert.NoError(t, err)
+	assert.Empty(t, *topology.NumaNodes())
+	assert.Empty(t, *topology.CacheDomains())
+}
+
 func (s *topologyTestSuite) TestSystemTopology_Getters() {
 	cpus := make(CpuList, 2)
 	cpus[0] = new(cpuMock)
@@ -34,13 +34,13 @@ func NewUncore(minFreq uint, maxFreq uint) (Uncore, error) {
 		return nil, featureList.getFeatureIdError(UncoreFeature)
 	}
 	if minFreq < defaultUncore.min {
-		return nil, fmt.Errorf("specified Min frequency is lower than %d kHZ allowed by the hardware", defaultUncore.min)
+		return nil, newError(ErrOutOfRange, "specified Min frequency is lower than %d kHZ allowed by the hardware", defaultUncore.min)
 	}
 	if maxFreq > defaultUncore.max {
-		return nil, fmt.Errorf("specified Max frequency is higher than %d kHz allowed by the hardware", defaultUncore.max)
+		return nil, newError(ErrOutOfRange, "specified Max frequency is higher than %d kHz allowed by the hardware", defaultUncore.max)
 	}
 	if maxFreq < minFreq {
-		return nil, fmt.Errorf("max freq cannot be lower than min")
+		return nil, newError(ErrInvalidValue, "max freq cannot be lower than min")
 	}
 
 	normalizedMin := normalizeUncoreFreq(minFreq)
@@ -60,14 +60,14 @@ func (u *uncoreFreq) write(pkgId, dieId uint) error {
 		[]byte(fmt.Sprint(u.max)),
 		0644,
 	); err != nil {
-		return err
+		return writeError(err)
 	}
 	if err := os.WriteFile(
 		path.Join(basePath, fmt.Sprintf(uncorePathFmt, pkgId, dieId), uncoreMinFreqFile),
 		[]byte(fmt.Sprint(u.min)),
 		0644,
 	); err != nil {
-		return err
+		return writeError(err)
 	}
 	return nil
 }
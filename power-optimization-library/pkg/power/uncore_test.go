@@ -151,16 +151,19 @@ func TestNewUncore(t *testing.T) {
 	ucre, err = NewUncore(1_400_000, 9999999)
 	assert.Nil(t, ucre)
 	assert.ErrorContains(t, err, "Max frequency is higher than")
+	assert.ErrorIs(t, err, ErrOutOfRange)
 
 	// min too low
 	ucre, err = NewUncore(100, 2_200_000)
 	assert.Nil(t, ucre)
 	assert.ErrorContains(t, err, "Min frequency is lower than")
+	assert.ErrorIs(t, err, ErrOutOfRange)
 
 	//uncore not supported
 	featureList[UncoreFeature].err = fmt.Errorf("uncore borked")
 	ucre, err = NewUncore(1_400_000, 2_200_000)
 	assert.ErrorIs(t, err, featureList[UncoreFeature].err)
+	assert.ErrorIs(t, err, ErrUnsupported)
 }
 
 func TestUncoreFreq_write(t *testing.T) {
@@ -188,6 +191,7 @@ func TestUncoreFreq_write(t *testing.T) {
 	// write to non-existing file
 	err = uncore.write(2, 3)
 	assert.ErrorContains(t, err, "no such file or directory")
+	assert.ErrorIs(t, err, ErrUnsupported)
 }
 
 func TestCpuTopology_SetUncoreFrequency(t *testing.T) {
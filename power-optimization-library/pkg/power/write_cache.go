@@ -0,0 +1,143 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const defaultWriteVerifyPeriod = 5 * time.Minute
+
+var (
+	// writeVerifyPeriod is the maximum age of a cached value, after which the next
+	// write is performed even if the value did not change. This catches values that
+	// were changed behind the library's back (other tools, driver resets, hotplug).
+	writeVerifyPeriod = defaultWriteVerifyPeriod
+	// maxParallelWrites bounds the number of CPUs consolidated concurrently
+	maxParallelWrites = uint(runtime.NumCPU())
+
+	// defined as var so can be mocked by the unit test
+	timeNow = time.Now
+)
+
+type cachedWrite struct {
+	value   string
+	written time.Time
+}
+
+// writeCache remembers the last value successfully written to each sysfs file of a CPU,
+// allowing no-op writes to be skipped. A nil writeCache disables elision.
+type writeCache struct {
+	mutex  sync.Mutex
+	values map[string]cachedWrite // file relative to cpu directory -> last written value
+}
+
+func newWriteCache() *writeCache {
+	return &writeCache{values: map[string]cachedWrite{}}
+}
+
+// isFresh returns true if value was the last value written to file and the entry
+// has not yet expired
+func (c *writeCache) isFresh(file, value string) bool {
+	if c == nil || writeVerifyPeriod <= 0 {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, exists := c.values[file]
+	if !exists || entry.value != value {
+		return false
+	}
+	return timeNow().Sub(entry.written) < writeVerifyPeriod
+}
+
+func (c *writeCache) store(file, value string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values[file] = cachedWrite{value: value, written: timeNow()}
+}
+
+// snapshot returns a copy of the cached values
+func (c *writeCache) snapshot() map[string]string {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	values := make(map[string]string, len(c.values))
+	for file, entry := range c.values {
+		values[file] = entry.value
+	}
+	return values
+}
+
+func (c *writeCache) invalidate(file string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.values, file)
+}
+
+// writeCpuProperty writes value to a file within the cpu subdirectory in sysfs,
+// skipping the write if the same value was recently written by the library
+func (cpu *cpuImpl) writeCpuProperty(file string, value string) error {
+	if cpu.writeCache.isFresh(file, value) {
+		log.V(5).Info("skipping unchanged write", "cpu", cpu.id, "file", file, "value", value)
+		return nil
+	}
+	path := filepath.Join(basePath, fmt.Sprint("cpu", cpu.id), file)
+	if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+		// the file content is unknown after a failed write
+		cpu.writeCache.invalidate(file)
+		return writeError(err)
+	}
+	cpu.writeCache.store(file, value)
+	return nil
+}
+
+// forEachCpuParallel calls fn for every cpu in the list using at most maxParallelWrites
+// concurrent goroutines. All cpus are processed, errors are joined
+func forEachCpuParallel(cpus CpuList, fn func(cpu Cpu) error) error {
+	workers := int(maxParallelWrites)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(cpus) {
+		workers = len(cpus)
+	}
+	if workers <= 1 {
+		allErrors := make([]error, 0)
+		for _, cpu := range cpus {
+			allErrors = append(allErrors, fn(cpu))
+		}
+		return errors.Join(allErrors...)
+	}
+
+	allErrors := make([]error, len(cpus))
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := range indexes {
+				allErrors[i] = fn(cpus[i])
+			}
+		}()
+	}
+	for i := range cpus {
+		indexes <- i
+	}
+	close(indexes)
+	waitGroup.Wait()
+	return errors.Join(allErrors...)
+}
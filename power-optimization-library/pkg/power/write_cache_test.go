@@ -0,0 +1,191 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestWriteCache(t *testing.T) {
+	origTimeNow := timeNow
+	defer func() { timeNow = origTimeNow }()
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+
+	// nil cache never elides
+	var nilCache *writeCache
+	nilCache.store("file", "value")
+	assert.False(t, nilCache.isFresh("file", "value"))
+	nilCache.invalidate("file")
+
+	cache := newWriteCache()
+	assert.False(t, cache.isFresh("file", "value"))
+
+	cache.store("file", "value")
+	assert.True(t, cache.isFresh("file", "value"))
+	assert.False(t, cache.isFresh("file", "other"))
+	assert.False(t, cache.isFresh("other", "value"))
+
+	// entry expires after verify period
+	now = now.Add(writeVerifyPeriod)
+	assert.False(t, cache.isFresh("file", "value"))
+
+	cache.store("file", "value")
+	cache.invalidate("file")
+	assert.False(t, cache.isFresh("file", "value"))
+
+	// negative verify period disables elision
+	origVerifyPeriod := writeVerifyPeriod
+	defer func() { writeVerifyPeriod = origVerifyPeriod }()
+	writeVerifyPeriod = -1
+	cache.store("file", "value")
+	assert.False(t, cache.isFresh("file", "value"))
+}
+
+func TestCpuImpl_writeCpuProperty(t *testing.T) {
+	origTimeNow := timeNow
+	defer func() { timeNow = origTimeNow }()
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+
+	defer setupCpuScalingTests(map[string]map[string]string{
+		"cpu0": {"governor": "powersave"},
+	})()
+	governorPath := filepath.Join(basePath, "cpu0", scalingGovFile)
+	cpu := &cpuImpl{id: 0, writeCache: newWriteCache()}
+
+	assert.NoError(t, cpu.writeCpuProperty(scalingGovFile, "performance"))
+	content, _ := os.ReadFile(governorPath)
+	assert.Equal(t, "performance", string(content))
+
+	// value changed behind our back, same write is skipped until verify period elapses
+	assert.NoError(t, os.WriteFile(governorPath, []byte("powersave"), 0644))
+	assert.NoError(t, cpu.writeCpuProperty(scalingGovFile, "performance"))
+	content, _ = os.ReadFile(governorPath)
+	assert.Equal(t, "powersave", string(content))
+
+	now = now.Add(writeVerifyPeriod)
+	assert.NoError(t, cpu.writeCpuProperty(scalingGovFile, "performance"))
+	content, _ = os.ReadFile(governorPath)
+	assert.Equal(t, "performance", string(content))
+
+	// different value is always written
+	assert.NoError(t, cpu.writeCpuProperty(scalingGovFile, "schedutil"))
+	content, _ = os.ReadFile(governorPath)
+	assert.Equal(t, "schedutil", string(content))
+
+	// failed write invalidates cached value
+	assert.Error(t, cpu.writeCpuProperty("cpufreq/missing/file", "1"))
+	assert.False(t, cpu.writeCache.isFresh("cpufreq/missing/file", "1"))
+}
+
+func TestForEachCpuParallel(t *testing.T) {
+	origMaxParallelWrites := maxParallelWrites
+	defer func() { maxParallelWrites = origMaxParallelWrites }()
+
+	cpus := make(CpuList, 32)
+	for i := range cpus {
+		cpus[i] = &cpuImpl{id: uint(i)}
+	}
+
+	for _, workers := range []uint{0, 1, 4, 64} {
+		maxParallelWrites = workers
+		var running, maxRunning atomic.Int32
+		visited := sync.Map{}
+		err := forEachCpuParallel(cpus, func(cpu Cpu) error {
+			current := running.Add(1)
+			for {
+				prev := maxRunning.Load()
+				if current <= prev || maxRunning.CompareAndSwap(prev, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			visited.Store(cpu.GetID(), true)
+			if cpu.GetID()%10 == 0 {
+				return fmt.Errorf("cpu %d failed", cpu.GetID())
+			}
+			return nil
+		})
+		for i := range cpus {
+			_, ok := visited.Load(uint(i))
+			assert.True(t, ok, "workers %d cpu %d", workers, i)
+		}
+		assert.ErrorContains(t, err, "cpu 0 failed")
+		assert.ErrorContains(t, err, "cpu 30 failed")
+		assert.LessOrEqual(t, maxRunning.Load(), int32(max(workers, 1)))
+	}
+
+	assert.NoError(t, forEachCpuParallel(CpuList{}, func(cpu Cpu) error {
+		return fmt.Errorf("should not be called")
+	}))
+}
+
+func benchmarkSetPowerProfile(b *testing.B, numCpus int, workers uint, cached bool) {
+	cpufiles := map[string]map[string]string{}
+	for i := 0; i < numCpus; i++ {
+		cpufiles[fmt.Sprint("cpu", i)] = map[string]string{
+			"max":      "3700000",
+			"min":      "400000",
+			"governor": "powersave",
+			"epp":      "power",
+		}
+	}
+	defer setupCpuScalingTests(cpufiles)()
+	coreTypes = CoreTypeList{&CpuFrequencySet{min: 400000, max: 3700000}}
+
+	origMaxParallelWrites := maxParallelWrites
+	defer func() { maxParallelWrites = origMaxParallelWrites }()
+	maxParallelWrites = workers
+
+	pool := &poolImpl{name: "bench", mutex: &sync.Mutex{}}
+	for i := 0; i < numCpus; i++ {
+		cpu := &cpuImpl{id: uint(i), mutex: &sync.Mutex{}, pool: pool, core: &cpuCore{id: uint(i)}}
+		if cached {
+			cpu.writeCache = newWriteCache()
+		}
+		pool.cpus = append(pool.cpus, cpu)
+	}
+	profile := &profileImpl{name: "bench", pstates: &pstatesImpl{
+		minFreq:  intstr.FromInt(1000000),
+		maxFreq:  intstr.FromInt(3000000),
+		governor: "performance",
+		epp:      "performance",
+	}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pool.SetPowerProfile(profile); err != nil {
+			b.Fatal(err)
+		}
+	}
+	// exclude fixture teardown from the measurement
+	b.StopTimer()
+}
+
+// BenchmarkPoolSetPowerProfile measures re-applying an unchanged profile to a large shared pool
+func BenchmarkPoolSetPowerProfile(b *testing.B) {
+	const numCpus = 384
+	for _, bench := range []struct {
+		name    string
+		workers uint
+		cached  bool
+	}{
+		{"serial-uncached", 1, false},
+		{"serial-cached", 1, true},
+		{"parallel-uncached", 16, false},
+		{"parallel-cached", 16, true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			benchmarkSetPowerProfile(b, numCpus, bench.workers, bench.cached)
+		})
+	}
+}
@@ -280,13 +280,14 @@ class Package(object):
         self.dies = {}
 
 class CpuTopology(object):
-    def __init__(self, pod_uid=None):
+    def __init__(self, pod_uid=None, file_prefix="rte"):
         self.cpus = {}
         self.cores = set()
         self.clusters = set()
         self.dies = set()
         self.packages = {}
         self.pod_uid = pod_uid
+        self.file_prefix = file_prefix
 
     def get_or_create_core(self, core_id, cluster_id, die_id, package_id):
         core = next((c for c in self.cores if c.id == core_id and c.cluster.id == cluster_id and c.die.id == die_id and c.package.id == package_id), None)
@@ -353,7 +354,7 @@ class CpuTopology(object):
     def read_usage(self):
         if not self.pod_uid:
             return
-        socket_path = os.path.join("/var/lib/power-node-agent/pods", self.pod_uid, "dpdk/rte/dpdk_telemetry.v2")
+        socket_path = os.path.join("/var/lib/power-node-agent/pods", self.pod_uid, "dpdk", self.file_prefix, "dpdk_telemetry.v2")
         
         # Read usage
         dpdk_usage = read_usage(socket_path)
@@ -458,7 +459,7 @@ def main(stdscr, args):
     monitor_siblings = not args.no_siblings
     monitor_dpdk_telemetry = args.dpdk_pod_uid != ""
     dpdk_pod_uid = args.dpdk_pod_uid
-    topology = CpuTopology(dpdk_pod_uid)
+    topology = CpuTopology(dpdk_pod_uid, args.dpdk_file_prefix)
     topology.read_topology()
 
     cpu_presenter = CpuPresenter(stdscr, [
@@ -559,6 +560,7 @@ if __name__=="__main__":
     parser.add_argument("--cpu", "-c", type=str, default="0", help="List of cpus to watch")
     parser.add_argument("--no-siblings", "-s", action="store_true", help="Don't watch siblings")
     parser.add_argument("--dpdk-pod-uid", "-d", type=str, default="", help="Watch dpdk usage using dpdk application pod")
+    parser.add_argument("--dpdk-file-prefix", type=str, default="rte", help="--file-prefix of the dpdk application")
     parser.add_argument("--scroll", action="store_true",
                     help="append snapshots instead of refreshing in-place")
     args = parser.parse_args()